@@ -65,7 +65,7 @@ func TestNewRootController(t *testing.T) {
 		// GIVEN
 		stubCommand := &commanddoubles.StubRunFromRootCommand{}
 		dependencies := []entities.Dependency{}
-		ctrl := controllers.NewRunFromRootController(stubCommand, dependencies)
+		ctrl := controllers.NewRunFromRootController(&entities.Settings{}, stubCommand, dependencies)
 
 		// WHEN
 		result := newRootController(ctrl)
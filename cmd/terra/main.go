@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/joho/godotenv"
@@ -17,6 +18,11 @@ import (
 
 var version = "dev"
 
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
 // runUpdateCheck queries the cliforge selfupdate command for a newer version,
 // skipping local dev builds and the self-update / version subcommands to avoid
 // redundant GitHub API calls and noisy warnings.
@@ -31,6 +37,96 @@ func runUpdateCheck(command *cobra.Command) {
 	selfupdate.NewCommand("rios0rios0", "terra", "terra", commands.TerraVersion).CheckForUpdates()
 }
 
+// applyProfileFlag exports --profile=NAME as TERRA_PROFILE before the settings are built,
+// so every command resolves the same profile. The flag wins over a TERRA_PROFILE from the
+// environment or the .env file.
+func applyProfileFlag(arguments []string) {
+	name, found := commands.GetProfileValue(arguments)
+	if !found {
+		return
+	}
+	if name == "" {
+		logger.Fatalf("Error: --profile flag is present but has no value. Provide a profile name, e.g. --profile=prod.")
+	}
+	if err := os.Setenv("TERRA_PROFILE", name); err != nil {
+		logger.Fatalf("Could not set TERRA_PROFILE: %s", err)
+	}
+}
+
+// configureLogFormat selects the log formatter from --log-format=FORMAT, falling back to
+// TERRA_LOG_FORMAT: "text" (the default) prints colored, human-readable lines, and "json"
+// prints one JSON object per event, including the structured fields of every command.
+func configureLogFormat(arguments []string) error {
+	format, found := commands.GetLogFormatValue(arguments)
+	if !found {
+		format = os.Getenv("TERRA_LOG_FORMAT")
+	}
+
+	switch format {
+	case "", logFormatText:
+		//nolint:exhaustruct // Minimal TextFormatter initialization with required fields only
+		logger.SetFormatter(&textFormatter{TextFormatter: logger.TextFormatter{
+			ForceColors:   true,
+			FullTimestamp: true,
+		}})
+	case logFormatJSON:
+		//nolint:exhaustruct // Minimal JSONFormatter initialization with required fields only
+		logger.SetFormatter(&logger.JSONFormatter{})
+	default:
+		return fmt.Errorf("unsupported log format %q (expected %q or %q)", format, logFormatText, logFormatJSON)
+	}
+
+	return nil
+}
+
+// textFormatter is the human-readable formatter. It leaves out the structured fields,
+// whose values are already part of the messages, so the lines stay short.
+type textFormatter struct {
+	logger.TextFormatter
+}
+
+func (it *textFormatter) Format(entry *logger.Entry) ([]byte, error) {
+	plain := *entry
+	plain.Data = logger.Fields{}
+	return it.TextFormatter.Format(&plain)
+}
+
+// loadEncryptedEnvFiles decrypts `.env.enc` and `.env.<profile>.enc` from the working
+// directory in memory and exports their variables for the settings and every child
+// process. Like godotenv.Load, it never overrides a variable that is already set, so the
+// environment and then `.env` win, the same order entities.ReadEnvFiles gives a module.
+func loadEncryptedEnvFiles() {
+	values, err := entities.ReadEnvFiles(".", encryptedEnvProfile())
+	if err != nil {
+		logger.Fatalf("Failed to load encrypted env files: %s", err)
+	}
+
+	for key, value := range values {
+		if _, found := os.LookupEnv(key); found {
+			continue
+		}
+		if err = os.Setenv(key, value); err != nil {
+			logger.Fatalf("Could not set %s: %s", key, err)
+		}
+	}
+}
+
+// encryptedEnvProfile returns the profile whose `.env.<profile>.enc` is loaded: the one
+// selected by TERRA_PROFILE or --profile, else the default `profile:` of the working
+// directory's .terra.yaml.
+func encryptedEnvProfile() string {
+	if name := os.Getenv("TERRA_PROFILE"); name != "" {
+		return name
+	}
+
+	// Any error in the configuration is reported once the settings are loaded
+	settings := &entities.Settings{}
+	if err := settings.LoadProjectConfig("."); err != nil {
+		logger.Debugf("Could not resolve the default profile for the encrypted env files: %s", err)
+	}
+	return settings.TerraProfile
+}
+
 // buildRootCommand creates and configures the root cobra command.
 func buildRootCommand(rootController entities.Controller, enableFlagParsing bool) *cobra.Command {
 	bind := rootController.GetBind()
@@ -47,6 +143,11 @@ func buildRootCommand(rootController entities.Controller, enableFlagParsing bool
 		},
 	}
 
+	// Declared for the help output and for subcommands; the values themselves are read by
+	// applyProfileFlag and configureLogFormat before any command runs
+	cmd.PersistentFlags().String("profile", "", "Select a profile declared in .terra.yaml (--profile=NAME)")
+	cmd.PersistentFlags().String("log-format", logFormatText, "Log format: text or json (--log-format=json)")
+
 	if !enableFlagParsing {
 		cmd.Args = cobra.MinimumNArgs(1)
 		cmd.DisableFlagParsing = true
@@ -73,11 +174,37 @@ func addSubcommands(rootCmd *cobra.Command, appContext entities.AppContext) {
 		if bind.Use == "self-update" {
 			subCmd.Flags().Bool("dry-run", false, "Show what would be updated without performing it")
 			subCmd.Flags().Bool("force", false, "Skip confirmation prompts")
+			subCmd.Flags().Bool("rollback", false, "Restore the terra binary replaced by the last self-update")
+		}
+
+		// Add flags for version command
+		if bind.Use == "version" {
+			subCmd.Flags().String("output", commands.VersionOutputText, "Output format: text or json (--output=json)")
+			subCmd.Flags().Bool("latest", false, "Also look up the latest available versions (requires network access)")
+			subCmd.Flags().Bool("check", false, "Exit with an error when a dependency is missing, outdated or mismatched")
+		}
+
+		// Add flags for install command
+		if bind.Use == "install" {
+			subCmd.Flags().String("rollback", "", "Restore the binary of the tool replaced by its last install (--rollback=terraform)")
+		}
+
+		// Add flags for format command
+		if bind.Use == "format [--check [directory]]" {
+			subCmd.Flags().Bool("check", false, "Only verify the formatting, printing the diff and failing when a file is unformatted")
+		}
+
+		// Add flags for cache command
+		if bind.Use == "cache stats|prune" {
+			subCmd.Flags().String("older-than", "", "Prune entries unused for longer than this age (--older-than=30d)")
+			subCmd.Flags().String("max-size", "", "Prune the least recently used entries until the caches fit (--max-size=20GB)")
 		}
 
 		// Add flags for clear command
-		if bind.Use == "clear" {
+		if bind.Use == "clear [directory]" {
 			subCmd.Flags().Bool("global", false, "Also remove centralized module and provider cache directories")
+			subCmd.Flags().Bool("dry-run", false, "List what would be removed, with sizes, without removing anything")
+			subCmd.Flags().Bool("keep-lock-files", false, "Keep the .terraform.lock.hcl files")
 		}
 
 		rootCmd.AddCommand(subCmd)
@@ -87,19 +214,27 @@ func addSubcommands(rootCmd *cobra.Command, appContext entities.AppContext) {
 func main() {
 	commands.TerraVersion = version //nolint:reassign // Bridge build-time ldflags to domain package
 
-	//nolint:exhaustruct // Minimal TextFormatter initialization with required fields only
-	logger.SetFormatter(&logger.TextFormatter{
-		ForceColors:   true,
-		FullTimestamp: true,
-	})
+	// The log format may come from .env, so any error loading it is logged afterwards
+	dotEnvErr := godotenv.Load()
+	if err := configureLogFormat(os.Args[1:]); err != nil {
+		logger.Fatalf("Error: %s", err)
+	}
 	if os.Getenv("DEBUG") == "true" {
 		logger.SetLevel(logger.DebugLevel)
 	}
+	if dotEnvErr != nil {
+		logger.Debugf("Error loading .env file: %s", dotEnvErr)
+	}
+	applyProfileFlag(os.Args[1:])
+	loadEncryptedEnvFiles()
 
-	err := godotenv.Load()
+	// The trace is flushed on every exit path: returning from main or a logger.Fatal
+	runTrace, err := startTracing(os.Args[1:])
 	if err != nil {
-		logger.Debugf("Error loading .env file: %s", err)
+		logger.Fatalf("Error: %s", err)
 	}
+	defer runTrace.finish(nil)
+	logger.RegisterExitHandler(func() { runTrace.finish(errFatalExit) })
 
 	// Handle --version flag before cobra processing
 	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
@@ -3,12 +3,17 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
 )
 
 // stubController is a minimal Controller implementation for testing.
@@ -105,11 +110,11 @@ func TestAddSubcommands(t *testing.T) {
 		assert.Equal(t, "format", rootCmd.Commands()[1].Use)
 	})
 
-	t.Run("should add global flag to clear command", func(t *testing.T) {
+	t.Run("should add global, dry-run and keep-lock-files flags to clear command", func(t *testing.T) {
 		t.Parallel()
 		// given
 		clearCtrl := &stubController{
-			bind: entities.ControllerBind{Use: "clear", Short: "Clear cache"},
+			bind: entities.ControllerBind{Use: "clear [directory]", Short: "Clear cache"},
 		}
 		appCtx := &stubAppContext{
 			controllers: []entities.Controller{clearCtrl},
@@ -125,6 +130,8 @@ func TestAddSubcommands(t *testing.T) {
 		globalFlag := clearCmd.Flags().Lookup("global")
 		require.NotNil(t, globalFlag, "clear command should have --global flag")
 		assert.Equal(t, "false", globalFlag.DefValue)
+		assert.NotNil(t, clearCmd.Flags().Lookup("dry-run"), "clear command should have --dry-run flag")
+		assert.NotNil(t, clearCmd.Flags().Lookup("keep-lock-files"), "clear command should have --keep-lock-files flag")
 	})
 
 	t.Run("should add dry-run and force flags to self-update command", func(t *testing.T) {
@@ -164,3 +171,149 @@ func TestAddSubcommands(t *testing.T) {
 		assert.Empty(t, rootCmd.Commands())
 	})
 }
+
+func TestApplyProfileFlag(t *testing.T) {
+	t.Run("should export the profile when --profile is given", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_PROFILE", "dev")
+
+		// when
+		applyProfileFlag([]string{"--profile=prod", "plan", "./network"})
+
+		// then
+		assert.Equal(t, "prod", os.Getenv("TERRA_PROFILE"))
+	})
+
+	t.Run("should keep the environment profile when --profile is absent", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_PROFILE", "dev")
+
+		// when
+		applyProfileFlag([]string{"plan", "./network"})
+
+		// then
+		assert.Equal(t, "dev", os.Getenv("TERRA_PROFILE"))
+	})
+}
+
+func TestEncryptedEnvProfile(t *testing.T) {
+	t.Run("should use the default profile of the working directory's .terra.yaml", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_PROFILE", "")
+		require.NoError(t, os.Unsetenv("TERRA_PROFILE"))
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, ".terra.yaml"),
+			[]byte("profile: dev\nprofiles:\n  dev:\n    workspace: dev\n"), 0o600))
+		t.Chdir(directory)
+
+		// when
+		profile := encryptedEnvProfile()
+
+		// then
+		assert.Equal(t, "dev", profile)
+	})
+
+	t.Run("should prefer the profile selected by TERRA_PROFILE", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_PROFILE", "prod")
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, ".terra.yaml"), []byte("profile: dev\n"), 0o600))
+		t.Chdir(directory)
+
+		// when
+		profile := encryptedEnvProfile()
+
+		// then
+		assert.Equal(t, "prod", profile)
+	})
+}
+
+func TestConfigureLogFormat(t *testing.T) {
+	t.Cleanup(func() { logger.SetFormatter(&logger.TextFormatter{}) })
+
+	t.Run("should print one JSON object per event when --log-format=json", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_LOG_FORMAT", "text")
+
+		// when
+		err := configureLogFormat([]string{"--log-format=json", "plan"})
+
+		// then
+		require.NoError(t, err)
+		output, formatErr := logger.StandardLogger().Formatter.Format(
+			logger.WithField("worker", 2).WithField("event", "command_started"),
+		)
+		require.NoError(t, formatErr)
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(output, &event))
+		assert.Equal(t, "command_started", event["event"])
+		assert.InDelta(t, 2, event["worker"], 0)
+	})
+
+	t.Run("should use TERRA_LOG_FORMAT and leave the fields out of text lines", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_LOG_FORMAT", "text")
+
+		// when
+		err := configureLogFormat([]string{"plan"})
+
+		// then
+		require.NoError(t, err)
+		entry := logger.WithField("worker", 2)
+		entry.Message = "Running [terragrunt plan] in ."
+		output, formatErr := logger.StandardLogger().Formatter.Format(entry)
+		require.NoError(t, formatErr)
+		assert.Contains(t, string(output), "Running [terragrunt plan] in .")
+		assert.NotContains(t, string(output), "worker=")
+	})
+
+	t.Run("should return error when the format is unknown", func(t *testing.T) {
+		// given
+		t.Setenv("TERRA_LOG_FORMAT", "xml")
+
+		// when
+		err := configureLogFormat(nil)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"xml"`)
+	})
+}
+
+func TestStartTracing(t *testing.T) {
+	t.Run("should export the root span to the trace file when TERRA_TRACE_FILE is set", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "trace.jsonl")
+		t.Setenv("TERRA_TRACE_FILE", path)
+		t.Setenv("TERRA_TRACE_ENDPOINT", "")
+		previous := otel.GetTracerProvider()
+		t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+		// when
+		session, err := startTracing([]string{"--profile=prod", "plan", "-var=password=s3cr3t"})
+		require.NoError(t, err)
+		session.finish(nil)
+
+		// then
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var span map[string]any
+		require.NoError(t, json.Unmarshal(content, &span))
+		assert.Equal(t, "terra plan", span["Name"])
+		assert.NotContains(t, string(content), "s3cr3t")
+	})
+}
+
+func TestRootSpanName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should name the span after the first non-flag argument", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "terra apply", rootSpanName([]string{"-y", "apply", "./network"}))
+	})
+
+	t.Run("should fall back to terra when there is no command", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "terra", rootSpanName([]string{"--version"}))
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	traceEndpointVariable = "TERRA_TRACE_ENDPOINT"
+	traceFileVariable     = "TERRA_TRACE_FILE"
+	traceShutdownTimeout  = 5 * time.Second
+)
+
+// errFatalExit marks the root span of a run that ended through logger.Fatal.
+var errFatalExit = errors.New("terra exited with a fatal error")
+
+// tracing is the trace of one terra invocation: the root span of the CLI command and the
+// provider exporting it, when one is configured.
+type tracing struct {
+	root     *entities.Span
+	provider *sdktrace.TracerProvider
+	file     *os.File
+	once     sync.Once
+}
+
+// startTracing installs the exporters configured by TERRA_TRACE_ENDPOINT (an OTLP/HTTP
+// collector URL) and TERRA_TRACE_FILE (a file receiving one JSON span per line), then
+// opens the root span of the command. Without either, spans go to otel's no-op provider.
+func startTracing(arguments []string) (*tracing, error) {
+	session := &tracing{}
+
+	var options []sdktrace.TracerProviderOption
+	if endpoint := os.Getenv(traceEndpointVariable); endpoint != "" {
+		exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(endpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter for %s: %w", endpoint, err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	if path := os.Getenv(traceFileVariable); path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to create the trace file exporter: %w", err)
+		}
+		session.file = file
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	if len(options) > 0 {
+		options = append(options, sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", commands.TerraCommandName),
+			attribute.String("service.version", commands.TerraVersion),
+		)))
+		session.provider = sdktrace.NewTracerProvider(options...)
+		otel.SetTracerProvider(session.provider)
+	}
+
+	session.root = entities.StartSpan(0, rootSpanName(arguments),
+		attribute.StringSlice("terra.arguments", entities.RedactSensitiveFlags(arguments)),
+	)
+	return session, nil
+}
+
+// finish ends the root span and flushes the exporters. Only the first call has an effect,
+// so it can be both deferred and registered as a logger exit handler.
+func (it *tracing) finish(err error) {
+	it.once.Do(func() {
+		it.root.End(err)
+		if it.provider == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), traceShutdownTimeout)
+		defer cancel()
+		if shutdownErr := it.provider.Shutdown(ctx); shutdownErr != nil {
+			logger.Warnf("Failed to export the trace: %s", shutdownErr)
+		}
+		if it.file != nil {
+			_ = it.file.Close()
+		}
+	})
+}
+
+// rootSpanName names the root span after the terra command, e.g. "terra plan" or
+// "terra config", leaving the other arguments to the attributes.
+func rootSpanName(arguments []string) string {
+	for _, argument := range arguments {
+		if !strings.HasPrefix(argument, "-") {
+			return commands.TerraCommandName + " " + argument
+		}
+	}
+	return commands.TerraCommandName
+}
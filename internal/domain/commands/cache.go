@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"io"
+	"time"
+)
+
+// CachePruneOptions selects the cache entries `terra cache prune` evicts: those unused for
+// longer than OlderThan, then the least recently used ones until the caches fit in
+// MaxSize. Zero disables either limit.
+type CachePruneOptions struct {
+	OlderThan time.Duration
+	MaxSize   int64
+}
+
+type Cache interface {
+	Stats(output io.Writer) error
+	Prune(options CachePruneOptions) error
+}
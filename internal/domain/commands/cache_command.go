@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+)
+
+type CacheCommand struct {
+	settings *entities.Settings
+}
+
+func NewCacheCommand(settings *entities.Settings) *CacheCommand {
+	return &CacheCommand{settings: settings}
+}
+
+// Stats prints every module checkout and provider version in the centralized caches, with
+// its size and when it was last used, followed by the size of each cache.
+func (it *CacheCommand) Stats(output io.Writer) error {
+	roots, entries, err := it.scan()
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Size > entries[j].Size
+	})
+
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "KIND\tNAME\tSIZE\tLAST USED\tSTATE")
+	totals := make(map[string]int64, len(roots))
+	counts := make(map[string]int, len(roots))
+	for _, entry := range entries {
+		state := ""
+		if entry.Locked {
+			state = "in use"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", entry.Kind, entry.Name,
+			formatSize(entry.Size), entry.LastUsed.Local().Format("2006-01-02 15:04"), state)
+		totals[entry.Kind] += entry.Size
+		counts[entry.Kind]++
+	}
+	if err = writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write cache statistics: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(output)
+	for _, kind := range []string{entities.CacheKindModule, entities.CacheKindProvider} {
+		_, _ = fmt.Fprintf(output, "%s cache %s: %d entries, %s\n",
+			kind, roots[kind], counts[kind], formatSize(totals[kind]))
+	}
+	return nil
+}
+
+// Prune evicts the module checkouts and provider versions unused for longer than
+// options.OlderThan, then the least recently used ones until both caches together fit in
+// options.MaxSize. Entries locked by a running Terragrunt are never removed.
+func (it *CacheCommand) Prune(options CachePruneOptions) error {
+	if options.OlderThan <= 0 && options.MaxSize <= 0 {
+		return errors.New("nothing to prune: pass --older-than, --max-size or both")
+	}
+
+	roots, entries, err := it.scan()
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].LastUsed.Before(entries[j].LastUsed) })
+
+	var total, freed int64
+	for _, entry := range entries {
+		total += entry.Size
+	}
+
+	now, removed := time.Now(), 0
+	for _, entry := range entries {
+		expired := options.OlderThan > 0 && now.Sub(entry.LastUsed) > options.OlderThan
+		overSize := options.MaxSize > 0 && total > options.MaxSize
+		if !expired && !overSize {
+			continue
+		}
+		if entry.Locked {
+			logger.Infof("Skipping %s %s, locked by a running terragrunt", entry.Kind, entry.Name)
+			continue
+		}
+
+		logger.Infof("Removing %s %s (%s, last used %s)", entry.Kind, entry.Name,
+			formatSize(entry.Size), entry.LastUsed.Local().Format("2006-01-02 15:04"))
+		if removeErr := os.RemoveAll(entry.Path); removeErr != nil {
+			logger.Errorf("Failed to remove %s: %s", entry.Path, removeErr)
+			continue
+		}
+		removeEmptyParents(filepath.Dir(entry.Path), roots[entry.Kind])
+		total -= entry.Size
+		freed += entry.Size
+		removed++
+	}
+
+	logger.Infof("Pruned %d cache entries, freeing %s; the caches now use %s",
+		removed, formatSize(freed), formatSize(total))
+	if options.MaxSize > 0 && total > options.MaxSize {
+		logger.Warnf("The caches still exceed %s because the remaining entries are in use",
+			formatSize(options.MaxSize))
+	}
+	return nil
+}
+
+// scan lists the entries of the module and provider caches, returning the root of each.
+func (it *CacheCommand) scan() (map[string]string, []entities.CacheEntry, error) {
+	moduleDir, err := it.settings.GetModuleCacheDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to determine module cache directory: %w", err)
+	}
+	providerDir, err := it.settings.GetProviderCacheDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to determine provider cache directory: %w", err)
+	}
+
+	roots := map[string]string{entities.CacheKindModule: moduleDir, entities.CacheKindProvider: providerDir}
+	var entries []entities.CacheEntry
+	for _, kind := range []string{entities.CacheKindModule, entities.CacheKindProvider} {
+		found, scanErr := entities.ScanCacheEntries(kind, roots[kind])
+		if scanErr != nil {
+			return nil, nil, scanErr
+		}
+		entries = append(entries, found...)
+	}
+	return roots, entries, nil
+}
+
+// removeEmptyParents removes directory and its parents up to root while they are empty,
+// so that evicting the last version of a provider does not leave its directories behind.
+func removeEmptyParents(directory, root string) {
+	for directory != root && strings.HasPrefix(directory, root+string(filepath.Separator)) {
+		if os.Remove(directory) != nil {
+			return
+		}
+		directory = filepath.Dir(directory)
+	}
+}
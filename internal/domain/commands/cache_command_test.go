@@ -0,0 +1,110 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCacheSettings points the module and provider caches to temporary directories.
+func newCacheSettings(t *testing.T) *entities.Settings {
+	t.Helper()
+
+	return &entities.Settings{TerraModuleCacheDir: t.TempDir(), TerraProviderCacheDir: t.TempDir()}
+}
+
+func TestCacheCommand_Stats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list the entries of both caches with their totals", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module checkout and a provider version
+		settings := newCacheSettings(t)
+		repositoryhelpers.HelperCreateCacheEntry(t, settings.TerraModuleCacheDir, "aaa/bbb", 2048, time.Now())
+		repositoryhelpers.HelperCreateCacheEntry(
+			t, settings.TerraProviderCacheDir, "registry.terraform.io/hashicorp/aws/5.31.0/linux_amd64", 100, time.Now(),
+		)
+		var output bytes.Buffer
+
+		// WHEN: Printing the statistics
+		err := commands.NewCacheCommand(settings).Stats(&output)
+
+		// THEN: Should print every entry and the size of each cache
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "aaa/bbb")
+		assert.Contains(t, output.String(), "registry.terraform.io/hashicorp/aws/5.31.0")
+		assert.Contains(t, output.String(), "module cache "+settings.TerraModuleCacheDir+": 1 entries, 2.0 KiB")
+		assert.Contains(t, output.String(), "provider cache "+settings.TerraProviderCacheDir+": 1 entries, 100 B")
+	})
+}
+
+func TestCacheCommand_Prune(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should remove the entries unused for longer than the age", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An old and a recent provider version
+		settings := newCacheSettings(t)
+		old := repositoryhelpers.HelperCreateCacheEntry(
+			t, settings.TerraProviderCacheDir, "registry.terraform.io/hashicorp/aws/5.31.0/linux_amd64", 10,
+			time.Now().Add(-60*24*time.Hour),
+		)
+		recent := repositoryhelpers.HelperCreateCacheEntry(
+			t, settings.TerraProviderCacheDir, "registry.terraform.io/hashicorp/aws/5.40.0/linux_amd64", 10, time.Now(),
+		)
+
+		// WHEN: Pruning what was unused for 30 days
+		err := commands.NewCacheCommand(settings).Prune(commands.CachePruneOptions{OlderThan: 30 * 24 * time.Hour})
+
+		// THEN: Should remove only the old version
+		require.NoError(t, err)
+		assert.NoDirExists(t, filepath.Dir(old))
+		assert.DirExists(t, recent)
+	})
+
+	t.Run("should remove the least recently used entries until the caches fit in the size", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Three module checkouts of 100 bytes each, used at different times
+		settings := newCacheSettings(t)
+		oldest := repositoryhelpers.HelperCreateCacheEntry(
+			t, settings.TerraModuleCacheDir, "aaa/111", 100, time.Now().Add(-3*time.Hour),
+		)
+		middle := repositoryhelpers.HelperCreateCacheEntry(
+			t, settings.TerraModuleCacheDir, "bbb/222", 100, time.Now().Add(-2*time.Hour),
+		)
+		newest := repositoryhelpers.HelperCreateCacheEntry(
+			t, settings.TerraModuleCacheDir, "ccc/333", 100, time.Now().Add(-1*time.Hour),
+		)
+
+		// WHEN: Capping the caches at 250 bytes
+		err := commands.NewCacheCommand(settings).Prune(commands.CachePruneOptions{MaxSize: 250})
+
+		// THEN: Should evict only the least recently used checkout, and its empty parent
+		require.NoError(t, err)
+		assert.NoDirExists(t, filepath.Dir(oldest))
+		assert.DirExists(t, middle)
+		assert.DirExists(t, newest)
+	})
+
+	t.Run("should return error when no limit is given", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A cache command
+		cmd := commands.NewCacheCommand(newCacheSettings(t))
+
+		// WHEN: Pruning without any limit
+		err := cmd.Prune(commands.CachePruneOptions{})
+
+		// THEN: Should refuse to prune
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nothing to prune")
+	})
+}
@@ -31,6 +31,18 @@ func RegisterProviders(container *dig.Container) error {
 	if err := container.Provide(NewVersionCommand); err != nil {
 		return err
 	}
+	if err := container.Provide(NewShowConfigCommand); err != nil {
+		return err
+	}
+	if err := container.Provide(NewUseVersionCommand); err != nil {
+		return err
+	}
+	if err := container.Provide(NewDoctorCommand); err != nil {
+		return err
+	}
+	if err := container.Provide(NewCacheCommand); err != nil {
+		return err
+	}
 
 	// Bind interfaces to implementations
 	if err := container.Provide(func(impl *DeleteCacheCommand) DeleteCache {
@@ -73,6 +85,26 @@ func RegisterProviders(container *dig.Container) error {
 	}); err != nil {
 		return err
 	}
+	if err := container.Provide(func(impl *ShowConfigCommand) ShowConfig {
+		return impl
+	}); err != nil {
+		return err
+	}
+	if err := container.Provide(func(impl *UseVersionCommand) UseVersion {
+		return impl
+	}); err != nil {
+		return err
+	}
+	if err := container.Provide(func(impl *DoctorCommand) Doctor {
+		return impl
+	}); err != nil {
+		return err
+	}
+	if err := container.Provide(func(impl *CacheCommand) Cache {
+		return impl
+	}); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1,5 +1,13 @@
 package commands
 
+// DeleteCacheOptions are the `terra clear` flags.
+type DeleteCacheOptions struct {
+	// Global also removes the centralized module and provider cache directories.
+	Global bool
+	// DryRun lists what would be removed, with sizes, without removing anything.
+	DryRun bool
+}
+
 type DeleteCache interface {
-	Execute(toBeDeleted []string, global bool)
+	Execute(targetPath string, toBeDeleted []string, options DeleteCacheOptions) error
 }
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -16,7 +17,16 @@ func NewDeleteCacheCommand(settings *entities.Settings) *DeleteCacheCommand {
 	return &DeleteCacheCommand{settings: settings}
 }
 
-func (it *DeleteCacheCommand) Execute(toBeDeleted []string, global bool) {
+// Execute removes every file or directory below targetPath named after one of
+// toBeDeleted. It refuses to run outside a project, so that a stray `terra clear` in the
+// home directory cannot wipe the lock files of every checkout.
+func (it *DeleteCacheCommand) Execute(targetPath string, toBeDeleted []string, options DeleteCacheOptions) error {
+	projectRoot, err := entities.FindProjectRoot(targetPath)
+	if err != nil {
+		return fmt.Errorf("refusing to clear outside a project: %w", err)
+	}
+	logger.Debugf("Clearing %s in project %s", targetPath, projectRoot)
+
 	patternSet := make(map[string]struct{}, len(toBeDeleted))
 	for _, p := range toBeDeleted {
 		patternSet[p] = struct{}{}
@@ -24,35 +34,50 @@ func (it *DeleteCacheCommand) Execute(toBeDeleted []string, global bool) {
 
 	logger.Infof("Clearing cache entries matching: %v", toBeDeleted)
 	var foundPaths []string
-	_ = filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	var foundSizes []int64
+	_ = filepath.Walk(targetPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
 		if _, ok := patternSet[filepath.Base(path)]; ok {
 			foundPaths = append(foundPaths, path)
-			if info.IsDir() {
-				return filepath.SkipDir
+			if !info.IsDir() {
+				foundSizes = append(foundSizes, info.Size())
+				return nil
 			}
+			foundSizes = append(foundSizes, directorySize(path))
+			return filepath.SkipDir
 		}
 		return nil
 	})
 
-	for _, found := range foundPaths {
-		logger.Infof("Removing: %s", found)
-		err := os.RemoveAll(found)
-		if err != nil {
-			logger.Errorf("Failed to remove: %s, error: %v", found, err)
+	if options.DryRun {
+		var total int64
+		for index, found := range foundPaths {
+			logger.Infof("Would remove: %s (%s)", found, formatSize(foundSizes[index]))
+			total += foundSizes[index]
+		}
+		logger.Infof("Would remove %d entries, freeing %s", len(foundPaths), formatSize(total))
+	} else {
+		for _, found := range foundPaths {
+			logger.Infof("Removing: %s", found)
+			err = os.RemoveAll(found)
+			if err != nil {
+				logger.Errorf("Failed to remove: %s, error: %v", found, err)
+			}
 		}
 	}
 
-	if global {
-		it.clearGlobalCache()
+	if options.Global {
+		it.clearGlobalCache(options.DryRun)
 	}
+	return nil
 }
 
-// clearGlobalCache removes the centralized module and provider cache directories.
-func (it *DeleteCacheCommand) clearGlobalCache() {
+// clearGlobalCache removes the centralized module and provider cache directories, or only
+// lists them on a dry run.
+func (it *DeleteCacheCommand) clearGlobalCache(dryRun bool) {
 	moduleDir, moduleDirErr := it.settings.GetModuleCacheDir()
 	if moduleDirErr != nil {
 		logger.Errorf("Failed to determine module cache directory: %s", moduleDirErr)
@@ -70,6 +95,10 @@ func (it *DeleteCacheCommand) clearGlobalCache() {
 			logger.Infof("Global cache directory does not exist, skipping: %s", dir)
 			continue
 		}
+		if dryRun {
+			logger.Infof("Would remove global cache directory: %s (%s)", dir, formatSize(directorySize(dir)))
+			continue
+		}
 		logger.Infof("Removing global cache directory: %s", dir)
 		if removeErr := os.RemoveAll(dir); removeErr != nil {
 			logger.Errorf("Failed to remove global cache directory: %s, error: %v", dir, removeErr)
@@ -8,11 +8,21 @@ import (
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/test/domain/entitybuilders"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// helperCreateProjectMarker makes the working directory a project root, which
+// `terra clear` requires.
+func helperCreateProjectMarker(t *testing.T) {
+	t.Helper()
+
+	// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
+	require.NoError(t, os.Mkdir(".git", 0755))
+}
+
 func TestNewDeleteCacheCommand(t *testing.T) {
 	t.Parallel()
 
@@ -35,6 +45,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
 		tempDir := t.TempDir()
 		t.Chdir(tempDir)
+		helperCreateProjectMarker(t)
 
 		// Create terraform cache directories
 		terraformDir := ".terraform"
@@ -71,10 +82,11 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		require.NoError(t, os.MkdirAll(keepDir, 0755))
 
 		// WHEN: Executing the delete command
-		cmd.Execute(
+		require.NoError(t, cmd.Execute(
+			".",
 			[]string{".terraform", ".terragrunt-cache", "terragrunt-cache", ".terraform.lock.hcl"},
-			false,
-		)
+			commands.DeleteCacheOptions{},
+		))
 
 		// THEN: Should delete target directories and files but preserve others
 		_, err := os.Stat(terraformDir)
@@ -107,13 +119,14 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
 		tempDir := t.TempDir()
 		t.Chdir(tempDir)
+		helperCreateProjectMarker(t)
 
 		testDir := ".terraform"
 		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
 		require.NoError(t, os.MkdirAll(testDir, 0755))
 
 		// WHEN: Executing with empty directory list
-		cmd.Execute([]string{}, false)
+		require.NoError(t, cmd.Execute(".", []string{}, commands.DeleteCacheOptions{}))
 
 		// THEN: Should complete without error and not delete any directories
 		_, err := os.Stat(testDir)
@@ -131,10 +144,11 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 			cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
 			tempDir := t.TempDir()
 			t.Chdir(tempDir)
+			helperCreateProjectMarker(t)
 
 			// WHEN: Executing with non-existent directory names
 			// THEN: Should complete without crashing or erroring
-			cmd.Execute([]string{".nonexistent", ".alsononexistent"}, false)
+			require.NoError(t, cmd.Execute(".", []string{".nonexistent", ".alsononexistent"}, commands.DeleteCacheOptions{}))
 		},
 	)
 
@@ -145,6 +159,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 			cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
 			tempDir := t.TempDir()
 			t.Chdir(tempDir)
+			helperCreateProjectMarker(t)
 
 			terraformDir := "project/.terraform"
 			terragruntDir := "project/.terragrunt-cache"
@@ -155,7 +170,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 			require.NoError(t, os.MkdirAll(terragruntDir, 0755))
 
 			// WHEN: Executing command to delete only terraform directories
-			cmd.Execute([]string{".terraform"}, false)
+			require.NoError(t, cmd.Execute(".", []string{".terraform"}, commands.DeleteCacheOptions{}))
 
 			// THEN: Should delete only terraform directories and preserve terragrunt
 			_, err := os.Stat(terraformDir)
@@ -170,6 +185,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		// GIVEN: A delete cache command with custom cache directories that exist
 		tempDir := t.TempDir()
 		t.Chdir(tempDir)
+		helperCreateProjectMarker(t)
 
 		moduleCache := filepath.Join(tempDir, "custom-modules")
 		providerCache := filepath.Join(tempDir, "custom-providers")
@@ -186,7 +202,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		cmd := commands.NewDeleteCacheCommand(settings)
 
 		// WHEN: Executing with global=true
-		cmd.Execute([]string{}, true)
+		require.NoError(t, cmd.Execute(".", []string{}, commands.DeleteCacheOptions{Global: true}))
 
 		// THEN: Should remove both global cache directories
 		_, err := os.Stat(moduleCache)
@@ -200,6 +216,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		// GIVEN: A delete cache command with cache directories that do not exist
 		tempDir := t.TempDir()
 		t.Chdir(tempDir)
+		helperCreateProjectMarker(t)
 
 		settings := entitybuilders.NewSettingsBuilder().
 			WithTerraModuleCacheDir(filepath.Join(tempDir, "nonexistent-modules")).
@@ -209,13 +226,14 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 
 		// WHEN: Executing with global=true
 		// THEN: Should complete without error
-		cmd.Execute([]string{}, true)
+		require.NoError(t, cmd.Execute(".", []string{}, commands.DeleteCacheOptions{Global: true}))
 	})
 
 	t.Run("should respect custom cache paths from settings when global flag is true", func(t *testing.T) {
 		// GIVEN: A delete cache command with custom settings and only module cache exists
 		tempDir := t.TempDir()
 		t.Chdir(tempDir)
+		helperCreateProjectMarker(t)
 
 		moduleCache := filepath.Join(tempDir, "my-modules")
 		providerCache := filepath.Join(tempDir, "my-providers")
@@ -231,7 +249,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		cmd := commands.NewDeleteCacheCommand(settings)
 
 		// WHEN: Executing with global=true
-		cmd.Execute([]string{}, true)
+		require.NoError(t, cmd.Execute(".", []string{}, commands.DeleteCacheOptions{Global: true}))
 
 		// THEN: Should delete existing module cache and skip non-existent provider cache
 		_, err := os.Stat(moduleCache)
@@ -242,6 +260,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		// GIVEN: A delete cache command with custom cache directories that exist
 		tempDir := t.TempDir()
 		t.Chdir(tempDir)
+		helperCreateProjectMarker(t)
 
 		moduleCache := filepath.Join(tempDir, "keep-modules")
 		providerCache := filepath.Join(tempDir, "keep-providers")
@@ -258,7 +277,7 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		cmd := commands.NewDeleteCacheCommand(settings)
 
 		// WHEN: Executing with global=false
-		cmd.Execute([]string{}, false)
+		require.NoError(t, cmd.Execute(".", []string{}, commands.DeleteCacheOptions{}))
 
 		// THEN: Should preserve both global cache directories
 		_, err := os.Stat(moduleCache)
@@ -267,4 +286,55 @@ func TestDeleteCacheCommand_Execute(t *testing.T) {
 		_, err = os.Stat(providerCache)
 		assert.False(t, os.IsNotExist(err), "Provider cache directory should be preserved")
 	})
+
+	t.Run("should only list the matches when dry run requested", func(t *testing.T) {
+		// GIVEN: A project holding a cache directory and a lock file
+		cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
+		t.Chdir(t.TempDir())
+		helperCreateProjectMarker(t)
+		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
+		require.NoError(t, os.MkdirAll("module/.terraform", 0755))
+		require.NoError(t, os.WriteFile("module/.terraform.lock.hcl", []byte("lock"), 0644))
+
+		// WHEN: Executing a dry run
+		err := cmd.Execute(".", []string{".terraform", ".terraform.lock.hcl"}, commands.DeleteCacheOptions{DryRun: true})
+
+		// THEN: Should remove nothing
+		require.NoError(t, err)
+		assert.DirExists(t, "module/.terraform")
+		assert.FileExists(t, "module/.terraform.lock.hcl")
+	})
+
+	t.Run("should only clear below the target path when one is given", func(t *testing.T) {
+		// GIVEN: A project with two modules holding cache directories
+		cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
+		t.Chdir(t.TempDir())
+		helperCreateProjectMarker(t)
+		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
+		require.NoError(t, os.MkdirAll("dev/.terraform", 0755))
+		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
+		require.NoError(t, os.MkdirAll("prod/.terraform", 0755))
+
+		// WHEN: Clearing the dev module only
+		err := cmd.Execute("dev", []string{".terraform"}, commands.DeleteCacheOptions{})
+
+		// THEN: Should leave the prod module untouched
+		require.NoError(t, err)
+		assert.NoDirExists(t, "dev/.terraform")
+		assert.DirExists(t, "prod/.terraform")
+	})
+
+	t.Run("should refuse to clear when the target path is outside a project", func(t *testing.T) {
+		// GIVEN: A directory without any project marker, holding a lock file
+		cmd := commands.NewDeleteCacheCommand(entitybuilders.NewSettingsBuilder().BuildSettings())
+		t.Chdir(t.TempDir())
+		require.NoError(t, os.WriteFile(".terraform.lock.hcl", []byte("lock"), 0644))
+
+		// WHEN: Clearing it
+		err := cmd.Execute(".", []string{".terraform.lock.hcl"}, commands.DeleteCacheOptions{})
+
+		// THEN: Should refuse and keep the lock file
+		require.ErrorIs(t, err, entities.ErrNoProjectRoot)
+		assert.FileExists(t, ".terraform.lock.hcl")
+	})
 }
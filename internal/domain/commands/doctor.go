@@ -0,0 +1,7 @@
+package commands
+
+import "io"
+
+type Doctor interface {
+	Execute(targetPath string, output io.Writer) error
+}
@@ -0,0 +1,405 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+)
+
+// minimumReftableGitVersion is the first Git release able to clone with the reftable
+// backend, the long-term fix for the parallel clone race (docs/parallel-git-clone-race.md).
+const minimumReftableGitVersion = "2.45.0"
+
+type DoctorCommand struct {
+	settings     *entities.Settings
+	dependencies []entities.Dependency
+}
+
+func NewDoctorCommand(settings *entities.Settings, dependencies []entities.Dependency) *DoctorCommand {
+	return &DoctorCommand{settings: settings, dependencies: dependencies}
+}
+
+// Execute runs every check for targetPath and prints a pass/warn/fail report, with a fix
+// under each check that did not pass. It returns an error when any check failed.
+func (it *DoctorCommand) Execute(targetPath string, output io.Writer) error {
+	diagnostics := it.Diagnose(targetPath)
+
+	failed := 0
+	for _, diagnostic := range diagnostics {
+		_, _ = fmt.Fprintf(output, "[%s] %s: %s\n", diagnostic.Status, diagnostic.Check, diagnostic.Detail)
+		if diagnostic.Fix != "" {
+			_, _ = fmt.Fprintf(output, "       fix: %s\n", diagnostic.Fix)
+		}
+		if diagnostic.Status == entities.DiagnosticFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(diagnostics))
+	}
+	return nil
+}
+
+// Diagnose checks, in order, the configuration and `.env` files of targetPath, the
+// dependencies, the installation path, the cache directories, the inherited environment,
+// the cloud CLI session and Git. The configuration of targetPath is resolved into a copy
+// of the settings, which diagnosing leaves as they were.
+func (it *DoctorCommand) Diagnose(targetPath string) []entities.Diagnostic {
+	settings := *it.settings
+	doctor := &DoctorCommand{settings: &settings, dependencies: it.dependencies}
+
+	diagnostics := []entities.Diagnostic{doctor.checkProjectConfig(targetPath)}
+	diagnostics = append(diagnostics, checkEnvFiles(targetPath, doctor.settings.TerraProfile)...)
+	for _, dependency := range doctor.dependencies {
+		diagnostics = append(diagnostics, doctor.checkDependency(dependency, targetPath))
+	}
+	diagnostics = append(diagnostics, checkInstallationPath())
+	diagnostics = append(diagnostics, doctor.checkCacheDirs()...)
+	diagnostics = append(diagnostics, checkInheritedEnvironment()...)
+	diagnostics = append(diagnostics, doctor.checkCloud(), checkGit())
+	return diagnostics
+}
+
+func (it *DoctorCommand) checkProjectConfig(targetPath string) entities.Diagnostic {
+	diagnostic := entities.Diagnostic{Check: "project configuration"}
+	if err := it.settings.LoadProjectConfig(targetPath); err != nil {
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = err.Error()
+		diagnostic.Fix = "correct the setting in " + entities.ProjectConfigFileName + " or the environment, " +
+			"then check the result with `terra config show`"
+		return diagnostic
+	}
+
+	diagnostic.Status = entities.DiagnosticPass
+	diagnostic.Detail = "loaded"
+	return diagnostic
+}
+
+// checkEnvFiles parses `.env` and decrypts `.env.enc` and `.env.<profile>.enc` in
+// directory, the files terra loads before any command runs.
+func checkEnvFiles(directory, profile string) []entities.Diagnostic {
+	path := filepath.Join(directory, ".env")
+	diagnostic := entities.Diagnostic{Check: ".env", Status: entities.DiagnosticPass}
+	values, err := godotenv.Read(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		diagnostic.Detail = "no .env in " + directory
+	case err != nil:
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = fmt.Sprintf("failed to parse %s: %s", path, err)
+		diagnostic.Fix = "use one KEY=VALUE per line and quote values containing spaces or #"
+	default:
+		diagnostic.Detail = fmt.Sprintf("%d variables parsed from %s", len(values), path)
+	}
+	diagnostics := []entities.Diagnostic{diagnostic}
+
+	if _, err = entities.ReadEncryptedEnvFiles(directory, profile); err != nil {
+		diagnostics = append(diagnostics, entities.Diagnostic{
+			Check:  entities.EncryptedEnvFileName,
+			Status: entities.DiagnosticFail,
+			Detail: err.Error(),
+			Fix:    "point TERRA_AGE_KEY_FILE to the age key, or install sops, to decrypt it",
+		})
+	}
+	return diagnostics
+}
+
+// checkDependency reports whether the version of the dependency terra would run satisfies
+// the version pinned or constrained for targetPath.
+func (it *DoctorCommand) checkDependency(dependency entities.Dependency, targetPath string) entities.Diagnostic {
+	diagnostic := entities.Diagnostic{Check: dependency.Name}
+	requirement, err := it.settings.ResolveToolVersion(dependency.CLI, targetPath)
+	if err != nil {
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = err.Error()
+		diagnostic.Fix = "align the pinned version with the constraint, e.g. with `terra use " + dependency.CLI + "@<version>`"
+		return diagnostic
+	}
+
+	binary := dependency.CLI
+	if requirement.Pinned != "" {
+		binary = cachedToolBinary(it.settings, dependency.CLI, requirement)
+		if binary == "" {
+			diagnostic.Status = entities.DiagnosticWarn
+			diagnostic.Detail = fmt.Sprintf("%s %s pinned by %s is not in the tool cache, terra downloads it on the next run",
+				dependency.CLI, requirement.Pinned, requirement.PinnedSource)
+			diagnostic.Fix = "run `terra install` to download it now"
+			return diagnostic
+		}
+	}
+
+	installed := getCurrentVersion(binary)
+	if installed == "" {
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = dependency.CLI + " is not installed"
+		diagnostic.Fix = "run `terra install`"
+		return diagnostic
+	}
+	if err = requirement.Check(installed); err != nil {
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = err.Error()
+		diagnostic.Fix = "run `terra install` to install the required version"
+		return diagnostic
+	}
+
+	diagnostic.Status = entities.DiagnosticPass
+	diagnostic.Detail = installed
+	if binary != dependency.CLI {
+		diagnostic.Detail += " (" + binary + ")"
+	}
+	return diagnostic
+}
+
+// checkInstallationPath reports whether the directory `terra install` writes to is on PATH.
+func checkInstallationPath() entities.Diagnostic {
+	installPath := filepath.Clean(entities.GetOS().GetInstallationPath())
+	diagnostic := entities.Diagnostic{Check: "PATH", Status: entities.DiagnosticPass, Detail: installPath + " is on PATH"}
+	for _, directory := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(directory) == installPath {
+			return diagnostic
+		}
+	}
+
+	diagnostic.Status = entities.DiagnosticWarn
+	diagnostic.Detail = installPath + " is not on PATH, so the binaries installed by terra are not found"
+	diagnostic.Fix = "add `export PATH=\"" + installPath + ":$PATH\"` to your shell profile"
+	return diagnostic
+}
+
+// checkCacheDirs reports whether the module, provider and tool caches are writable, and
+// how much they hold.
+func (it *DoctorCommand) checkCacheDirs() []entities.Diagnostic {
+	caches := []struct {
+		name    string
+		key     string
+		resolve func() (string, error)
+	}{
+		{"module cache", "TERRA_MODULE_CACHE_DIR", it.settings.GetModuleCacheDir},
+		{"provider cache", "TERRA_PROVIDER_CACHE_DIR", it.settings.GetProviderCacheDir},
+		{"tool cache", "TERRA_TOOL_CACHE_DIR", it.settings.GetToolCacheDir},
+	}
+
+	diagnostics := make([]entities.Diagnostic, 0, len(caches))
+	for _, cache := range caches {
+		diagnostic := entities.Diagnostic{Check: cache.name}
+		directory, err := cache.resolve()
+		if err == nil {
+			err = checkWritable(directory)
+		}
+		if err != nil {
+			diagnostic.Status = entities.DiagnosticFail
+			diagnostic.Detail = err.Error()
+			diagnostic.Fix = "fix the permissions of the directory or point " + cache.key + " to a writable one"
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+
+		diagnostic.Status = entities.DiagnosticPass
+		diagnostic.Detail = fmt.Sprintf("%s is writable (%s)", directory, formatSize(directorySize(directory)))
+		if _, err = os.Stat(directory); errors.Is(err, fs.ErrNotExist) {
+			diagnostic.Detail = directory + " does not exist yet and can be created"
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// checkInheritedEnvironment warns about variables exported by the shell that conflict
+// with the caching terra configures.
+func checkInheritedEnvironment() []entities.Diagnostic {
+	var diagnostics []entities.Diagnostic
+	if value, found := os.LookupEnv("TF_PLUGIN_CACHE_DIR"); found {
+		diagnostics = append(diagnostics, entities.Diagnostic{
+			Check:  "TF_PLUGIN_CACHE_DIR",
+			Status: entities.DiagnosticWarn,
+			Detail: "TF_PLUGIN_CACHE_DIR=" + value + " is inherited from the shell; terra unsets it because it " +
+				"causes \"text file busy\" errors in parallel runs, but other tools still use it",
+			Fix: "remove TF_PLUGIN_CACHE_DIR from your shell profile, terra caches providers with the Provider Cache Server",
+		})
+	}
+	if value := os.Getenv("TG_EXPERIMENT"); hasExperiment(value, "cas") {
+		diagnostics = append(diagnostics, entities.Diagnostic{
+			Check:  "TG_EXPERIMENT",
+			Status: entities.DiagnosticWarn,
+			Detail: "TG_EXPERIMENT=" + value + " enables the completed cas experiment, which is on by default",
+			Fix:    "remove cas from TG_EXPERIMENT in your shell profile, or set TERRA_NO_CAS=true to disable it",
+		})
+	}
+
+	if len(diagnostics) == 0 {
+		return []entities.Diagnostic{{
+			Check:  "environment",
+			Status: entities.DiagnosticPass,
+			Detail: "no conflicting TF_PLUGIN_CACHE_DIR or TG_EXPERIMENT",
+		}}
+	}
+	return diagnostics
+}
+
+// checkCloud reports whether the CLI of the configured cloud is installed and logged in.
+func (it *DoctorCommand) checkCloud() entities.Diagnostic {
+	diagnostic := entities.Diagnostic{Check: "cloud CLI", Status: entities.DiagnosticPass}
+	cli := entities.NewCLI(it.settings)
+	if cli == nil {
+		diagnostic.Detail = "no cloud configured"
+		return diagnostic
+	}
+
+	diagnostic.Check = cli.GetName() + " CLI"
+	if _, err := exec.LookPath(cli.GetName()); err != nil {
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = cli.GetName() + " is not installed"
+		diagnostic.Fix = "install the " + cloudCLIName(cli.GetName())
+		return diagnostic
+	}
+
+	command := cli.GetCommandIdentity()
+	if command == nil {
+		diagnostic.Detail = "installed, the account is authenticated from the configured credentials"
+		return diagnostic
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if err := exec.CommandContext(ctx, cli.GetName(), command...).Run(); err != nil {
+		diagnostic.Status = entities.DiagnosticFail
+		diagnostic.Detail = fmt.Sprintf("not logged in (`%s %s` failed: %s)", cli.GetName(), strings.Join(command, " "), err)
+		diagnostic.Fix = it.cloudLoginHint(cli.GetName())
+		return diagnostic
+	}
+
+	diagnostic.Detail = "installed and logged in"
+	return diagnostic
+}
+
+func (it *DoctorCommand) cloudLoginHint(name string) string {
+	switch {
+	case name == "az":
+		return "run `az login`"
+	case it.settings.TerraAwsSSO:
+		return "run `aws sso login --profile " + it.settings.TerraAwsProfile + "`"
+	default:
+		return "run `aws configure` or `aws sso login`"
+	}
+}
+
+// checkGit reports the Git version, warning when it predates the reftable backend.
+func checkGit() entities.Diagnostic {
+	diagnostic := entities.Diagnostic{Check: "git"}
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "git", "--version").Output()
+	if err != nil {
+		diagnostic.Status = entities.DiagnosticWarn
+		diagnostic.Detail = "git is not installed, so Terragrunt cannot fetch git:: module sources"
+		diagnostic.Fix = "install Git " + minimumReftableGitVersion + " or later"
+		return diagnostic
+	}
+
+	version := ""
+	if matches := cliVersionPattern.FindStringSubmatch(string(output)); len(matches) > 1 {
+		version = matches[1]
+	}
+	if version == "" || entities.CompareVersions(version, minimumReftableGitVersion) < 0 {
+		diagnostic.Status = entities.DiagnosticWarn
+		diagnostic.Detail = strings.TrimSpace(string(output)) + " does not support the reftable backend " +
+			"(see docs/parallel-git-clone-race.md)"
+		diagnostic.Fix = "upgrade Git to " + minimumReftableGitVersion + " or later"
+		return diagnostic
+	}
+
+	diagnostic.Status = entities.DiagnosticPass
+	diagnostic.Detail = version + " (supports reftable)"
+	return diagnostic
+}
+
+func cloudCLIName(name string) string {
+	if name == "az" {
+		return "Azure CLI: https://learn.microsoft.com/cli/azure/install-azure-cli"
+	}
+	return "AWS CLI: https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html"
+}
+
+// hasExperiment reports whether the comma-separated TG_EXPERIMENT value lists name.
+func hasExperiment(value, name string) bool {
+	for _, experiment := range strings.Split(value, ",") {
+		if strings.TrimSpace(experiment) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWritable writes a probe file into directory or, while it does not exist yet, into
+// its nearest existing parent, where terra would create it. Nothing is left behind.
+func checkWritable(directory string) error {
+	existing := directory
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", existing)
+			}
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to check %s: %w", existing, err)
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return fmt.Errorf("no parent directory of %s exists", directory)
+		}
+		existing = parent
+	}
+
+	probe, err := os.CreateTemp(existing, ".terra-doctor-*")
+	if err != nil {
+		if existing != directory {
+			return fmt.Errorf("%s cannot be created, %s is not writable: %w", directory, existing, err)
+		}
+		return fmt.Errorf("%s is not writable: %w", directory, err)
+	}
+	_ = probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// directorySize sums the size of the regular files below directory, skipping what cannot
+// be read.
+func directorySize(directory string) int64 {
+	var size int64
+	_ = filepath.WalkDir(directory, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil //nolint:nilerr // unreadable entries are left out of the total
+		}
+		if info, infoErr := entry.Info(); infoErr == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// formatSize renders a byte count with binary units, e.g. "1.5 GiB".
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	divisor, exponent := int64(unit), 0
+	for remaining := size / unit; remaining >= unit; remaining /= unit {
+		divisor *= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(divisor), "KMGTPE"[exponent])
+}
@@ -0,0 +1,189 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/entitybuilders"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHealthyDoctorEnvironment puts terraform, terragrunt and a recent git on a PATH that
+// also holds the installation directory, and points every cache to a temporary directory.
+func newHealthyDoctorEnvironment(t *testing.T) string {
+	t.Helper()
+
+	binDir := t.TempDir()
+	repositoryhelpers.HelperCreateMockBinary(t, binDir, "terraform", `echo "Terraform v1.9.5"`)
+	repositoryhelpers.HelperCreateMockBinary(t, binDir, "terragrunt", `echo "terragrunt version v0.67.0"`)
+	repositoryhelpers.HelperCreateMockBinary(t, binDir, "git", `echo "git version 2.46.0"`)
+	t.Setenv("PATH", binDir)
+	t.Setenv("TERRA_INSTALL_PATH", binDir)
+	t.Setenv("TERRA_MODULE_CACHE_DIR", t.TempDir())
+	t.Setenv("TERRA_PROVIDER_CACHE_DIR", t.TempDir())
+	t.Setenv("TERRA_TOOL_CACHE_DIR", t.TempDir())
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "")
+	t.Setenv("TG_EXPERIMENT", "")
+	require.NoError(t, os.Unsetenv("TF_PLUGIN_CACHE_DIR"))
+	return binDir
+}
+
+func newDoctorDependencies() []entities.Dependency {
+	return []entities.Dependency{
+		entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency(),
+		entitybuilders.NewDependencyBuilder().WithName("Terragrunt").WithCLI("terragrunt").BuildDependency(),
+	}
+}
+
+func TestDoctorCommand_Execute(t *testing.T) {
+	t.Run("should pass every check when the environment is healthy", func(t *testing.T) {
+		// GIVEN: Every dependency installed, the installation path on PATH and writable caches
+		newHealthyDoctorEnvironment(t)
+		cmd := commands.NewDoctorCommand(&entities.Settings{}, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(t.TempDir(), &output)
+
+		// THEN: Should report only passing checks
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "[PASS] Terraform: 1.9.5")
+		assert.Contains(t, output.String(), "[PASS] Terragrunt: 0.67.0")
+		assert.Contains(t, output.String(), "[PASS] git: 2.46.0")
+		assert.Regexp(t, `\[PASS\] module cache: .* is writable \(0 B\)`, output.String())
+		assert.NotContains(t, output.String(), "[WARN]")
+		assert.NotContains(t, output.String(), "[FAIL]")
+	})
+
+	t.Run("should leave the settings and a missing cache directory untouched", func(t *testing.T) {
+		// GIVEN: A target path with its own workspace, and a tool cache that does not exist yet
+		newHealthyDoctorEnvironment(t)
+		toolCache := filepath.Join(t.TempDir(), "bin")
+		t.Setenv("TERRA_TOOL_CACHE_DIR", toolCache)
+		targetPath := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(targetPath, entities.ProjectConfigFileName),
+			[]byte("workspace: dev\n"), 0o600))
+		settings := &entities.Settings{}
+		cmd := commands.NewDoctorCommand(settings, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(targetPath, &output)
+
+		// THEN: Should check the parent of the cache without creating it or loading the settings
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "[PASS] tool cache: "+toolCache+" does not exist yet and can be created")
+		assert.NoDirExists(t, toolCache)
+		assert.Empty(t, settings.TerraTerraformWorkspace)
+	})
+
+	t.Run("should fail with a fix when a dependency is missing or violates its pin", func(t *testing.T) {
+		// GIVEN: Terragrunt missing and terraform older than the constraint of the project
+		binDir := newHealthyDoctorEnvironment(t)
+		require.NoError(t, os.Remove(filepath.Join(binDir, "terragrunt")))
+		t.Setenv("TERRA_TERRAFORM_VERSION", ">= 1.10.0")
+		settings := &entities.Settings{}
+		cmd := commands.NewDoctorCommand(settings, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(t.TempDir(), &output)
+
+		// THEN: Should report both failures with their fixes
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 of")
+		assert.Contains(t, output.String(), "[FAIL] Terraform: terraform 1.9.5 is installed, but TERRA_TERRAFORM_VERSION requires")
+		assert.Contains(t, output.String(), "[FAIL] Terragrunt: terragrunt is not installed\n       fix: run `terra install`")
+	})
+
+	t.Run("should fail when the .env file cannot be parsed", func(t *testing.T) {
+		// GIVEN: A .env file with an unterminated quoted value
+		newHealthyDoctorEnvironment(t)
+		targetPath := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(targetPath, ".env"), []byte("TERRA_CLOUD=\"aws\n"), 0o600))
+		cmd := commands.NewDoctorCommand(&entities.Settings{}, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(targetPath, &output)
+
+		// THEN: Should report the parse error
+		require.Error(t, err)
+		assert.Contains(t, output.String(), "[FAIL] .env: failed to parse")
+	})
+
+	t.Run("should warn when the shell exports conflicting variables", func(t *testing.T) {
+		// GIVEN: TF_PLUGIN_CACHE_DIR and the completed cas experiment inherited from the shell
+		newHealthyDoctorEnvironment(t)
+		t.Setenv("TF_PLUGIN_CACHE_DIR", "/tmp/plugins")
+		t.Setenv("TG_EXPERIMENT", "symlinks,cas")
+		cmd := commands.NewDoctorCommand(&entities.Settings{}, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(t.TempDir(), &output)
+
+		// THEN: Should warn about both without failing
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "[WARN] TF_PLUGIN_CACHE_DIR: TF_PLUGIN_CACHE_DIR=/tmp/plugins")
+		assert.Contains(t, output.String(), "[WARN] TG_EXPERIMENT: TG_EXPERIMENT=symlinks,cas")
+	})
+
+	t.Run("should fail when the configured cloud CLI is not logged in", func(t *testing.T) {
+		// GIVEN: An AWS profile whose caller identity cannot be resolved
+		binDir := newHealthyDoctorEnvironment(t)
+		repositoryhelpers.HelperCreateMockBinary(t, binDir, "aws", "exit 255")
+		t.Setenv("TERRA_CLOUD", "aws")
+		t.Setenv("TERRA_AWS_PROFILE", "dev")
+		cmd := commands.NewDoctorCommand(&entities.Settings{}, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(t.TempDir(), &output)
+
+		// THEN: Should report the missing session with the login command
+		require.Error(t, err)
+		assert.Contains(t, output.String(), "[FAIL] aws CLI: not logged in (`aws sts get-caller-identity --profile dev` failed")
+		assert.Contains(t, output.String(), "fix: run `aws configure` or `aws sso login`")
+	})
+
+	t.Run("should warn when git predates the reftable backend", func(t *testing.T) {
+		// GIVEN: Git 2.39
+		binDir := newHealthyDoctorEnvironment(t)
+		repositoryhelpers.HelperCreateMockBinary(t, binDir, "git", `echo "git version 2.39.2"`)
+		cmd := commands.NewDoctorCommand(&entities.Settings{}, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(t.TempDir(), &output)
+
+		// THEN: Should warn and suggest an upgrade
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "[WARN] git: git version 2.39.2 does not support the reftable backend")
+		assert.Contains(t, output.String(), "fix: upgrade Git to 2.45.0 or later")
+	})
+
+	t.Run("should warn when the installation path is not on PATH", func(t *testing.T) {
+		// GIVEN: terra installing binaries into a directory missing from PATH
+		newHealthyDoctorEnvironment(t)
+		installPath := t.TempDir()
+		t.Setenv("TERRA_INSTALL_PATH", installPath)
+		cmd := commands.NewDoctorCommand(&entities.Settings{}, newDoctorDependencies())
+		var output bytes.Buffer
+
+		// WHEN: Running the doctor
+		err := cmd.Execute(t.TempDir(), &output)
+
+		// THEN: Should warn and suggest exporting it
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "[WARN] PATH: "+installPath+" is not on PATH")
+	})
+}
@@ -5,4 +5,6 @@ import "github.com/rios0rios0/terra/internal/domain/entities"
 //nolint:iface // Different semantic purpose than InstallDependencies
 type FormatFiles interface {
 	Execute(dependencies []entities.Dependency)
+	ExecuteTarget(targetPath string, changedOnly bool, dependencies []entities.Dependency)
+	Check(targetPath string, dependencies []entities.Dependency) error
 }
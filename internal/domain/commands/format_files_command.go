@@ -1,9 +1,15 @@
 package commands
 
 import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
 	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/internal/domain/repositories"
 	logger "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type FormatFilesCommand struct {
@@ -17,9 +23,118 @@ func NewFormatFilesCommand(repository repositories.ShellRepository) *FormatFiles
 func (it *FormatFilesCommand) Execute(dependencies []entities.Dependency) {
 	logger.Info("Formatting the code...")
 	for _, dependency := range dependencies {
-		err := it.repository.ExecuteCommand(dependency.CLI, dependency.FormattingCommand, ".")
+		// Tools declared without a formatting command are not formatters
+		if len(dependency.FormattingCommand) == 0 {
+			continue
+		}
+		it.format(dependency, dependency.FormattingCommand, ".")
+	}
+}
+
+// ExecuteTarget formats the files a run on targetPath reads: the modules at or below
+// targetPath, and the Terragrunt configurations they include from the directories above
+// it. With changedOnly, only the files among them that Git reports as changed are
+// formatted, and nothing is when targetPath is not in a Git repository.
+func (it *FormatFilesCommand) ExecuteTarget(
+	targetPath string,
+	changedOnly bool,
+	dependencies []entities.Dependency,
+) {
+	// Git reports the files below the repository's real path
+	if resolved, err := filepath.EvalSymlinks(targetPath); err == nil {
+		targetPath = resolved
+	}
+	var includes []string
+	for _, include := range entities.FindTerragruntIncludes(targetPath) {
+		if !isWithinDirectory(include, targetPath) {
+			includes = append(includes, include)
+		}
+	}
+
+	if !changedOnly {
+		logger.Infof("Formatting the code in %s...", targetPath)
+		for _, dependency := range dependencies {
+			if len(dependency.FormattingCommand) > 0 {
+				it.format(dependency, dependency.FormattingCommand, targetPath)
+			}
+		}
+		it.formatFiles(includes, dependencies)
+		return
+	}
+
+	changed, err := entities.GitChangedFiles(targetPath)
+	if err != nil {
+		logger.Warnf("Skipping the formatting of the changed files: %s", err)
+		return
+	}
+	var files []string
+	for _, file := range changed {
+		if isWithinDirectory(file, targetPath) || slices.Contains(includes, file) {
+			files = append(files, file)
+		}
+	}
+	logger.Infof("Formatting %d changed files in %s...", len(files), targetPath)
+	it.formatFiles(files, dependencies)
+}
+
+// formatFiles formats each file with the dependencies formatting its extension one file at
+// a time. Dependencies without a file formatting command leave the files untouched.
+func (it *FormatFilesCommand) formatFiles(files []string, dependencies []entities.Dependency) {
+	for _, dependency := range dependencies {
+		if len(dependency.FormattingFileCommand) == 0 {
+			continue
+		}
+		for _, file := range files {
+			if slices.Contains(dependency.FormattingFileExtensions, filepath.Ext(file)) {
+				arguments := append(slices.Clone(dependency.FormattingFileCommand), file)
+				it.format(dependency, arguments, filepath.Dir(file))
+			}
+		}
+	}
+}
+
+// format runs the dependency with the formatting arguments in directory, only warning when
+// it fails since unformatted files never prevent a run.
+func (it *FormatFilesCommand) format(dependency entities.Dependency, arguments []string, directory string) {
+	span := entities.StartSpan(0, entities.SpanFormat, attribute.String("terra.command", dependency.CLI))
+	err := it.repository.ExecuteCommand(dependency.CLI, arguments, directory)
+	span.End(err)
+	if err != nil {
+		logger.Warnf("Failed to format '%s' files: %s", dependency.CLI, err)
+	}
+}
+
+// isWithinDirectory reports whether path is directory or below it.
+func isWithinDirectory(path, directory string) bool {
+	relative, err := filepath.Rel(directory, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// Check verifies the formatting of the files below targetPath without rewriting them. Each
+// dependency prints the diff of its unformatted files, and the check fails when any of
+// them found one (or could not run).
+func (it *FormatFilesCommand) Check(targetPath string, dependencies []entities.Dependency) error {
+	logger.Info("Checking the code formatting...")
+	var unformatted []string
+	for _, dependency := range dependencies {
+		if len(dependency.FormattingCheckCommand) == 0 {
+			if len(dependency.FormattingCommand) > 0 {
+				logger.Warnf("'%s' has no formatting check command, skipping it", dependency.CLI)
+			}
+			continue
+		}
+		span := entities.StartSpan(0, entities.SpanFormat, attribute.String("terra.command", dependency.CLI))
+		err := it.repository.ExecuteCommand(dependency.CLI, dependency.FormattingCheckCommand, targetPath)
+		span.End(err)
 		if err != nil {
-			logger.Warnf("Failed to format '%s' files: %s", dependency.CLI, err)
+			unformatted = append(unformatted, dependency.CLI)
 		}
 	}
+
+	if len(unformatted) > 0 {
+		return fmt.Errorf("the formatting check failed for %s, run `terra format` to fix it",
+			strings.Join(unformatted, ", "))
+	}
+	logger.Info("All files are formatted")
+	return nil
 }
@@ -3,6 +3,9 @@
 package commands_test
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/commands"
@@ -94,10 +97,11 @@ func TestFormatFilesCommand_Execute(t *testing.T) {
 	})
 
 	t.Run(
-		"should execute with empty arguments when dependency has no formatting command",
+		"should skip the dependency when it has no formatting command",
 		func(t *testing.T) {
 			t.Parallel()
-			// GIVEN: A mock repository and dependency with empty formatting command
+			// GIVEN: A mock repository and dependency with empty formatting command, such as a
+			// linter declared under `tools:`
 			mockRepo := &repositorydoubles.StubShellRepository{}
 			dependencies := []entities.Dependency{
 				entitybuilders.NewDependencyBuilder().
@@ -111,10 +115,8 @@ func TestFormatFilesCommand_Execute(t *testing.T) {
 			// WHEN: Executing the format command
 			cmd.Execute(dependencies)
 
-			// THEN: Should execute command with empty arguments
-			assert.Equal(t, 1, mockRepo.ExecuteCallCount)
-			assert.Equal(t, "sometool", mockRepo.LastCommand)
-			assert.Empty(t, mockRepo.LastArguments)
+			// THEN: Should not run the tool
+			assert.Equal(t, 0, mockRepo.ExecuteCallCount)
 		},
 	)
 
@@ -168,3 +170,141 @@ func TestFormatFilesCommand_Execute(t *testing.T) {
 		},
 	)
 }
+
+func TestFormatFilesCommand_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run the check commands in the target path when they pass", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A dependency with a check command and one without any formatting
+		mockRepo := &repositorydoubles.StubShellRepository{}
+		dependencies := []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().
+				WithCLI("terraform").
+				WithFormattingCheckCommand([]string{"fmt", "-check", "-diff", "-recursive"}).
+				BuildDependency(),
+			entitybuilders.NewDependencyBuilder().WithCLI("tflint").WithFormattingCommand(nil).BuildDependency(),
+		}
+		cmd := commands.NewFormatFilesCommand(mockRepo)
+
+		// WHEN: Checking the formatting of a directory
+		err := cmd.Check("/repo/live", dependencies)
+
+		// THEN: Should run only the check command, in that directory
+		require.NoError(t, err)
+		assert.Equal(t, 1, mockRepo.ExecuteCallCount)
+		assert.Equal(t, "terraform", mockRepo.LastCommand)
+		assert.Equal(t, []string{"fmt", "-check", "-diff", "-recursive"}, mockRepo.LastArguments)
+		assert.Equal(t, "/repo/live", mockRepo.LastDirectory)
+	})
+
+	t.Run("should return error naming the dependency when its check fails", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A check command exiting non-zero, as on unformatted files
+		mockRepo := &repositorydoubles.StubShellRepository{ShouldReturnError: true}
+		dependencies := []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().
+				WithCLI("terragrunt").
+				WithFormattingCheckCommand([]string{"hcl", "format", "--check", "--diff"}).
+				BuildDependency(),
+		}
+		cmd := commands.NewFormatFilesCommand(mockRepo)
+
+		// WHEN: Checking the formatting
+		err := cmd.Check(".", dependencies)
+
+		// THEN: Should fail and name the dependency
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the formatting check failed for terragrunt")
+	})
+}
+
+func TestFormatFilesCommand_ExecuteTarget(t *testing.T) {
+	t.Parallel()
+
+	helperWriteFile := func(t *testing.T, path string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("locals {}\n"), 0o644))
+	}
+	terragruntDep := entitybuilders.NewDependencyBuilder().
+		WithCLI("terragrunt").
+		WithFormattingCommand([]string{"hcl", "format"}).
+		WithFormattingFileCommand([]string{"hcl", "format", "--file"}, ".hcl").
+		BuildDependency()
+
+	t.Run("should format the target path and the files it includes from above", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module including root.hcl, next to an unrelated module
+		root := t.TempDir()
+		helperWriteFile(t, filepath.Join(root, "root.hcl"))
+		helperWriteFile(t, filepath.Join(root, "dev", "app", "terragrunt.hcl"))
+		module := filepath.Join(root, "prod", "app")
+		require.NoError(t, os.MkdirAll(module, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(module, "terragrunt.hcl"),
+			[]byte(`include "root" { path = find_in_parent_folders("root.hcl") }`), 0o644))
+		mockRepo := &repositorydoubles.StubShellRepositoryWithRecording{}
+		cmd := commands.NewFormatFilesCommand(mockRepo)
+
+		// WHEN: Formatting the files of a run on the module
+		cmd.ExecuteTarget(module, false, []entities.Dependency{terragruntDep})
+
+		// THEN: Should format the module recursively and root.hcl alone
+		require.Len(t, mockRepo.CallRecords, 2)
+		assert.Equal(t, []string{"hcl", "format"}, mockRepo.CallRecords[0].Arguments)
+		assert.Equal(t, module, mockRepo.CallRecords[0].Directory)
+		assert.Equal(t, []string{"hcl", "format", "--file", filepath.Join(root, "root.hcl")},
+			mockRepo.CallRecords[1].Arguments)
+		assert.Equal(t, root, mockRepo.CallRecords[1].Directory)
+	})
+
+	t.Run("should format only the changed files of the target with the formatter of their extension",
+		func(t *testing.T) {
+			t.Parallel()
+			// GIVEN: A repository where a file of the module and a file of another one changed
+			root, err := filepath.EvalSymlinks(t.TempDir())
+			require.NoError(t, err)
+			module := filepath.Join(root, "prod")
+			helperWriteFile(t, filepath.Join(module, "terragrunt.hcl"))
+			helperWriteFile(t, filepath.Join(module, "main.tf"))
+			for _, arguments := range [][]string{
+				{"init", "-q"}, {"add", "."},
+				{"-c", "user.name=terra", "-c", "user.email=terra@example.com", "commit", "-q", "-m", "initial"},
+			} {
+				output, gitErr := exec.Command("git", append([]string{"-C", root}, arguments...)...).CombinedOutput()
+				require.NoError(t, gitErr, string(output))
+			}
+			helperWriteFile(t, filepath.Join(module, "variables.tf"))
+			helperWriteFile(t, filepath.Join(root, "dev", "main.tf"))
+			terraformDep := entitybuilders.NewDependencyBuilder().
+				WithCLI("terraform").
+				WithFormattingFileCommand([]string{"fmt"}, ".tf", ".tfvars").
+				BuildDependency()
+			mockRepo := &repositorydoubles.StubShellRepositoryWithRecording{}
+			cmd := commands.NewFormatFilesCommand(mockRepo)
+
+			// WHEN: Formatting the changed files of a run on the module
+			cmd.ExecuteTarget(module, true, []entities.Dependency{terraformDep, terragruntDep})
+
+			// THEN: Should format the new file of the module only, with terraform
+			require.Len(t, mockRepo.CallRecords, 1)
+			assert.Equal(t, "terraform", mockRepo.CallRecords[0].Command)
+			assert.Equal(t, []string{"fmt", filepath.Join(module, "variables.tf")}, mockRepo.CallRecords[0].Arguments)
+		},
+	)
+
+	t.Run("should format nothing when the changed files are asked outside a Git repository", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module that is not in a Git repository
+		module := t.TempDir()
+		helperWriteFile(t, filepath.Join(module, "terragrunt.hcl"))
+		mockRepo := &repositorydoubles.StubShellRepositoryWithRecording{}
+		cmd := commands.NewFormatFilesCommand(mockRepo)
+
+		// WHEN: Formatting its changed files
+		cmd.ExecuteTarget(module, true, []entities.Dependency{terragruntDep})
+
+		// THEN: Should not fall back to formatting everything
+		assert.Empty(t, mockRepo.CallRecords)
+	})
+}
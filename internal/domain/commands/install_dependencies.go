@@ -5,4 +5,7 @@ import "github.com/rios0rios0/terra/internal/domain/entities"
 //nolint:iface // Different semantic purpose than FormatFiles
 type InstallDependencies interface {
 	Execute(dependencies []entities.Dependency)
+	InstallVersion(dependency entities.Dependency, version string) string
+	LatestVersion(dependency entities.Dependency) (string, error)
+	Rollback(tool string, dependencies []entities.Dependency) error
 }
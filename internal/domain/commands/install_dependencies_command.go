@@ -27,19 +27,38 @@ const contextTimeout = 10 * time.Second
 // blocking on stdin would hang the job forever.
 const assumeYesEnvVar = "TERRA_ASSUME_YES"
 
-type InstallDependenciesCommand struct{}
+type InstallDependenciesCommand struct {
+	settings *entities.Settings
+}
 
-func NewInstallDependenciesCommand() *InstallDependenciesCommand {
-	return &InstallDependenciesCommand{}
+func NewInstallDependenciesCommand(settings *entities.Settings) *InstallDependenciesCommand {
+	return &InstallDependenciesCommand{settings: settings}
 }
 
 func (it *InstallDependenciesCommand) Execute(dependencies []entities.Dependency) {
 	for _, dependency := range dependencies {
-		latestVersion := fetchLatestVersion(dependency.VersionURL, dependency.RegexVersion)
+		requirement, err := it.settings.ResolveToolVersion(dependency.CLI, ".")
+		if err != nil {
+			logger.Fatalf("Failed to resolve the %s version: %s", dependency.Name, err)
+		}
+
+		// A pinned version is installed as is into the tool cache: no latest lookup, no prompt
+		if requirement.Pinned != "" {
+			binaryPath := it.InstallVersion(dependency, requirement.Pinned)
+			logger.Infof("%s %s pinned by %s is available at %s",
+				dependency.Name, requirement.Pinned, requirement.PinnedSource, binaryPath)
+			continue
+		}
+
+		latestVersion := it.resolveLatestVersion(dependency)
+		if !requirement.Allows(latestVersion) {
+			keepConstrained(dependency, requirement, latestVersion)
+			continue
+		}
 
 		if !isDependencyCLIAvailable(dependency.CLI) {
 			logger.Warnf("%s is not installed, installing now...", dependency.Name)
-			install(dependency.GetBinaryURL(latestVersion), dependency.CLI)
+			it.install(dependency, latestVersion)
 		} else {
 			// Dependency is installed, check if it's the latest version
 			currentVersion := getCurrentVersion(dependency.CLI)
@@ -54,7 +73,7 @@ func (it *InstallDependenciesCommand) Execute(dependencies []entities.Dependency
 				// Current version is older than latest
 				if promptForUpdate(dependency.Name, currentVersion, latestVersion) {
 					logger.Infof("Updating %s from %s to %s...", dependency.Name, currentVersion, latestVersion)
-					install(dependency.GetBinaryURL(latestVersion), dependency.CLI)
+					it.install(dependency, latestVersion)
 				} else {
 					logger.Infof("Skipping update for %s", dependency.Name)
 				}
@@ -72,35 +91,105 @@ func (it *InstallDependenciesCommand) Execute(dependencies []entities.Dependency
 	}
 }
 
+// InstallVersion installs the given version of the dependency into the tool cache, next
+// to its other versions and without touching the default binary on the PATH, unless it is
+// already there. It returns the path of the binary.
+func (it *InstallDependenciesCommand) InstallVersion(dependency entities.Dependency, version string) string {
+	binaryPath, err := it.settings.GetToolBinaryPath(dependency.CLI, version)
+	if err != nil {
+		logger.Fatalf("Failed to resolve the tool cache for %s: %s", dependency.Name, err)
+	}
+	if _, err = os.Stat(binaryPath); err == nil {
+		logger.Debugf("%s %s is already installed at %s", dependency.Name, version, binaryPath)
+		return binaryPath
+	}
+
+	logger.Infof("Installing %s %s into %s...", dependency.Name, version, filepath.Dir(binaryPath))
+	it.installInto(dependency, version, filepath.Dir(binaryPath))
+	return binaryPath
+}
+
+// resolveLatestVersion returns the latest version of the dependency, exiting when it
+// cannot be determined.
+func (it *InstallDependenciesCommand) resolveLatestVersion(dependency entities.Dependency) string {
+	version, err := it.LatestVersion(dependency)
+	if err != nil {
+		logger.Fatalf("%s", err)
+	}
+	return version
+}
+
+// LatestVersion returns the latest version of the dependency, read from the index of the
+// offline directory or the mirror when one is configured.
+func (it *InstallDependenciesCommand) LatestVersion(dependency entities.Dependency) (string, error) {
+	indexLocation := it.settings.GetReleaseIndexLocation()
+	if indexLocation == "" {
+		return fetchLatestVersion(dependency.VersionURL, dependency.RegexVersion)
+	}
+
+	content, err := it.fetchReleaseFile(indexLocation)
+	if err != nil {
+		return "", fmt.Errorf("error fetching version info: %w", err)
+	}
+	version, err := entities.ParseReleaseIndex(content, dependency.CLI)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", indexLocation, err)
+	}
+	return version, nil
+}
+
+// keepConstrained handles a latest release the repository's version constraints exclude:
+// an installed version satisfying them is kept, otherwise the user has to pin one.
+func keepConstrained(dependency entities.Dependency, requirement *entities.ToolVersion, latestVersion string) {
+	if isDependencyCLIAvailable(dependency.CLI) {
+		currentVersion := getCurrentVersion(dependency.CLI)
+		if currentVersion != "" && requirement.Check(currentVersion) == nil {
+			logger.Infof("Keeping %s %s: the latest version %s does not satisfy the repository's version constraints",
+				dependency.Name, currentVersion, latestVersion)
+			return
+		}
+	}
+
+	for _, constraint := range requirement.Constraints {
+		if !constraint.Constraint.Check(latestVersion) {
+			logger.Fatalf(
+				"The latest %s version %s does not satisfy %q required by %s. "+
+					"Pin a matching version in .%s-version or TERRA_%s_VERSION.",
+				dependency.Name, latestVersion, constraint.Constraint, constraint.Source,
+				dependency.CLI, strings.ToUpper(dependency.CLI),
+			)
+		}
+	}
+}
+
 // fetch the latest version of software from a URL.
-func fetchLatestVersion(url, regexPattern string) string {
+func fetchLatestVersion(url, regexPattern string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		logger.Fatalf("Error creating request: %s", err)
+		return "", fmt.Errorf("error creating request: %w", err)
 	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logger.Fatalf("Error fetching version info: %s", err)
+		return "", fmt.Errorf("error fetching version info: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		logger.Fatalf("Error reading response body: %s", err)
+		return "", fmt.Errorf("error reading response body: %w", err)
 	}
 
 	re := regexp.MustCompile(regexPattern)
 	matches := re.FindStringSubmatch(string(body))
 	if len(matches) > 1 {
-		return matches[1]
+		return matches[1], nil
 	}
 
-	logger.Fatalf("No version match found, check the regex pattern: %s", regexPattern)
-	return ""
+	return "", fmt.Errorf("no version match found, check the regex pattern: %s", regexPattern)
 }
 
 // checking if a dependency is available.
@@ -116,16 +205,9 @@ func getCurrentVersion(name string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	var cmd *exec.Cmd
-	switch name {
-	case "terraform":
-		cmd = exec.CommandContext(ctx, name, "--version")
-	case "terragrunt":
-		cmd = exec.CommandContext(ctx, name, "--version")
-	default:
-		return ""
-	}
-
+	// Every built-in dependency, and the tools declared in the project configuration,
+	// print their version with --version
+	cmd := exec.CommandContext(ctx, name, "--version")
 	output, err := cmd.Output()
 	if err != nil {
 		logger.Debugf("Failed to get %s version: %s", name, err)
@@ -135,15 +217,31 @@ func getCurrentVersion(name string) string {
 	version := strings.TrimSpace(string(output))
 
 	// Extract version number from output
-	re := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
-	matches := re.FindStringSubmatch(version)
-	if len(matches) > 1 {
+	if matches := cliVersionPattern.FindStringSubmatch(version); len(matches) > 1 {
 		return matches[1]
 	}
 
 	return ""
 }
 
+// cachedToolBinary returns the tool cache binary of the version pinned for the dependency,
+// which every run switches to, or "" when nothing is pinned or that version is not
+// installed yet.
+func cachedToolBinary(settings *entities.Settings, cli string, requirement *entities.ToolVersion) string {
+	if requirement.Pinned == "" {
+		return ""
+	}
+
+	binaryPath, err := settings.GetToolBinaryPath(cli, requirement.Pinned)
+	if err != nil {
+		return ""
+	}
+	if info, statErr := os.Stat(binaryPath); statErr != nil || info.IsDir() {
+		return ""
+	}
+	return binaryPath
+}
+
 // isTruthy reports whether an environment-variable value means affirmative.
 func isTruthy(value string) bool {
 	normalized := strings.ToLower(strings.TrimSpace(value))
@@ -249,7 +347,7 @@ func findBinaryInArchive(extractDir, binaryName string) (string, error) {
 }
 
 // setupInstallationEnvironment prepares the temporary file and installation directory.
-func setupInstallationEnvironment(name string, currentOS entities.OS) (string, string) {
+func setupInstallationEnvironment(name, installDir string, currentOS entities.OS) (string, string) {
 	// Create a unique temporary file to avoid permission conflicts
 	tempFile, err := os.CreateTemp(currentOS.GetTempDir(), name+"_*")
 	if err != nil {
@@ -260,10 +358,9 @@ func setupInstallationEnvironment(name string, currentOS entities.OS) (string, s
 		logger.Warnf("Failed to close temporary file %s: %s", tempFilePath, closeErr)
 	} // Close immediately since we'll overwrite it during download
 
-	destPath := path.Join(currentOS.GetInstallationPath(), name)
+	destPath := path.Join(installDir, name)
 
 	// Ensure installation directory exists
-	installDir := currentOS.GetInstallationPath()
 	// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
 	if mkdirErr := os.MkdirAll(installDir, 0750); mkdirErr != nil {
 		logger.Fatalf("Failed to create installation directory %s: %s", installDir, mkdirErr)
@@ -272,29 +369,55 @@ func setupInstallationEnvironment(name string, currentOS entities.OS) (string, s
 	return tempFilePath, destPath
 }
 
-// downloadDependency downloads the dependency to the specified temporary file.
-func downloadDependency(url, name, tempFilePath string, currentOS entities.OS) {
-	logger.Infof("Downloading %s from %s...", name, url)
-	if downloadErr := currentOS.Download(url, tempFilePath); downloadErr != nil {
+// downloadDependency downloads the dependency to the specified temporary file, or copies
+// it from the offline directory.
+func (it *InstallDependenciesCommand) downloadDependency(location, name, tempFilePath string, currentOS entities.OS) {
+	if it.settings.IsOffline() {
+		logger.Infof("Copying %s from %s...", name, location)
+		if copyErr := copyFile(location, tempFilePath); copyErr != nil {
+			logger.Fatalf("Failed to copy %s: %s", name, copyErr)
+		}
+		return
+	}
+
+	logger.Infof("Downloading %s from %s...", name, location)
+	if downloadErr := currentOS.Download(location, tempFilePath); downloadErr != nil {
 		logger.Fatalf("Failed to download %s: %s", name, downloadErr)
 	}
 }
 
-// detectFileType determines if the downloaded file is a zip archive.
-func detectFileType(tempFilePath, name string) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
-	defer cancel()
-	fileTypeCmd := exec.CommandContext(ctx, "file", tempFilePath)
-	fileTypeOutput, err := fileTypeCmd.Output()
+// copyFile copies the file at source over destination.
+func copyFile(source, destination string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	output, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(output, input); err != nil {
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// detectFileType returns the archive format of the downloaded file from its magic bytes,
+// or "" when it is a bare binary.
+func detectFileType(tempFilePath, name string) string {
+	format, err := entities.DetectArchiveFormat(tempFilePath)
 	if err != nil {
 		logger.Fatalf("Failed to determine file type of %s: %s", name, err)
 	}
-	return strings.Contains(string(fileTypeOutput), "Zip archive data")
+	return format
 }
 
-// processArchive extracts the zip archive and moves the binary to destination.
-func processArchive(tempFilePath, destPath, name string, currentOS entities.OS) {
-	logger.Infof("%s is a zip file, extracting...", name)
+// processArchive extracts the zip or tar.gz archive and moves the binary to destination.
+func processArchive(tempFilePath, destPath, name, format string, currentOS entities.OS) {
+	logger.Infof("%s is a %s archive, extracting...", name, format)
 
 	// Create a unique temporary directory for extraction
 	extractDir, extractErr := os.MkdirTemp(currentOS.GetTempDir(), name+"_extract_*")
@@ -316,6 +439,9 @@ func processArchive(tempFilePath, destPath, name string, currentOS entities.OS)
 	if err = currentOS.Move(binaryPath, destPath); err != nil {
 		logger.Fatalf("Failed to move %s to %s: %s", name, destPath, err)
 	}
+	if err = currentOS.MakeExecutable(destPath); err != nil {
+		logger.Fatalf("Failed to make %s executable: %s", name, err)
+	}
 
 	// Clean up temporary files
 	cleanupArchiveFiles(tempFilePath, extractDir, name, currentOS)
@@ -342,25 +468,180 @@ func installBinary(tempFilePath, destPath, name string, currentOS entities.OS) {
 	}
 }
 
-// installing dependencies doesn't matter the operating system.
-func install(url, name string) {
+// installing dependencies doesn't matter the operating system. The binary being replaced
+// is kept next to the new one, so that `terra install --rollback` can restore it.
+func (it *InstallDependenciesCommand) install(dependency entities.Dependency, version string) {
+	installDir := entities.GetOS().GetInstallationPath()
+	destPath := path.Join(installDir, dependency.CLI)
+
+	previousVersion := ""
+	if _, err := os.Stat(destPath); err == nil {
+		previousVersion = getCurrentVersion(destPath)
+	}
+
+	// Install into a staging directory next to the binary, so that a failed download or
+	// verification leaves the installed binary in place
+	stagingDir := path.Join(installDir, "."+dependency.CLI+".install")
+	if err := os.RemoveAll(stagingDir); err != nil {
+		logger.Fatalf("Failed to install %s: %s", dependency.Name, err)
+	}
+	defer os.RemoveAll(stagingDir)
+	it.installInto(dependency, version, stagingDir)
+
+	// Reinstalling the same version must not replace the binary kept by the install before
+	keepPrevious := previousVersion == "" ||
+		strings.TrimPrefix(previousVersion, "v") != strings.TrimPrefix(version, "v")
+	if err := entities.ReplaceBinary(path.Join(stagingDir, dependency.CLI), destPath, keepPrevious); err != nil {
+		logger.Fatalf("Failed to install %s: %s", dependency.Name, err)
+	}
+	recordInstall(entities.InstallRecord{
+		Action:   entities.InstallActionInstall,
+		Tool:     dependency.CLI,
+		Version:  version,
+		Previous: previousVersion,
+		Path:     destPath,
+	})
+}
+
+// Rollback restores the binary of the tool replaced by its last install, keeping the
+// current one in its place so that rolling back again undoes the rollback.
+func (it *InstallDependenciesCommand) Rollback(tool string, dependencies []entities.Dependency) error {
+	if findDependencyIndex(dependencies, tool) < 0 {
+		return fmt.Errorf("unknown tool %q (expected one of: %s)", tool, strings.Join(dependencyCLIs(dependencies), ", "))
+	}
+
+	destPath := path.Join(entities.GetOS().GetInstallationPath(), tool)
+	currentVersion := getCurrentVersion(destPath)
+	if err := entities.SwapPreviousBinary(destPath); err != nil {
+		return fmt.Errorf("failed to roll back %s: %w", tool, err)
+	}
+
+	restoredVersion := getCurrentVersion(destPath)
+	logger.Infof("Rolled %s back to %s at %s", tool, displayVersion(restoredVersion), destPath)
+	recordInstall(entities.InstallRecord{
+		Action:   entities.InstallActionRollback,
+		Tool:     tool,
+		Version:  restoredVersion,
+		Previous: currentVersion,
+		Path:     destPath,
+	})
+	return nil
+}
+
+// recordInstall appends the record to the install history. The install itself already
+// succeeded, so a history that cannot be written is only worth a warning.
+func recordInstall(record entities.InstallRecord) {
+	if err := entities.AppendInstallRecord(record); err != nil {
+		logger.Warnf("Failed to record the %s of %s in the install history: %s", record.Action, record.Tool, err)
+	}
+}
+
+// displayVersion returns the version, or a placeholder when it could not be determined.
+func displayVersion(version string) string {
+	if version == "" {
+		return "an unknown version"
+	}
+	return version
+}
+
+// installInto installs the dependency into installDir instead of the default installation path.
+func (it *InstallDependenciesCommand) installInto(dependency entities.Dependency, version, installDir string) {
 	currentOS := entities.GetOS()
+	url, name := dependency.GetBinaryURL(version), dependency.CLI
 
 	// Setup environment
-	tempFilePath, destPath := setupInstallationEnvironment(name, currentOS)
+	tempFilePath, destPath := setupInstallationEnvironment(name, installDir, currentOS)
 	defer os.Remove(tempFilePath) // Ensure cleanup of the temporary file
 
-	// Download the dependency
-	downloadDependency(url, name, tempFilePath, currentOS)
+	// Download the dependency, from the mirror or the offline directory when configured
+	it.downloadDependency(it.settings.GetReleaseAssetLocation(name, version, url), name, tempFilePath, currentOS)
+
+	// Never install a download that does not match its published checksum
+	if err := it.verifyDownload(dependency, version, url, tempFilePath); err != nil {
+		logger.Fatalf("Refusing to install %s %s: %s", dependency.Name, version, err)
+	}
 
 	// Process based on file type
-	if detectFileType(tempFilePath, name) {
-		processArchive(tempFilePath, destPath, name, currentOS)
+	if format := detectFileType(tempFilePath, name); format != "" {
+		processArchive(tempFilePath, destPath, name, format, currentOS)
 	} else {
 		installBinary(tempFilePath, destPath, name, currentOS)
 	}
 }
 
+// verifyDownload checks the downloaded file against the release's SHA256SUMS, after
+// checking the signature of the SHA256SUMS itself when the dependency publishes one. Both
+// are fetched from the same mirror or offline directory as the download.
+func (it *InstallDependenciesCommand) verifyDownload(
+	dependency entities.Dependency,
+	version, url, tempFilePath string,
+) error {
+	if dependency.ChecksumsURL == "" {
+		logger.Warnf("%s publishes no checksums, skipping the integrity verification", dependency.Name)
+		return nil
+	}
+
+	checksumsURL := it.settings.GetReleaseAssetLocation(dependency.CLI, version, dependency.GetChecksumsURL(version))
+	checksums, err := it.fetchReleaseFile(checksumsURL)
+	if err != nil {
+		return fmt.Errorf("failed to download the checksums: %w", err)
+	}
+
+	if dependency.SignatureURL != "" {
+		signatureURL := it.settings.GetReleaseAssetLocation(dependency.CLI, version, dependency.GetSignatureURL(version))
+		signature, signatureErr := it.fetchReleaseFile(signatureURL)
+		if signatureErr != nil {
+			return fmt.Errorf("failed to download the checksums signature: %w", signatureErr)
+		}
+		if signatureErr = entities.VerifySignature(dependency.SigningKey, checksums, signature); signatureErr != nil {
+			return fmt.Errorf("%s: %w", checksumsURL, signatureErr)
+		}
+	}
+
+	digests, err := entities.ParseChecksums(checksums)
+	if err != nil {
+		return fmt.Errorf("%s: %w", checksumsURL, err)
+	}
+	fileName := path.Base(url)
+	expected, found := digests[fileName]
+	if !found {
+		return fmt.Errorf("%s lists no checksum for %s", checksumsURL, fileName)
+	}
+	if err = entities.VerifyFileChecksum(tempFilePath, expected); err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	logger.Infof("Verified the SHA-256 checksum of %s", fileName)
+	return nil
+}
+
+// fetchReleaseFile downloads a small release file, such as SHA256SUMS, into memory, or
+// reads it from the offline directory.
+func (it *InstallDependenciesCommand) fetchReleaseFile(url string) ([]byte, error) {
+	if it.settings.IsOffline() {
+		return os.ReadFile(url)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: HTTP %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
 // CompareVersionsPublic is a public wrapper for testing.
 func CompareVersionsPublic(v1, v2 string) int {
 	return selfupdate.CompareVersions(v1, v2)
@@ -370,3 +651,16 @@ func CompareVersionsPublic(v1, v2 string) int {
 func FindBinaryInArchivePublic(extractDir, binaryName string) (string, error) {
 	return findBinaryInArchive(extractDir, binaryName)
 }
+
+// VerifyDownloadPublic is a public wrapper for testing the private verifyDownload method.
+func (it *InstallDependenciesCommand) VerifyDownloadPublic(
+	dependency entities.Dependency,
+	version, url, tempFilePath string,
+) error {
+	return it.verifyDownload(dependency, version, url, tempFilePath)
+}
+
+// ResolveLatestVersionPublic is a public wrapper for testing the private resolveLatestVersion method.
+func (it *InstallDependenciesCommand) ResolveLatestVersionPublic(dependency entities.Dependency) string {
+	return it.resolveLatestVersion(dependency)
+}
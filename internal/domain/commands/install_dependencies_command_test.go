@@ -20,7 +20,7 @@ func TestNewInstallDependenciesCommand(t *testing.T) {
 		// GIVEN: The NewInstallDependenciesCommand constructor is available
 
 		// WHEN: Creating a new install dependencies command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 
 		// THEN: Should return a valid command instance
 		require.NotNil(t, cmd)
@@ -33,7 +33,7 @@ func TestInstallDependenciesCommand_Execute(t *testing.T) {
 	t.Run("should complete without error when empty dependencies provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An install dependencies command and empty dependencies list
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		dependencies := []entities.Dependency{}
 
 		// WHEN: Executing the command with empty dependencies
@@ -61,7 +61,7 @@ func TestInstallDependenciesCommand_Execute(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should attempt to install (tested through integration test success)
@@ -86,7 +86,7 @@ func TestInstallDependenciesCommand_Execute(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command with terraform (if installed)
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should handle version determination logic
@@ -121,7 +121,7 @@ func TestInstallDependenciesCommand_Execute(t *testing.T) {
 		}
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute(dependencies)
 
 		// THEN: Should handle version comparison logic
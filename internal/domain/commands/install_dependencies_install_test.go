@@ -39,7 +39,7 @@ func TestInstallDependenciesCommand_Execute_InstallScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should handle non-zip binary installation path in install method
@@ -66,7 +66,7 @@ func TestInstallDependenciesCommand_Execute_InstallScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should create installation directory if it doesn't exist
@@ -93,7 +93,7 @@ func TestInstallDependenciesCommand_Execute_InstallScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should make binary executable after installation
@@ -120,7 +120,7 @@ func TestInstallDependenciesCommand_Execute_InstallScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should create temp files, extract, and clean up
@@ -147,7 +147,7 @@ func TestInstallDependenciesCommand_Execute_InstallScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should detect file type using `file` command
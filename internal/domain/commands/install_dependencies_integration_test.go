@@ -44,7 +44,7 @@ func TestInstallDependenciesCommand_Execute_Integration(t *testing.T) {
 				BuildDependency()
 
 			// WHEN: Executing the install command
-			cmd := commands.NewInstallDependenciesCommand()
+			cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 			cmd.Execute([]entities.Dependency{dependency})
 
 			// THEN: Should install the dependency
@@ -89,7 +89,7 @@ func TestInstallDependenciesCommand_Execute_Integration(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the install command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should handle zip processing (may fail with mock zip, which is expected)
@@ -141,7 +141,7 @@ func TestInstallDependenciesCommand_Execute_Integration(t *testing.T) {
 				BuildDependency()
 
 			// WHEN: Executing with mixed dependencies
-			cmd := commands.NewInstallDependenciesCommand()
+			cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 			cmd.Execute([]entities.Dependency{terraformDep, terragruntDep})
 
 			// THEN: Should handle both dependencies
@@ -0,0 +1,82 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/entitybuilders"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallDependenciesCommand_releaseSources(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho 'Terraform v1.9.5'\n")
+	checksums := repositoryhelpers.HelperSHA256SUMS(map[string][]byte{"terraform_1.9.5": binary})
+
+	// upstream is a dependency whose release sites must never be reached
+	upstream := entitybuilders.NewDependencyBuilder().
+		WithName("Terraform").
+		WithCLI("terraform").
+		WithBinaryURL("http://127.0.0.1:0/releases/%[1]s/terraform_%[1]s").
+		WithVersionURL("http://127.0.0.1:0/latest").
+		WithChecksumsURL("http://127.0.0.1:0/releases/%s/SHA256SUMS").
+		BuildDependency()
+
+	t.Run("should install from the offline directory when offline", func(t *testing.T) {
+		// GIVEN: An offline directory holding the release and its checksums
+		t.Setenv("TMPDIR", t.TempDir())
+		offlineDir := t.TempDir()
+		releaseDir := filepath.Join(offlineDir, "terraform", "1.9.5")
+		require.NoError(t, os.MkdirAll(releaseDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(releaseDir, "terraform_1.9.5"), binary, 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(releaseDir, "SHA256SUMS"), checksums, 0o600))
+		toolCacheDir := t.TempDir()
+		settings := &entities.Settings{TerraOfflineDir: offlineDir, TerraToolCacheDir: toolCacheDir}
+
+		// WHEN: Installing the version
+		binaryPath := commands.NewInstallDependenciesCommand(settings).InstallVersion(upstream, "1.9.5")
+
+		// THEN: Should install the verified copy into the tool cache
+		assert.Equal(t, filepath.Join(toolCacheDir, "terraform", "1.9.5", "terraform"), binaryPath)
+		content, err := os.ReadFile(binaryPath)
+		require.NoError(t, err)
+		assert.Equal(t, binary, content)
+	})
+
+	t.Run("should install from the mirror when a mirror is configured", func(t *testing.T) {
+		// GIVEN: A mirror serving the release and its checksums
+		t.Setenv("TMPDIR", t.TempDir())
+		server := repositoryhelpers.HelperCreateReleaseServer(t, map[string][]byte{
+			"/artifactory/terraform/1.9.5/terraform_1.9.5": binary,
+			"/artifactory/terraform/1.9.5/SHA256SUMS":      checksums,
+		})
+		toolCacheDir := t.TempDir()
+		settings := &entities.Settings{TerraMirrorURL: server.URL + "/artifactory/", TerraToolCacheDir: toolCacheDir}
+
+		// WHEN: Installing the version
+		binaryPath := commands.NewInstallDependenciesCommand(settings).InstallVersion(upstream, "1.9.5")
+
+		// THEN: Should install the mirrored binary into the tool cache
+		assert.FileExists(t, binaryPath)
+	})
+
+	t.Run("should resolve the latest version from the index of the offline directory", func(t *testing.T) {
+		// GIVEN: An offline directory whose index lists terraform
+		offlineDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(offlineDir, entities.ReleaseIndexFileName),
+			[]byte(`{"terraform": "1.9.5", "terragrunt": "0.67.0"}`), 0o600))
+		settings := &entities.Settings{TerraOfflineDir: offlineDir}
+
+		// WHEN: Resolving the latest version
+		version := commands.NewInstallDependenciesCommand(settings).ResolveLatestVersionPublic(upstream)
+
+		// THEN: Should return the indexed version without reaching the version URL
+		assert.Equal(t, "1.9.5", version)
+	})
+}
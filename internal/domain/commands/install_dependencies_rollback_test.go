@@ -0,0 +1,78 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/entitybuilders"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallDependenciesCommand_Rollback(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because these tests set TERRA_INSTALL_PATH and HOME
+
+	dependencies := []entities.Dependency{
+		entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency(),
+	}
+
+	t.Run("should restore the previous binary and record it when one was kept", func(t *testing.T) {
+		// GIVEN: An installed terraform and the one its install replaced
+		installDir := t.TempDir()
+		home := t.TempDir()
+		t.Setenv("TERRA_INSTALL_PATH", installDir)
+		t.Setenv("HOME", home)
+		repositoryhelpers.HelperCreateMockBinary(t, installDir, "terraform", "echo 'Terraform v1.10.0'")
+		repositoryhelpers.HelperCreateMockBinary(
+			t, installDir, "terraform"+entities.PreviousBinarySuffix, "echo 'Terraform v1.9.8'",
+		)
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
+
+		// WHEN: Rolling terraform back
+		err := cmd.Rollback("terraform", dependencies)
+
+		// THEN: Should swap the binaries and record the rollback in the history
+		require.NoError(t, err)
+		current, _ := os.ReadFile(filepath.Join(installDir, "terraform"))
+		assert.Contains(t, string(current), "v1.9.8")
+		previous, _ := os.ReadFile(filepath.Join(installDir, "terraform"+entities.PreviousBinarySuffix))
+		assert.Contains(t, string(previous), "v1.10.0")
+		history, err := os.ReadFile(filepath.Join(home, ".cache", "terra", entities.InstallHistoryFileName))
+		require.NoError(t, err)
+		assert.Contains(t, string(history), `"action":"rollback","tool":"terraform","version":"1.9.8","previous":"1.10.0"`)
+	})
+
+	t.Run("should return error when no previous binary was kept", func(t *testing.T) {
+		// GIVEN: An installed terraform that never replaced another one
+		installDir := t.TempDir()
+		t.Setenv("TERRA_INSTALL_PATH", installDir)
+		t.Setenv("HOME", t.TempDir())
+		repositoryhelpers.HelperCreateMockBinary(t, installDir, "terraform", "echo 'Terraform v1.10.0'")
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
+
+		// WHEN: Rolling terraform back
+		err := cmd.Rollback("terraform", dependencies)
+
+		// THEN: Should refuse to roll back
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no previous binary")
+	})
+
+	t.Run("should return error when the tool is not a dependency", func(t *testing.T) {
+		// GIVEN: A tool terra does not install
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
+
+		// WHEN: Rolling it back
+		err := cmd.Rollback("packer", dependencies)
+
+		// THEN: Should list the tools that can be rolled back
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown tool "packer" (expected one of: terraform)`)
+	})
+}
@@ -0,0 +1,102 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/entitybuilders"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallDependenciesCommand_verifyDownload(t *testing.T) {
+	binary := []byte("terraform release")
+	checksums := repositoryhelpers.HelperSHA256SUMS(map[string][]byte{"terraform_1.9.5_linux_amd64.zip": binary})
+
+	// download writes the content the install would have downloaded to a temporary file
+	download := func(t *testing.T, content []byte) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "terraform_download")
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+		return path
+	}
+
+	t.Run("should accept a download matching the signed checksums", func(t *testing.T) {
+		// GIVEN: A release whose SHA256SUMS is signed by the trusted key
+		publicKey, sign := repositoryhelpers.HelperCreateSigningKey(t)
+		server := repositoryhelpers.HelperCreateReleaseServer(t, map[string][]byte{
+			"/1.9.5/SHA256SUMS":     checksums,
+			"/1.9.5/SHA256SUMS.sig": sign(checksums),
+		})
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithName("Terraform").
+			WithCLI("terraform").
+			WithChecksumsURL(server.URL+"/%s/SHA256SUMS").
+			WithSignature(server.URL+"/%s/SHA256SUMS.sig", publicKey).
+			BuildDependency()
+
+		// WHEN: Verifying the downloaded archive
+		err := commands.NewInstallDependenciesCommand(&entities.Settings{}).VerifyDownloadPublic(dependency, "1.9.5",
+			server.URL+"/1.9.5/terraform_1.9.5_linux_amd64.zip", download(t, binary))
+
+		// THEN: Should accept it
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a download whose checksum does not match", func(t *testing.T) {
+		// GIVEN: Checksums without a signature, as published by Terragrunt
+		server := repositoryhelpers.HelperCreateReleaseServer(t, map[string][]byte{"/1.9.5/SHA256SUMS": checksums})
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithChecksumsURL(server.URL + "/%s/SHA256SUMS").
+			BuildDependency()
+
+		// WHEN: Verifying a tampered download
+		err := commands.NewInstallDependenciesCommand(&entities.Settings{}).VerifyDownloadPublic(dependency, "1.9.5",
+			server.URL+"/1.9.5/terraform_1.9.5_linux_amd64.zip", download(t, []byte("tampered")))
+
+		// THEN: Should report the mismatch
+		assert.ErrorIs(t, err, entities.ErrChecksumMismatch)
+	})
+
+	t.Run("should reject checksums signed by another key", func(t *testing.T) {
+		// GIVEN: A SHA256SUMS signed by a key other than the trusted one
+		trustedKey, _ := repositoryhelpers.HelperCreateSigningKey(t)
+		_, signWithOtherKey := repositoryhelpers.HelperCreateSigningKey(t)
+		server := repositoryhelpers.HelperCreateReleaseServer(t, map[string][]byte{
+			"/1.9.5/SHA256SUMS":     checksums,
+			"/1.9.5/SHA256SUMS.sig": signWithOtherKey(checksums),
+		})
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithChecksumsURL(server.URL+"/%s/SHA256SUMS").
+			WithSignature(server.URL+"/%s/SHA256SUMS.sig", trustedKey).
+			BuildDependency()
+
+		// WHEN: Verifying a download matching the checksums
+		err := commands.NewInstallDependenciesCommand(&entities.Settings{}).VerifyDownloadPublic(dependency, "1.9.5",
+			server.URL+"/1.9.5/terraform_1.9.5_linux_amd64.zip", download(t, binary))
+
+		// THEN: Should reject the checksums
+		assert.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("should reject a download the checksums do not list", func(t *testing.T) {
+		// GIVEN: Checksums for another file name
+		server := repositoryhelpers.HelperCreateReleaseServer(t, map[string][]byte{"/1.9.5/SHA256SUMS": checksums})
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithChecksumsURL(server.URL + "/%s/SHA256SUMS").
+			BuildDependency()
+
+		// WHEN: Verifying a download with an unlisted name
+		err := commands.NewInstallDependenciesCommand(&entities.Settings{}).VerifyDownloadPublic(dependency, "1.9.5",
+			server.URL+"/1.9.5/terraform_1.9.5_darwin_arm64.zip", download(t, binary))
+
+		// THEN: Should refuse it
+		assert.ErrorContains(t, err, "lists no checksum for terraform_1.9.5_darwin_arm64.zip")
+	})
+}
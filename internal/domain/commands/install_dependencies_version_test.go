@@ -3,7 +3,10 @@
 package commands_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/commands"
@@ -11,6 +14,8 @@ import (
 	"github.com/rios0rios0/terra/test/domain/entitybuilders"
 	"github.com/rios0rios0/terra/test/infrastructure/repositorybuilders"
 	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestInstallDependenciesCommand_Execute_VersionScenarios tests version comparison and prompt functionality.
@@ -52,7 +57,7 @@ func TestInstallDependenciesCommand_Execute_VersionScenarios(t *testing.T) {
 		}()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// Restore stdin
@@ -89,7 +94,7 @@ func TestInstallDependenciesCommand_Execute_VersionScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should have triggered compareVersions with equal versions (== 0 path)
@@ -121,7 +126,7 @@ func TestInstallDependenciesCommand_Execute_VersionScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should have triggered compareVersions with newer local version (> 0 path)
@@ -169,7 +174,7 @@ func TestInstallDependenciesCommand_Execute_VersionScenarios(t *testing.T) {
 		}()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// Restore stdin
@@ -179,3 +184,63 @@ func TestInstallDependenciesCommand_Execute_VersionScenarios(t *testing.T) {
 		// THEN: Should have triggered promptForUpdate returning true and install path
 	})
 }
+
+func TestInstallDependenciesCommand_Execute_PinnedVersion(t *testing.T) {
+	t.Run("should install the pinned version into the tool cache without looking up the latest one", func(t *testing.T) {
+		// GIVEN: terraform 1.0.0 installed while the working directory pins 1.9.5
+		mockBinaryDir := repositoryhelpers.HelperCreateMockTerraformBinary(t, "1.0.0")
+		t.Setenv("PATH", mockBinaryDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		t.Setenv("TMPDIR", t.TempDir())
+		installPath := t.TempDir()
+		t.Setenv("TERRA_INSTALL_PATH", installPath)
+		toolCacheDir := t.TempDir()
+		t.Setenv("TERRA_TOOL_CACHE_DIR", toolCacheDir)
+		t.Setenv("TERRA_TERRAFORM_VERSION", "1.9.5")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(t.TempDir()))
+
+		var requested []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.Path)
+			_, _ = w.Write([]byte("#!/bin/sh\necho 'Terraform v1.9.5'\n"))
+		}))
+		defer server.Close()
+
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithName("Terraform").
+			WithCLI("terraform").
+			WithBinaryURL(server.URL + "/terraform_%s").
+			WithVersionURL(server.URL + "/latest").
+			WithTerraformPattern().
+			BuildDependency()
+
+		// WHEN: Executing the command
+		cmd := commands.NewInstallDependenciesCommand(settings)
+		cmd.Execute([]entities.Dependency{dependency})
+
+		// THEN: Should download only the pinned release, next to the default binary
+		assert.Equal(t, []string{"/terraform_1.9.5"}, requested)
+		assert.FileExists(t, filepath.Join(toolCacheDir, "terraform", "1.9.5", "terraform"))
+		assert.NoFileExists(t, filepath.Join(installPath, "terraform"))
+	})
+
+	t.Run("should not download a pinned version already in the tool cache", func(t *testing.T) {
+		// GIVEN: A tool cache already holding the pinned version
+		toolCacheDir := t.TempDir()
+		cached := filepath.Join(toolCacheDir, "terragrunt", "0.67.0", "terragrunt")
+		require.NoError(t, os.MkdirAll(filepath.Dir(cached), 0o755))
+		require.NoError(t, os.WriteFile(cached, []byte("#!/bin/sh\n"), 0o700))
+		settings := &entities.Settings{TerraToolCacheDir: toolCacheDir}
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithName("Terragrunt").
+			WithCLI("terragrunt").
+			WithBinaryURL("http://127.0.0.1:0/terragrunt_%s").
+			BuildDependency()
+
+		// WHEN: Installing that version
+		binaryPath := commands.NewInstallDependenciesCommand(settings).InstallVersion(dependency, "v0.67.0")
+
+		// THEN: Should return the cached binary
+		assert.Equal(t, cached, binaryPath)
+	})
+}
@@ -41,7 +41,7 @@ func TestInstallDependenciesCommand_Execute_ZipScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should successfully extract and find the binary
@@ -72,7 +72,7 @@ func TestInstallDependenciesCommand_Execute_ZipScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should find binary using pattern matching
@@ -103,7 +103,7 @@ func TestInstallDependenciesCommand_Execute_ZipScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should recursively find binary in nested structure
@@ -130,7 +130,7 @@ func TestInstallDependenciesCommand_Execute_ZipScenarios(t *testing.T) {
 			BuildDependency()
 
 		// WHEN: Executing the command
-		cmd := commands.NewInstallDependenciesCommand()
+		cmd := commands.NewInstallDependenciesCommand(&entities.Settings{})
 		cmd.Execute([]entities.Dependency{dependency})
 
 		// THEN: Should skip non-binary files and find the correct binary
@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,17 +14,26 @@ import (
 	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/internal/domain/repositories"
 	logger "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const defaultMaxJobs = 5
 
 type ParallelStateCommand struct {
-	repository repositories.ParallelShellRepository
+	settings         *entities.Settings
+	repository       repositories.ParallelShellRepository
+	outputRepository repositories.OutputShellRepository
 }
 
-func NewParallelStateCommand(repository repositories.ParallelShellRepository) *ParallelStateCommand {
+func NewParallelStateCommand(
+	settings *entities.Settings,
+	repository repositories.ParallelShellRepository,
+	outputRepository repositories.OutputShellRepository,
+) *ParallelStateCommand {
 	return &ParallelStateCommand{
-		repository: repository,
+		settings:         settings,
+		repository:       repository,
+		outputRepository: outputRepository,
 	}
 }
 
@@ -66,6 +76,11 @@ func (it *ParallelStateCommand) findSubdirectories(rootPath string) ([]string, e
 			return filepath.SkipDir
 		}
 
+		// Skip directories excluded by the project's discovery rules
+		if it.isExcludedFromDiscovery(rootPath, path) {
+			return filepath.SkipDir
+		}
+
 		// Check if directory contains terraform files
 		if it.containsTerraformFiles(path) {
 			modules = append(modules, path)
@@ -87,6 +102,26 @@ func (it *ParallelStateCommand) findSubdirectories(rootPath string) ([]string, e
 	return modules, nil
 }
 
+// isExcludedFromDiscovery checks whether a directory matches one of the configured
+// discovery exclusion globs, either by base name or by path relative to the root.
+func (it *ParallelStateCommand) isExcludedFromDiscovery(rootPath, path string) bool {
+	relPath, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		relPath = path
+	}
+
+	for _, pattern := range it.settings.TerraDiscoveryExclude {
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, filepath.ToSlash(relPath)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
 // containsTerraformFiles checks if a directory contains terraform or terragrunt files.
 func (it *ParallelStateCommand) containsTerraformFiles(dirPath string) bool {
 	entries, err := os.ReadDir(dirPath)
@@ -196,6 +231,10 @@ func (it *ParallelStateCommand) resolveModules(
 	arguments []string,
 ) ([]string, error) {
 	selection := GetSelectionValues(arguments)
+	// Default skips from the project configuration never override an explicit --only
+	if len(selection.Only) == 0 {
+		selection.Skip = append(selection.Skip, it.settings.TerraSkip...)
+	}
 	hasSelection := len(selection.Only) > 0 || len(selection.Skip) > 0
 
 	if hasSelection {
@@ -219,35 +258,46 @@ func (it *ParallelStateCommand) resolveModules(
 	return modules, nil
 }
 
-// runWorkers spawns goroutine workers that execute the command across modules and collects errors.
+// moduleOutcome is what a worker reports about a module: its result for the run summary,
+// and the error failing the run, if any.
+type moduleOutcome struct {
+	result entities.ModuleResult
+	err    error
+}
+
+// runWorkers spawns goroutine workers that execute the command across modules and collects
+// their results, ordered by module path, and errors.
 func (it *ParallelStateCommand) runWorkers(
 	modules []string,
 	filteredArguments []string,
 	maxJobs int,
-) []error {
+) ([]entities.ModuleResult, []error) {
 	jobs := make(chan string, len(modules))
-	results := make(chan error, len(modules))
+	outcomes := make(chan moduleOutcome, len(modules))
+	resolver := newModuleEnvironmentResolver(it.settings, it.repository, it.outputRepository)
 
 	var wg sync.WaitGroup
 
-	for range maxJobs {
+	for index := range maxJobs {
+		worker := index + 1
 		wg.Go(func() {
 			for modulePath := range jobs {
-				logger.Infof("==> Processing %s", modulePath)
-
-				// Prefix each worker's output with the module's directory name so the
-				// interleaved logs from concurrent modules stay attributable.
-				prefix := filepath.Base(modulePath)
-				executeErr := it.repository.ExecuteCommandWithPrefix(
-					"terragrunt", filteredArguments, modulePath, prefix,
-				)
+				moduleLog := logger.WithFields(logger.Fields{"module": modulePath, "worker": worker})
+				moduleLog.Infof("==> Processing %s", modulePath)
+
+				span := entities.StartSpan(worker, entities.SpanModule,
+					attribute.String("terra.module", modulePath), attribute.Int("terra.worker", worker))
+				start := time.Now()
+				executeErr := it.executeModule(resolver, modulePath, filteredArguments, worker)
+				span.End(executeErr)
+				outcome := moduleOutcome{result: entities.NewModuleResult(modulePath, time.Since(start), executeErr)}
 				if executeErr != nil {
-					logger.Errorf("✗ %s: %s", modulePath, executeErr)
-					results <- fmt.Errorf("module %s failed: %w", modulePath, executeErr)
+					moduleLog.Errorf("✗ %s: %s", modulePath, executeErr)
+					outcome.err = fmt.Errorf("module %s failed: %w", modulePath, executeErr)
 				} else {
-					logger.Infof("✓ %s", modulePath)
-					results <- nil
+					moduleLog.Infof("✓ %s", modulePath)
 				}
+				outcomes <- outcome
 			}
 		})
 	}
@@ -261,17 +311,72 @@ func (it *ParallelStateCommand) runWorkers(
 
 	go func() {
 		wg.Wait()
-		close(results)
+		close(outcomes)
 	}()
 
+	results := make([]entities.ModuleResult, 0, len(modules))
 	var executeErrors []error
-	for err := range results {
+	for outcome := range outcomes {
+		results = append(results, outcome.result)
+		if outcome.err != nil {
+			executeErrors = append(executeErrors, outcome.err)
+		}
+	}
+	slices.SortFunc(results, func(a, b entities.ModuleResult) int { return strings.Compare(a.Path, b.Path) })
+
+	return results, executeErrors
+}
+
+// executeModule runs the account and workspace preparation for one module, then the
+// command itself between its hooks, all scoped to the module's own environment. The
+// worker is the 1-based id of the pool worker running the module.
+func (it *ParallelStateCommand) executeModule(
+	resolver *moduleEnvironmentResolver,
+	modulePath string,
+	filteredArguments []string,
+	worker int,
+) error {
+	// Prefix each worker's output with the module's directory name so the
+	// interleaved logs from concurrent modules stay attributable.
+	prefix := filepath.Base(modulePath)
+
+	module, err := resolver.resolve(modulePath, worker)
+	if err != nil {
+		return err
+	}
+
+	if workspace, ok := resolveWorkspace(module.settings); ok {
+		span := entities.StartSpan(worker, entities.SpanWorkspaceSelect, attribute.String("terra.workspace", workspace))
+		err = it.repository.ExecuteCommandWithPrefix(
+			"terragrunt",
+			[]string{"workspace", "select", "-or-create", workspace},
+			modulePath, prefix, worker, module.environment,
+		)
+		span.End(err)
 		if err != nil {
-			executeErrors = append(executeErrors, err)
+			return fmt.Errorf("failed to change workspace: %w", err)
 		}
 	}
 
-	return executeErrors
+	hooks := &hookRunner{
+		settings: module.settings,
+		execute: func(command string, arguments []string, directory string, environment []string) error {
+			return it.repository.ExecuteCommandWithPrefix(
+				command, arguments, directory, prefix, worker, append(slices.Clone(module.environment), environment...),
+			)
+		},
+	}
+	if err = hooks.before(modulePath, filteredArguments); err != nil {
+		hooks.after(modulePath, filteredArguments, 0, err)
+		return err
+	}
+
+	start := time.Now()
+	err = it.repository.ExecuteCommandWithPrefix(
+		"terragrunt", filteredArguments, modulePath, prefix, worker, module.environment,
+	)
+	hooks.after(modulePath, filteredArguments, time.Since(start), err)
+	return err
 }
 
 // executeInParallel executes the command in parallel across multiple directories.
@@ -296,7 +401,7 @@ func (it *ParallelStateCommand) executeInParallel(
 	filteredArguments := it.removeParallelFlags(arguments)
 	filteredArguments = append(filteredArguments, injected...)
 
-	executeErrors := it.runWorkers(modules, filteredArguments, maxJobs)
+	results, executeErrors := it.runWorkers(modules, filteredArguments, maxJobs)
 	duration := time.Since(startTime)
 
 	successful := len(modules) - len(executeErrors)
@@ -305,6 +410,9 @@ func (it *ParallelStateCommand) executeInParallel(
 		successful, len(executeErrors), maxJobs, duration.Round(time.Millisecond),
 	)
 
+	notifier := &runNotifier{settings: it.settings}
+	notifier.notify(targetPath, arguments, duration, results)
+
 	if len(executeErrors) > 0 {
 		for _, workerErr := range executeErrors {
 			logger.Error(workerErr)
@@ -327,8 +435,11 @@ func (it *ParallelStateCommand) Execute(
 		return errors.New("command is not a parallel command")
 	}
 
-	// Determine max jobs: use parallel=N value if present, otherwise default
+	// Determine max jobs: use parallel=N value if present, otherwise the configured default
 	maxJobs := defaultMaxJobs
+	if it.settings.TerraParallelism > 0 {
+		maxJobs = it.settings.TerraParallelism
+	}
 	if parallelValue, found := GetParallelValue(arguments); found {
 		maxJobs = parallelValue
 		logger.Infof("Using %d parallel threads", maxJobs)
@@ -3,9 +3,12 @@
 package commands_test
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"filippo.io/age"
 	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/test/infrastructure/repositorydoubles"
@@ -32,7 +35,7 @@ func TestNewParallelStateCommand(t *testing.T) {
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
 
 		// WHEN: Creating a new parallel state command
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 
 		// THEN: Should create a valid command instance
 		require.NotNil(t, cmd)
@@ -43,7 +46,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute import command in parallel when --parallel flag present", func(t *testing.T) {
 		// GIVEN: A parallel state command and import arguments with --parallel=5
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "null_resource.test", "test-id"}
 		dependencies := []entities.Dependency{}
 
@@ -63,7 +66,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute state rm command in parallel when --parallel flag present", func(t *testing.T) {
 		// GIVEN: A parallel state command and state rm arguments with --parallel=5
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"state", "rm", "--parallel=5", "null_resource.test"}
 		dependencies := []entities.Dependency{}
 
@@ -83,7 +86,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should return error when command is not state manipulation", func(t *testing.T) {
 		// GIVEN: A parallel state command and non-state arguments without --parallel=N flag
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := "/tmp/test-terraform"
 		arguments := []string{"plan"}
 		dependencies := []entities.Dependency{}
@@ -99,7 +102,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should skip hidden directories when discovering modules", func(t *testing.T) {
 		// GIVEN: A directory with hidden and non-hidden module directories
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -122,7 +125,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should not descend into .terragrunt-cache and discover cached dependencies", func(t *testing.T) {
 		// GIVEN: A directory with a real module and a .terragrunt-cache containing cached dependency modules
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -148,7 +151,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should skip directories without terraform files", func(t *testing.T) {
 		// GIVEN: A directory with one tf module and one non-tf directory
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -171,7 +174,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should detect terragrunt.hcl files as valid modules", func(t *testing.T) {
 		// GIVEN: A directory with a terragrunt.hcl module (no .tf files)
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -191,7 +194,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should detect tfvars files as valid modules", func(t *testing.T) {
 		// GIVEN: A directory with only .tfvars files
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -211,7 +214,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should return error when no modules found", func(t *testing.T) {
 		// GIVEN: A parallel state command and empty directory
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "null_resource.test", "test-id"}
 		dependencies := []entities.Dependency{}
 
@@ -229,7 +232,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should handle state mv command correctly", func(t *testing.T) {
 		// GIVEN: A parallel state command and state mv arguments
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"state", "mv", "--parallel=5", "old_resource", "new_resource"}
 		dependencies := []entities.Dependency{}
 
@@ -253,7 +256,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute with --parallel=2 for any command", func(t *testing.T) {
 		// GIVEN: A parallel state command with --parallel=2 flag
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -272,7 +275,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute with --only when only flag present", func(t *testing.T) {
 		// GIVEN: A parallel state command with --only flag
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "--only=mod1,mod2", "null_resource.test", "id"}
 		dependencies := []entities.Dependency{}
 
@@ -291,7 +294,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute with --skip when skip flag present", func(t *testing.T) {
 		// GIVEN: A parallel state command with --skip flag
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "--skip=mod3", "null_resource.test", "id"}
 		dependencies := []entities.Dependency{}
 
@@ -310,7 +313,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should return error when only matches no valid paths", func(t *testing.T) {
 		// GIVEN: A parallel state command with --only pointing to nonexistent dirs
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "--only=nonexistent", "null_resource.test", "id"}
 		dependencies := []entities.Dependency{}
 
@@ -327,7 +330,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute with both --only and --skip when both flags present", func(t *testing.T) {
 		// GIVEN: A parallel state command with both --only and --skip flags
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "--only=mod1,mod2,mod3", "--skip=mod2", "null_resource.test", "id"}
 		dependencies := []entities.Dependency{}
 
@@ -346,7 +349,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should execute with --skip only discovering all modules first", func(t *testing.T) {
 		// GIVEN: A parallel state command with only --skip flag (no --only)
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=4", "--skip=mod2"}
 		dependencies := []entities.Dependency{}
 
@@ -365,7 +368,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should return error when skip removes all discovered modules", func(t *testing.T) {
 		// GIVEN: A parallel state command where --skip removes all modules
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2", "--skip=mod1,mod2"}
 		dependencies := []entities.Dependency{}
 
@@ -384,7 +387,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should not pass --only or --skip flags to terragrunt", func(t *testing.T) {
 		// GIVEN: A parallel state command with both --only and --skip flags
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"import", "--parallel=5", "--only=mod1", "--skip=mod2", "null_resource.test", "id"}
 		dependencies := []entities.Dependency{}
 
@@ -408,7 +411,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should strip --reply flag and inject --non-interactive and -auto-approve for apply", func(t *testing.T) {
 		// GIVEN: A parallel state command with --reply=y flag on an apply command
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"apply", "--parallel=2", "--reply=y"}
 		dependencies := []entities.Dependency{}
 
@@ -433,7 +436,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should inject --non-interactive but not -auto-approve for plan with --reply", func(t *testing.T) {
 		// GIVEN: A parallel state command with --reply=y flag on a plan command (non-interactive)
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2", "--reply=y"}
 		dependencies := []entities.Dependency{}
 
@@ -455,7 +458,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should not inject --non-interactive when no confirmation flag present", func(t *testing.T) {
 		// GIVEN: A parallel state command without any confirmation flag
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"plan", "--parallel=2"}
 		dependencies := []entities.Dependency{}
 
@@ -476,7 +479,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should strip --yes and inject --non-interactive and -auto-approve for apply", func(t *testing.T) {
 		// GIVEN: A parallel state command with the new --yes flag on apply
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"apply", "--parallel=2", "--yes"}
 		dependencies := []entities.Dependency{}
 
@@ -502,7 +505,7 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	t.Run("should strip --no and inject only --non-interactive", func(t *testing.T) {
 		// GIVEN: A parallel state command with the new --no flag on apply
 		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
-		cmd := commands.NewParallelStateCommand(repository)
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
 		arguments := []string{"apply", "--parallel=2", "--no"}
 		dependencies := []entities.Dependency{}
 
@@ -526,6 +529,227 @@ func TestParallelStateCommand_Execute(t *testing.T) {
 	})
 }
 
+func TestParallelStateCommand_ProjectDefaults(t *testing.T) {
+	t.Run("should skip the configured modules when no --only is given", func(t *testing.T) {
+		// GIVEN: Settings with a default skip list
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		settings := &entities.Settings{TerraSkip: []string{"legacy"}}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"app", "legacy"})
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: Should only run the module that is not skipped
+		require.NoError(t, err)
+		require.Len(t, repository.CallHistory, 1)
+		assert.Equal(t, "app", repository.CallHistory[0].Prefix)
+	})
+
+	t.Run("should not apply the configured skips when --only selects the module", func(t *testing.T) {
+		// GIVEN: Settings with a default skip list and an explicit --only
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		settings := &entities.Settings{TerraSkip: []string{"legacy"}}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"app", "legacy"})
+
+		// WHEN: Executing the command selecting the skipped module
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2", "--only=legacy"}, []entities.Dependency{})
+
+		// THEN: Should run the explicitly selected module
+		require.NoError(t, err)
+		require.Len(t, repository.CallHistory, 1)
+		assert.Equal(t, "legacy", repository.CallHistory[0].Prefix)
+	})
+
+	t.Run("should not discover directories matching the discovery exclusions", func(t *testing.T) {
+		// GIVEN: Settings excluding example directories from discovery
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		settings := &entities.Settings{TerraDiscoveryExclude: []string{"examples"}}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"app", "examples/basic"})
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: Should not descend into the excluded directory
+		require.NoError(t, err)
+		require.Len(t, repository.CallHistory, 1)
+		assert.Equal(t, "app", repository.CallHistory[0].Prefix)
+	})
+}
+
+func TestParallelStateCommand_ModuleEnvironment(t *testing.T) {
+	t.Run("should select the workspace declared in each module .env", func(t *testing.T) {
+		// GIVEN: Two modules declaring different workspaces in their own .env
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"dev", "prod"})
+		require.NoError(t, writeFile(tempDir+"/dev/.env", "TERRA_WORKSPACE=development\n"))
+		require.NoError(t, writeFile(tempDir+"/prod/.env", "TERRA_WORKSPACE=production\n"))
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: Each module should select its own workspace before running the command
+		require.NoError(t, err)
+		assert.Equal(t, 4, repository.ExecuteCallCount)
+		workspaces := map[string]string{}
+		for _, call := range repository.CallHistory {
+			if len(call.Arguments) == 4 && call.Arguments[0] == "workspace" {
+				workspaces[call.Prefix] = call.Arguments[3]
+			}
+		}
+		assert.Equal(t, map[string]string{"dev": "development", "prod": "production"}, workspaces)
+	})
+
+	t.Run("should pass the module's decrypted .env.enc to its processes", func(t *testing.T) {
+		// GIVEN: A module with an age-encrypted .env.enc and the matching key file
+		identity, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+		keyFile := filepath.Join(t.TempDir(), "keys.txt")
+		require.NoError(t, writeFile(keyFile, identity.String()+"\n"))
+		t.Setenv("TERRA_AGE_KEY_FILE", keyFile)
+		var encrypted bytes.Buffer
+		writer, err := age.Encrypt(&encrypted, identity.Recipient())
+		require.NoError(t, err)
+		_, err = writer.Write([]byte("TF_VAR_db_password=s3cr3t\n"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1"})
+		require.NoError(t, writeFile(tempDir+"/mod1/.env.enc", encrypted.String()))
+
+		// WHEN: Executing the command in parallel
+		err = cmd.Execute(tempDir, []string{"plan", "--parallel=1"}, []entities.Dependency{})
+
+		// THEN: The decrypted variable should reach the module's process environment
+		require.NoError(t, err)
+		require.Len(t, repository.CallHistory, 1)
+		assert.Contains(t, repository.CallHistory[0].Environment, "TF_VAR_db_password=s3cr3t")
+	})
+
+	t.Run("should pass assumed role credentials through the module environment", func(t *testing.T) {
+		// GIVEN: Two modules sharing the same role declared in their .env
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		outputRepository := &repositorydoubles.StubOutputShellRepository{
+			Output: `{"Credentials":{"AccessKeyId":"AKIA","SecretAccessKey":"secret","SessionToken":"token"}}`,
+		}
+		settings := &entities.Settings{TerraCloud: "aws"}
+		cmd := commands.NewParallelStateCommand(settings, repository, outputRepository)
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1", "mod2"})
+		require.NoError(t, writeFile(tempDir+"/mod1/.env", "TERRA_AWS_ROLE_ARN=arn:aws:iam::1:role/x\n"))
+		require.NoError(t, writeFile(tempDir+"/mod2/.env", "TERRA_AWS_ROLE_ARN=arn:aws:iam::1:role/x\n"))
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: The role should be assumed once and its credentials scoped to every module process
+		require.NoError(t, err)
+		assert.Len(t, outputRepository.CallHistory, 1)
+		assert.Equal(t, "aws", outputRepository.CallHistory[0].Command)
+		require.Len(t, repository.CallHistory, 2)
+		for _, call := range repository.CallHistory {
+			assert.Contains(t, call.Environment, "AWS_ACCESS_KEY_ID=AKIA")
+			assert.Contains(t, call.Environment, "AWS_SESSION_TOKEN=token")
+		}
+	})
+
+	t.Run("should log in once before resolving a shared account", func(t *testing.T) {
+		// GIVEN: Two modules sharing an SSO profile whose token cache is missing
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("AWS_CONFIG_FILE", "")
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		settings := &entities.Settings{TerraCloud: "aws", TerraAwsProfile: "dev", TerraAwsSSO: true}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1", "mod2"})
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: SSO login should run once and every module should use the profile
+		require.NoError(t, err)
+		logins := 0
+		for _, call := range repository.CallHistory {
+			if call.Command == "aws" {
+				logins++
+				continue
+			}
+			assert.Contains(t, call.Environment, "AWS_PROFILE=dev")
+		}
+		assert.Equal(t, 1, logins)
+		assert.Equal(t, 3, repository.ExecuteCallCount)
+	})
+
+	t.Run("should authenticate through the ARM variables without a global az login", func(t *testing.T) {
+		// GIVEN: Two modules and a service principal with a certificate and TERRA_AZURE_LOGIN
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		settings := &entities.Settings{
+			TerraCloud:                      "azure",
+			TerraAzureSubscriptionID:        "sub-1",
+			TerraAzureTenantID:              "tenant-1",
+			TerraAzureClientID:              "client-1",
+			TerraAzureClientCertificatePath: "/certs/sp.pem",
+			TerraAzureLogin:                 true,
+		}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1", "mod2"})
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: Should only run terragrunt, with the credentials in every module's environment
+		require.NoError(t, err)
+		require.Len(t, repository.CallHistory, 2)
+		for _, call := range repository.CallHistory {
+			assert.Equal(t, "terragrunt", call.Command)
+			assert.Contains(t, call.Environment, "ARM_CLIENT_CERTIFICATE_PATH=/certs/sp.pem")
+		}
+	})
+
+	t.Run("should fail the module without running terragrunt when the account cannot be resolved", func(t *testing.T) {
+		// GIVEN: A module whose role cannot be assumed
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		outputRepository := &repositorydoubles.StubOutputShellRepository{ShouldFail: true}
+		settings := &entities.Settings{TerraCloud: "aws", TerraAwsRoleArn: "arn:aws:iam::1:role/x"}
+		cmd := commands.NewParallelStateCommand(settings, repository, outputRepository)
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1"})
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: The run should fail and terragrunt should never be called
+		require.Error(t, err)
+		assert.Equal(t, 0, repository.ExecuteCallCount)
+	})
+
+	t.Run("should return error when the module .env holds an invalid setting", func(t *testing.T) {
+		// GIVEN: A module declaring an unsupported cloud
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		cmd := commands.NewParallelStateCommand(&entities.Settings{}, repository, &repositorydoubles.StubOutputShellRepository{})
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1"})
+		require.NoError(t, writeFile(tempDir+"/mod1/.env", "TERRA_CLOUD=gcp\n"))
+
+		// WHEN: Executing the command in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: The module should fail before any command runs
+		require.Error(t, err)
+		assert.Equal(t, 0, repository.ExecuteCallCount)
+	})
+}
+
 // testDirectoryHelper helps create test directories for parallel state tests
 type testDirectoryHelper struct {
 	t *testing.T
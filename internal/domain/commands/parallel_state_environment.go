@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/domain/repositories"
+	logger "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// moduleEnvironment is the isolated execution context of a single module in the worker pool.
+type moduleEnvironment struct {
+	settings    *entities.Settings
+	environment []string
+}
+
+// accountResolution memoizes one account lookup so modules sharing an account only pay for
+// a single credential request, even when several workers ask for it concurrently.
+type accountResolution struct {
+	once        sync.Once
+	environment []string
+	err         error
+}
+
+// moduleEnvironmentResolver builds the per-module settings and process environment for a
+// parallel run. Nothing here touches the process environment or the global cloud CLI
+// state: every value is handed to the child process through its own environment.
+type moduleEnvironmentResolver struct {
+	settings         *entities.Settings
+	repository       repositories.ParallelShellRepository
+	outputRepository repositories.OutputShellRepository
+
+	mu       sync.Mutex
+	accounts map[string]*accountResolution
+}
+
+func newModuleEnvironmentResolver(
+	settings *entities.Settings,
+	repository repositories.ParallelShellRepository,
+	outputRepository repositories.OutputShellRepository,
+) *moduleEnvironmentResolver {
+	return &moduleEnvironmentResolver{
+		settings:         settings,
+		repository:       repository,
+		outputRepository: outputRepository,
+		accounts:         make(map[string]*accountResolution),
+	}
+}
+
+// resolve layers the module's own .env over the process-wide settings and returns the
+// environment that scopes the module's child processes to its account. The worker is the
+// pool worker asking, recorded in the logs of any login it triggers.
+func (it *moduleEnvironmentResolver) resolve(modulePath string, worker int) (*moduleEnvironment, error) {
+	values, err := entities.ReadEnvFiles(modulePath, it.settings.TerraProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := it.settings.WithOverrides(values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid settings in %s: %w", modulePath, err)
+	}
+
+	environment := make([]string, 0, len(values))
+	for key, value := range values {
+		environment = append(environment, key+"="+value)
+	}
+
+	cli := entities.NewCLI(settings)
+	if cli != nil && cli.CanChangeAccount() {
+		span := entities.StartSpan(worker, entities.SpanAccountSwitch, attribute.String("terra.cloud", cli.GetName()))
+		accountEnvironment, accountErr := it.resolveAccount(cli, modulePath, worker)
+		span.End(accountErr)
+		if accountErr != nil {
+			return nil, fmt.Errorf("failed to change account: %w", accountErr)
+		}
+		environment = append(environment, accountEnvironment...)
+	}
+
+	return &moduleEnvironment{settings: settings, environment: environment}, nil
+}
+
+// resolveAccount returns the account environment for the CLI, running the login and the
+// credential commands at most once per distinct account. A login that would switch the
+// CLI's identity for every process (e.g. `az login`) is never run by the workers: the
+// account environment alone authenticates the module's processes.
+func (it *moduleEnvironmentResolver) resolveAccount(
+	cli entities.CLI,
+	modulePath string,
+	worker int,
+) ([]string, error) {
+	var login []string
+	if cli.CanLogin() && cli.IsLoginGlobal() {
+		logger.Debugf("Skipping the %s login in %s, which would be shared by every worker", cli.GetName(), modulePath)
+	} else if cli.CanLogin() {
+		var err error
+		if login, err = cli.GetCommandLogin(); err != nil {
+			return nil, err
+		}
+	}
+
+	command := cli.GetCommandAccountEnvironment()
+	if command == nil && login == nil {
+		// built from settings alone: cheap, and nothing to share between modules
+		return cli.GetAccountEnvironment("")
+	}
+
+	key := cli.GetName() + " " + strings.Join(command, " ") + " | " + strings.Join(login, " ")
+	it.mu.Lock()
+	resolution, found := it.accounts[key]
+	if !found {
+		resolution = &accountResolution{}
+		it.accounts[key] = resolution
+	}
+	it.mu.Unlock()
+
+	resolution.once.Do(func() {
+		if login != nil {
+			resolution.err = it.repository.ExecuteCommandWithPrefix(
+				cli.GetName(), login, modulePath, filepath.Base(modulePath), worker, nil,
+			)
+			if resolution.err != nil {
+				return
+			}
+		}
+
+		var output string
+		if command != nil {
+			output, resolution.err = it.outputRepository.ExecuteCommandWithOutput(cli.GetName(), command, modulePath, nil)
+			if resolution.err != nil {
+				return
+			}
+		}
+		resolution.environment, resolution.err = cli.GetAccountEnvironment(output)
+	})
+
+	return resolution.environment, resolution.err
+}
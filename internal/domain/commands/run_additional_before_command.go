@@ -4,68 +4,137 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/internal/domain/repositories"
 	logger "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type RunAdditionalBeforeCommand struct {
-	settings   *entities.Settings
-	cli        entities.CLI
-	repository repositories.ShellRepository
+	settings         *entities.Settings
+	cli              entities.CLI
+	repository       repositories.ShellRepository
+	outputRepository repositories.OutputShellRepository
 }
 
 func NewRunAdditionalBeforeCommand(
 	settings *entities.Settings,
 	cli entities.CLI,
 	repository repositories.ShellRepository,
+	outputRepository repositories.OutputShellRepository,
 ) *RunAdditionalBeforeCommand {
 	return &RunAdditionalBeforeCommand{
-		settings:   settings,
-		cli:        cli,
-		repository: repository,
+		settings:         settings,
+		cli:              cli,
+		repository:       repository,
+		outputRepository: outputRepository,
 	}
 }
 
 func (it *RunAdditionalBeforeCommand) Execute(targetPath string, arguments []string) {
-	// change account if necessary
-	if it.cli != nil && it.cli.CanChangeAccount() {
-		err := it.repository.ExecuteCommand(
-			it.cli.GetName(),
-			it.cli.GetCommandChangeAccount(),
-			targetPath,
-		)
-		if err != nil {
-			logger.Fatalf("Error changing account: %s", err)
+	// the cloud may only be declared, or changed, by the target's project configuration or
+	// its profile, which are resolved after the CLI was injected
+	it.cli = it.selectCLI()
+
+	// log in and change account if necessary
+	if it.cli != nil && (it.cli.CanLogin() || it.cli.CanChangeAccount()) {
+		span := entities.StartSpan(0, entities.SpanAccountSwitch, attribute.String("terra.cloud", it.cli.GetName()))
+		it.login(targetPath)
+		if it.cli.CanChangeAccount() {
+			it.changeAccount(targetPath)
 		}
+		span.End(nil)
 	}
 
 	// init environment if necessary
 	if shouldInitEnvironment(arguments, targetPath) {
-		if err := it.repository.ExecuteCommand("terragrunt", []string{"init"}, targetPath); err != nil {
+		span := entities.StartSpan(0, entities.SpanProactiveInit)
+		err := it.repository.ExecuteCommand("terragrunt", []string{"init"}, targetPath)
+		span.End(err)
+		if err != nil {
 			logger.Warnf("Proactive terragrunt init failed in %s: %v", targetPath, err)
 		}
 	}
 
 	// change workspace if necessary
-	if value, ok := it.shouldChangeWorkspace(); ok {
+	if value, ok := resolveWorkspace(it.settings); ok {
+		span := entities.StartSpan(0, entities.SpanWorkspaceSelect, attribute.String("terra.workspace", value))
 		err := it.repository.ExecuteCommand(
 			"terragrunt",
 			[]string{"workspace", "select", "-or-create", value},
 			targetPath,
 		)
+		span.End(err)
 		if err != nil {
 			logger.Fatalf("Error changing workspace: %s", err)
 		}
 	}
 }
 
-func (it *RunAdditionalBeforeCommand) shouldChangeWorkspace() (string, bool) {
-	if it.settings.TerraNoWorkspace {
+// selectCLI returns the adapter of the cloud selected by the settings as they are resolved
+// now, keeping the current one while it still serves that cloud. The adapters read the
+// account settings whenever they are called, so only a change of cloud needs a new one.
+func (it *RunAdditionalBeforeCommand) selectCLI() entities.CLI {
+	selected := entities.NewCLI(it.settings)
+	if selected != nil && it.cli != nil && it.cli.GetName() == selected.GetName() {
+		return it.cli
+	}
+	return selected
+}
+
+// login runs the CLI's login command, when it has one.
+func (it *RunAdditionalBeforeCommand) login(targetPath string) {
+	if !it.cli.CanLogin() {
+		return
+	}
+
+	command, err := it.cli.GetCommandLogin()
+	if err != nil {
+		logger.Fatalf("Error building login command: %s", err)
+	}
+	if err = it.repository.ExecuteCommand(it.cli.GetName(), command, targetPath); err != nil {
+		logger.Fatalf("Error logging in: %s", err)
+	}
+}
+
+// changeAccount switches to the configured account and exports its environment, so
+// terragrunt and its providers inherit it. When the environment is built from a command's
+// output (e.g. assumed-role credentials), that output is captured instead of printed.
+func (it *RunAdditionalBeforeCommand) changeAccount(targetPath string) {
+	var output string
+	if command := it.cli.GetCommandAccountEnvironment(); command != nil {
+		var err error
+		output, err = it.outputRepository.ExecuteCommandWithOutput(it.cli.GetName(), command, targetPath, nil)
+		if err != nil {
+			logger.Fatalf("Error changing account: %s", err)
+		}
+	} else if command := it.cli.GetCommandChangeAccount(); command != nil {
+		if err := it.repository.ExecuteCommand(it.cli.GetName(), command, targetPath); err != nil {
+			logger.Fatalf("Error changing account: %s", err)
+		}
+	}
+
+	environment, err := it.cli.GetAccountEnvironment(output)
+	if err != nil {
+		logger.Fatalf("Error resolving account environment: %s", err)
+	}
+	for _, entry := range environment {
+		key, value, _ := strings.Cut(entry, "=")
+		if err = os.Setenv(key, value); err != nil {
+			logger.Fatalf("Error exporting %s: %s", key, err)
+		}
+	}
+}
+
+// resolveWorkspace returns the workspace to select for the given settings, and whether a
+// workspace switch is needed at all.
+func resolveWorkspace(settings *entities.Settings) (string, bool) {
+	if settings.TerraNoWorkspace {
 		return "", false
 	}
-	workspace := it.settings.TerraTerraformWorkspace
+	workspace := settings.TerraTerraformWorkspace
 	return workspace, workspace != ""
 }
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
 
 	"github.com/rios0rios0/terra/test/domain/entitybuilders"
 	"github.com/rios0rios0/terra/test/domain/entitydoubles"
@@ -35,7 +36,7 @@ func TestNewRunAdditionalBeforeCommand(t *testing.T) {
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
 
 		// WHEN: Creating a new RunAdditionalBeforeCommand
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
 
 		// THEN: Should return a valid command instance
 		require.NotNil(t, cmd)
@@ -57,7 +58,7 @@ func TestRunAdditionalBeforeCommand_Execute_AccountChange(t *testing.T) {
 			CommandChangeAccount:  []string{"sts", "assume-role", "--role-arn", "test-role"},
 		}
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
@@ -87,7 +88,7 @@ func TestRunAdditionalBeforeCommand_Execute_AccountChange(t *testing.T) {
 			CommandChangeAccount:  []string{},
 		}
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
@@ -108,7 +109,7 @@ func TestRunAdditionalBeforeCommand_Execute_AccountChange(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
@@ -122,6 +123,121 @@ func TestRunAdditionalBeforeCommand_Execute_AccountChange(t *testing.T) {
 	})
 }
 
+func TestRunAdditionalBeforeCommand_Execute_CloudChange(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because the account environment is exported to the process
+
+	t.Run("should switch to the cloud selected once the target configuration is loaded", func(t *testing.T) {
+		// GIVEN: An AWS adapter injected at startup, and settings now selecting Azure
+		t.Setenv("ARM_SUBSCRIPTION_ID", "")
+		settings := &entities.Settings{TerraCloud: "azure", TerraAzureSubscriptionID: "sub-1"}
+		cli := &entitydoubles.StubCLI{
+			Name:                  "aws",
+			CanChangeAccountValue: true,
+			CommandChangeAccount:  []string{"sts", "assume-role", "--role-arn", "test-role"},
+		}
+		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the command
+		cmd.Execute(t.TempDir(), []string{"plan"})
+
+		// THEN: Should select the Azure subscription instead of assuming the AWS role
+		require.NotEmpty(t, repository.CallHistory)
+		assert.Equal(t, "az", repository.CallHistory[0].Command)
+		assert.Equal(t, []string{"account", "set", "--subscription", "sub-1"}, repository.CallHistory[0].Arguments)
+		assert.Equal(t, "sub-1", os.Getenv("ARM_SUBSCRIPTION_ID"))
+	})
+}
+
+func TestRunAdditionalBeforeCommand_Execute_Login(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should log in before changing account when CLI can log in", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A CLI requesting a non-interactive login
+		settings := entitybuilders.NewSettingsBuilder().WithTerraCloud("azure").BuildSettings()
+		cli := &entitydoubles.StubCLI{
+			Name:                  "az",
+			CanLoginValue:         true,
+			CommandLogin:          []string{"login", "--service-principal"},
+			CanChangeAccountValue: true,
+			CommandChangeAccount:  []string{"account", "set"},
+		}
+		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the command
+		cmd.Execute(t.TempDir(), []string{"init"})
+
+		// THEN: Should log in first, then change account
+		require.Len(t, repository.CallHistory, 2)
+		assert.Equal(t, []string{"login", "--service-principal"}, repository.CallHistory[0].Arguments)
+		assert.Equal(t, []string{"account", "set"}, repository.CallHistory[1].Arguments)
+	})
+
+	t.Run("should not log in when CLI cannot log in", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A CLI without login configured
+		settings := entitybuilders.NewSettingsBuilder().WithTerraCloud("azure").BuildSettings()
+		cli := &entitydoubles.StubCLI{Name: "az", CommandLogin: []string{"login"}}
+		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the command
+		cmd.Execute(t.TempDir(), []string{"init"})
+
+		// THEN: Should not run any CLI command
+		assert.Empty(t, repository.CallHistory)
+	})
+}
+
+func TestRunAdditionalBeforeCommand_Execute_AccountEnvironment(t *testing.T) {
+	t.Run("should export account environment and skip nil change account command", func(t *testing.T) {
+		// GIVEN: A CLI whose account is fully described by environment variables
+		t.Setenv("ARM_CLIENT_ID", "")
+		settings := entitybuilders.NewSettingsBuilder().WithTerraCloud("azure").BuildSettings()
+		cli := &entitydoubles.StubCLI{
+			Name:                  "az",
+			CanChangeAccountValue: true,
+			AccountEnvironment:    []string{"ARM_CLIENT_ID=client"},
+		}
+		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the command
+		cmd.Execute(t.TempDir(), []string{"init"})
+
+		// THEN: Should export the variable without running the CLI
+		assert.Empty(t, repository.CallHistory)
+		assert.Equal(t, "client", os.Getenv("ARM_CLIENT_ID"))
+	})
+
+	t.Run("should capture the account command output and export the resolved environment", func(t *testing.T) {
+		// GIVEN: A CLI whose account environment is built from a command output
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		settings := entitybuilders.NewSettingsBuilder().WithTerraCloud("aws").BuildSettings()
+		cli := &entitydoubles.StubCLI{
+			Name:                      "aws",
+			CanChangeAccountValue:     true,
+			CommandChangeAccount:      []string{"sts", "assume-role"},
+			CommandAccountEnvironment: []string{"sts", "assume-role", "--output", "json"},
+			AccountEnvironment:        []string{"AWS_ACCESS_KEY_ID=key"},
+		}
+		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
+		outputRepository := &repositorydoubles.StubOutputShellRepository{Output: "{}"}
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, outputRepository)
+
+		// WHEN: Executing the command
+		cmd.Execute(t.TempDir(), []string{"init"})
+
+		// THEN: Should capture the command instead of printing it, and export its result
+		assert.Empty(t, repository.CallHistory)
+		require.Len(t, outputRepository.CallHistory, 1)
+		assert.Equal(t, []string{"sts", "assume-role", "--output", "json"}, outputRepository.CallHistory[0].Arguments)
+		assert.Equal(t, "key", os.Getenv("AWS_ACCESS_KEY_ID"))
+	})
+}
+
 //nolint:gocognit,gocyclo,cyclop // Large test function with comprehensive coverage - complex scenarios
 func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 	t.Parallel()
@@ -133,7 +249,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"init"}
 
@@ -156,7 +272,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"apply", "--all"}
 
@@ -179,7 +295,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan", "--detailed-exitcode", "--all", "--out=plan.out"}
 
@@ -203,14 +319,14 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraTerraformWorkspace("production").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
 		// WHEN: Executing the command
 		cmd.Execute(targetPath, arguments)
 
-		// THEN: Should execute workspace change command (indirectly tests shouldChangeWorkspace)
+		// THEN: Should execute workspace change command (indirectly tests resolveWorkspace)
 		workspaceCommandExecuted := false
 		for _, call := range repository.CallHistory {
 			if call.Command == "terragrunt" && len(call.Arguments) >= 4 &&
@@ -232,14 +348,14 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraTerraformWorkspace("").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
 		// WHEN: Executing the command
 		cmd.Execute(targetPath, arguments)
 
-		// THEN: Should not execute workspace change command (indirectly tests shouldChangeWorkspace)
+		// THEN: Should not execute workspace change command (indirectly tests resolveWorkspace)
 		for _, call := range repository.CallHistory {
 			if call.Command == "terragrunt" && len(call.Arguments) >= 1 &&
 				call.Arguments[0] == "workspace" {
@@ -255,7 +371,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
 		require.NoError(t, os.MkdirAll(filepath.Join(targetPath, ".terraform"), 0o700))
@@ -280,7 +396,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
 		require.NoError(t, os.MkdirAll(filepath.Join(targetPath, ".terragrunt-cache"), 0o700))
@@ -305,7 +421,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
 		require.NoError(t, os.MkdirAll(filepath.Join(targetPath, "terragrunt-cache"), 0o700))
@@ -332,7 +448,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraNoWorkspace(true).
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
@@ -357,7 +473,7 @@ func TestRunAdditionalBeforeCommand_Execute_EnvironmentInit(t *testing.T) {
 			WithTerraNoWorkspace(false).
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
@@ -389,7 +505,7 @@ func TestRunAdditionalBeforeCommand_Execute_CentralizedCache(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		cacheDir := t.TempDir()
 		// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
@@ -415,7 +531,7 @@ func TestRunAdditionalBeforeCommand_Execute_CentralizedCache(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		cacheDir := t.TempDir()
 		t.Setenv("TG_DOWNLOAD_DIR", cacheDir)
@@ -442,7 +558,7 @@ func TestRunAdditionalBeforeCommand_Execute_CentralizedCache(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		t.Setenv("TG_DOWNLOAD_DIR", "")
 		arguments := []string{"plan", "--detailed-exitcode"}
@@ -475,7 +591,7 @@ func TestRunAdditionalBeforeCommand_Execute_CentralizedCache(t *testing.T) {
 			CommandChangeAccount:  []string{"sts", "assume-role"},
 		}
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		t.Setenv("TG_DOWNLOAD_DIR", "")
 		arguments := []string{"apply", "-auto-approve"}
@@ -32,7 +32,7 @@ func TestRunAdditionalBeforeCommand_Execute_AccountChangeError(t *testing.T) {
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{
 			ExecuteErrors: []error{errors.New("account change failed")},
 		}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, cli, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		arguments := []string{"plan"}
 
@@ -63,7 +63,7 @@ func TestRunAdditionalBeforeCommand_Execute_WorkspaceChangeError(t *testing.T) {
 			// First call: init (success), Second call: workspace (fail)
 			ExecuteErrors: []error{nil, errors.New("workspace change failed")},
 		}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		t.Setenv("TG_DOWNLOAD_DIR", "")
 		arguments := []string{"plan"}
@@ -86,7 +86,7 @@ func TestRunAdditionalBeforeCommand_Execute_StateCommand(t *testing.T) {
 			WithTerraCloud("aws").
 			BuildSettings()
 		repository := &repositorydoubles.StubShellRepositoryForAdditional{}
-		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository)
+		cmd := commands.NewRunAdditionalBeforeCommand(settings, nil, repository, &repositorydoubles.StubOutputShellRepository{})
 		targetPath := t.TempDir()
 		t.Setenv("TG_DOWNLOAD_DIR", "")
 		arguments := []string{"import", "null_resource.test", "test-id"}
@@ -48,9 +48,9 @@ func TestRunFromRootCommand_AutoAnswer_Integration(t *testing.T) {
 			&commanddoubles.StubFormatFiles{},
 			&commanddoubles.StubRunAdditionalBefore{},
 			&commanddoubles.StubParallelState{},
-			repositories.NewStdShellRepository(),
-			repositories.NewUpgradeAwareShellRepository(),
-			repositories.NewInteractiveShellRepository(),
+			repositories.NewStdShellRepository(nil),
+			repositories.NewUpgradeAwareShellRepository(nil),
+			repositories.NewInteractiveShellRepository(nil),
 		)
 
 		// WHEN: Executing with reply=y
@@ -96,9 +96,9 @@ func TestRunFromRootCommand_AutoAnswer_Integration(t *testing.T) {
 			&commanddoubles.StubFormatFiles{},
 			&commanddoubles.StubRunAdditionalBefore{},
 			&commanddoubles.StubParallelState{},
-			repositories.NewStdShellRepository(),
-			repositories.NewUpgradeAwareShellRepository(),
-			repositories.NewInteractiveShellRepository(),
+			repositories.NewStdShellRepository(nil),
+			repositories.NewUpgradeAwareShellRepository(nil),
+			repositories.NewInteractiveShellRepository(nil),
 		)
 
 		// WHEN: Executing with reply=n
@@ -144,9 +144,9 @@ func TestRunFromRootCommand_AutoAnswer_Integration(t *testing.T) {
 			&commanddoubles.StubFormatFiles{},
 			&commanddoubles.StubRunAdditionalBefore{},
 			&commanddoubles.StubParallelState{},
-			repositories.NewStdShellRepository(),
-			repositories.NewUpgradeAwareShellRepository(),
-			repositories.NewInteractiveShellRepository(),
+			repositories.NewStdShellRepository(nil),
+			repositories.NewUpgradeAwareShellRepository(nil),
+			repositories.NewInteractiveShellRepository(nil),
 		)
 
 		// WHEN: Executing with boolean reply flag
@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/internal/domain/repositories"
@@ -58,23 +63,53 @@ func (it *RunFromRootCommand) Execute(
 	arguments []string,
 	dependencies []entities.Dependency,
 ) {
+	// The profile and the log format were already applied before the settings were built
+	arguments = RemoveLogFormatFlag(RemoveProfileFlag(arguments))
+
+	// The formatting flags are terra's own and never reach Terragrunt
+	noFormat := it.settings.TerraNoFormat || HasNoFormatFlag(arguments)
+	changedOnly := HasChangedOnlyFlag(arguments)
+	arguments = RemoveFormatFlags(arguments)
+
+	// Re-resolve the project configuration now that the target path is known, so the
+	// .terra.yaml files above the target (not only above the working directory) apply
+	if err := it.settings.LoadProjectConfig(targetPath); err != nil {
+		logger.Fatalf("Failed to load project configuration: %s", err)
+	}
+
 	// Configure centralized cache directories before any Terragrunt invocation
 	it.configureCacheEnvironment()
+	it.configureProfileEnvironment()
+	it.configureEngineEnvironment()
+
+	// Switch to the binaries the repository pins, and refuse to run with binaries violating
+	// its pins or constraints
+	it.selectToolVersions(targetPath, dependencies)
 
 	// Skip formatting for state commands: state operations (mv, rm, etc.) don't modify
 	// source code, so formatting is unnecessary. Skipping it also avoids file contention
-	// when multiple terra processes run concurrently from the same repository.
-	if !IsStateManipulationCommand(arguments) {
-		it.formatCommand.Execute(dependencies)
+	// when multiple terra processes run concurrently from the same repository. Otherwise
+	// only the files the run reads are formatted, not the whole working directory.
+	if !noFormat && !IsStateManipulationCommand(arguments) {
+		it.formatCommand.ExecuteTarget(targetPath, changedOnly, dependencies)
 	}
 
 	// Validate flag combinations before execution
 	it.validateFlagCombinations(arguments, targetPath)
 
+	// Profiles marked with `confirm: true` always ask before changing infrastructure or
+	// state, even when --yes is passed
+	if it.settings.IsProfileConfirmationRequired() &&
+		(IsInteractiveCommand(arguments) || IsStateManipulationCommand(arguments)) {
+		if err := confirmProfile(it.settings.TerraProfile, os.Stdin, stdinIsInteractive()); err != nil {
+			logger.Fatalf("%s", err)
+		}
+	}
+
 	// Check if this is a parallel command (either state command with --all or any command with --parallel=N)
 	if it.isParallelCommand(arguments) {
-		// For parallel commands, skip additional before steps as they don't make sense
-		// when running across multiple directories
+		// For parallel commands, the account and workspace steps run inside each worker,
+		// scoped to the module's own environment, instead of once for the whole tree
 		err := it.parallelState.Execute(targetPath, arguments, dependencies)
 		if err != nil {
 			logger.Fatalf("Parallel command failed: %s", err)
@@ -85,6 +120,15 @@ func (it *RunFromRootCommand) Execute(
 	// Normal execution path for non-parallel commands
 	it.additionalBefore.Execute(targetPath, arguments)
 
+	// The hooks run once the account and workspace are selected, like the command itself
+	hooks := &hookRunner{settings: it.settings, execute: it.repository.ExecuteCommandWithEnvironment}
+	notifier := &runNotifier{settings: it.settings}
+	if err := hooks.before(targetPath, arguments); err != nil {
+		hooks.after(targetPath, arguments, 0, err)
+		notifier.notify(targetPath, arguments, 0, []entities.ModuleResult{entities.NewModuleResult(targetPath, 0, err)})
+		logger.Fatalf("%s", err)
+	}
+
 	// Translate terra confirmation flags (--yes/-y, --no/-n, legacy --reply/-r)
 	// into native Terraform/Terragrunt flags, then strip the terra-level flags.
 	it.warnDeprecatedReplyFlag(arguments)
@@ -94,8 +138,14 @@ func (it *RunFromRootCommand) Execute(
 
 	// Use upgrade-aware repository: automatically detects when init --upgrade
 	// is needed, runs it, and retries the original command.
+	start := time.Now()
 	err := it.upgradeRepository.ExecuteCommandWithUpgrade(
 		"terragrunt", filteredArguments, targetPath)
+	duration := time.Since(start)
+	hooks.after(targetPath, arguments, duration, err)
+	notifier.notify(
+		targetPath, arguments, duration, []entities.ModuleResult{entities.NewModuleResult(targetPath, duration, err)},
+	)
 	if err != nil {
 		logger.Fatalf("Terragrunt command failed: %s", err)
 	}
@@ -393,6 +443,104 @@ func (it *RunFromRootCommand) configureCacheEnvironment() {
 	)
 }
 
+// configureProfileEnvironment exports the active profile's Terraform variables to every
+// Terragrunt invocation. Variables already present in the environment win, the same way
+// environment variables override the project configuration.
+func (it *RunFromRootCommand) configureProfileEnvironment() {
+	for key, value := range it.settings.GetProfileVariables() {
+		if _, found := os.LookupEnv(key); found {
+			logger.Debugf("%s is already set, ignoring the value from profile %q", key, it.settings.TerraProfile)
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			logger.Warnf("Could not set %s: %s", key, err)
+		}
+	}
+}
+
+// configureEngineEnvironment points Terragrunt at the OpenTofu binary when it is the
+// selected engine. A TG_TF_PATH already present in the environment wins.
+func (it *RunFromRootCommand) configureEngineEnvironment() {
+	if it.settings.GetEngine() != entities.EngineTofu {
+		return
+	}
+	if _, found := os.LookupEnv("TG_TF_PATH"); found {
+		logger.Debugf("TG_TF_PATH is already set, not pointing Terragrunt at %s", entities.EngineTofu)
+		return
+	}
+	setOrUnsetEnv("TG_TF_PATH", entities.EngineTofu, false)
+}
+
+// selectToolVersions puts the binary of each version pinned for targetPath in front of the
+// PATH, installing it into the tool cache when missing, and points Terragrunt at the pinned
+// engine (Terraform or OpenTofu) through TG_TF_PATH. It then compares the binaries that will
+// run with the pins and constraints, failing on a mismatch, or only warning when
+// TERRA_VERSION_CHECK=warn.
+// Dependencies without any requirement are not executed.
+func (it *RunFromRootCommand) selectToolVersions(targetPath string, dependencies []entities.Dependency) {
+	for _, dependency := range dependencies {
+		requirement, err := it.settings.ResolveToolVersion(dependency.CLI, targetPath)
+		if err != nil {
+			logger.Fatalf("Failed to resolve the %s version: %s", dependency.Name, err)
+		}
+		if requirement.IsEmpty() {
+			continue
+		}
+		if requirement.Pinned != "" {
+			it.activateToolVersion(dependency, requirement.Pinned)
+		}
+
+		if err = requirement.Check(getCurrentVersion(dependency.CLI)); err != nil {
+			if it.settings.TerraVersionCheck == entities.VersionCheckWarn {
+				logger.Warnf("%s", err)
+				continue
+			}
+			logger.Fatalf("%s. Run 'terra install' to install the required version, "+
+				"or set TERRA_VERSION_CHECK=warn to run anyway.", err)
+		}
+	}
+}
+
+// activateToolVersion makes the cached binary of the given version the one every child
+// process runs.
+func (it *RunFromRootCommand) activateToolVersion(dependency entities.Dependency, version string) {
+	binaryPath := it.installCommand.InstallVersion(dependency, version)
+	if binaryPath == "" {
+		return
+	}
+
+	binaryDir := filepath.Dir(binaryPath)
+	if err := os.Setenv("PATH", binaryDir+string(os.PathListSeparator)+os.Getenv("PATH")); err != nil {
+		logger.Warnf("Could not add %s to PATH: %s", binaryDir, err)
+	}
+	if dependency.Engine != "" {
+		setOrUnsetEnv("TG_TF_PATH", binaryPath, false)
+	}
+	logger.Debugf("Using %s %s from %s", dependency.Name, version, binaryPath)
+}
+
+// confirmProfile asks the operator to type the profile name before a command runs under
+// a profile marked with `confirm: true`. A non-interactive session cannot confirm, so the
+// command is refused instead of being silently approved.
+func confirmProfile(name string, input io.Reader, interactive bool) error {
+	if !interactive {
+		return fmt.Errorf(
+			"profile %q requires an interactive confirmation, but stdin is not a terminal", name)
+	}
+
+	logger.Warnf("Profile %q requires confirmation. Type the profile name to continue: ", name)
+
+	scanner := bufio.NewScanner(input)
+	if !scanner.Scan() {
+		return fmt.Errorf("no confirmation received for profile %q, aborting", name)
+	}
+	if strings.TrimSpace(scanner.Text()) != name {
+		return fmt.Errorf("confirmation did not match profile %q, aborting", name)
+	}
+
+	return nil
+}
+
 // setOrUnsetEnv sets the environment variable to the given value when disabled is false,
 // or unsets it when disabled is true to ensure the opt-out is deterministic.
 func setOrUnsetEnv(key, value string, disabled bool) {
@@ -451,6 +599,11 @@ func (it *RunFromRootCommand) ConfigureCacheEnvironmentPublic() {
 	it.configureCacheEnvironment()
 }
 
+// ConfirmProfilePublic is a public wrapper for testing the private confirmProfile function.
+func ConfirmProfilePublic(name string, input io.Reader, interactive bool) error {
+	return confirmProfile(name, input, interactive)
+}
+
 // HasReplyFlagPublic is a public wrapper for testing the private hasReplyFlag method.
 func (it *RunFromRootCommand) HasReplyFlagPublic(arguments []string) bool {
 	return it.hasReplyFlag(arguments)
@@ -465,3 +618,13 @@ func (it *RunFromRootCommand) GetReplyValuePublic(arguments []string) string {
 func (it *RunFromRootCommand) RemoveReplyFlagPublic(arguments []string) []string {
 	return it.removeReplyFlag(arguments)
 }
+
+// SelectToolVersionsPublic is a public wrapper for testing the private selectToolVersions method.
+func (it *RunFromRootCommand) SelectToolVersionsPublic(targetPath string, dependencies []entities.Dependency) {
+	it.selectToolVersions(targetPath, dependencies)
+}
+
+// ConfigureEngineEnvironmentPublic is a public wrapper for testing the private configureEngineEnvironment method.
+func (it *RunFromRootCommand) ConfigureEngineEnvironmentPublic() {
+	it.configureEngineEnvironment()
+}
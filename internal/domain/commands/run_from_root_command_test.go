@@ -4,6 +4,8 @@ package commands_test
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/commands"
@@ -28,7 +30,7 @@ func TestNewRunFromRootCommand(t *testing.T) {
 		formatCommand := &commanddoubles.StubFormatFiles{}
 		additionalBefore := &commanddoubles.StubRunAdditionalBefore{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 
 		// WHEN: Creating a new RunFromRootCommand
 		cmd := commands.NewRunFromRootCommand(
@@ -58,7 +60,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		additionalBefore := &commanddoubles.StubRunAdditionalBefore{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -90,7 +92,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		assert.False(t, installCommand.ExecuteCalled, "Should not execute install command automatically")
 		assert.Nil(t, installCommand.LastDependencies)
 
-		assert.True(t, formatCommand.ExecuteCalled, "Should execute format command")
+		assert.True(t, formatCommand.ExecuteTargetCalled, "Should execute format command")
 		assert.Equal(t, dependencies, formatCommand.LastDependencies)
 
 		assert.True(t, additionalBefore.ExecuteCalled, "Should execute additional before command")
@@ -112,7 +114,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		additionalBefore := &commanddoubles.StubRunAdditionalBefore{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -133,7 +135,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 
 		// THEN: Should handle empty arguments gracefully
 		assert.False(t, installCommand.ExecuteCalled, "Should not execute install command automatically")
-		assert.True(t, formatCommand.ExecuteCalled, "Should execute format command")
+		assert.True(t, formatCommand.ExecuteTargetCalled, "Should execute format command")
 		assert.True(t, additionalBefore.ExecuteCalled, "Should execute additional before command")
 		assert.Equal(t, 1, upgradeRepository.ExecuteCallCount, "Should execute terragrunt command")
 		assert.Len(
@@ -152,7 +154,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		additionalBefore := &commanddoubles.StubRunAdditionalBefore{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -175,7 +177,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		assert.False(t, installCommand.ExecuteCalled, "Should not execute install command automatically")
 		assert.Nil(t, installCommand.LastDependencies)
 
-		assert.True(t, formatCommand.ExecuteCalled, "Should execute format command")
+		assert.True(t, formatCommand.ExecuteTargetCalled, "Should execute format command")
 		assert.Equal(t, dependencies, formatCommand.LastDependencies)
 
 		assert.Equal(t, 1, upgradeRepository.ExecuteCallCount, "Should execute terragrunt command")
@@ -189,7 +191,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		additionalBefore := &commanddoubles.StubRunAdditionalBefore{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -221,7 +223,7 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		additionalBefore := &commanddoubles.StubRunAdditionalBefore{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -398,6 +400,64 @@ func TestRunFromRootCommand_Execute(t *testing.T) {
 		)
 		assert.Equal(t, 0, interactiveRepository.ExecuteWithAnswerCallCount, "PTY path is no longer used")
 	})
+
+	t.Run("should format the changed files of the target path when --changed-only is used", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A plan asking to format only the changed files
+		formatCommand := &commanddoubles.StubFormatFiles{}
+		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
+		cmd := commands.NewRunFromRootCommand(
+			entitybuilders.NewSettingsBuilder().BuildSettings(),
+			&commanddoubles.StubInstallDependencies{},
+			formatCommand,
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			&repositorydoubles.StubShellRepositoryForRoot{},
+			upgradeRepository,
+			&repositorydoubles.StubInteractiveShellRepository{},
+		)
+
+		// WHEN: Executing the command
+		cmd.Execute("/test/path", []string{"plan", "--changed-only"}, []entities.Dependency{})
+
+		// THEN: Should scope the formatting and keep the flag away from terragrunt
+		assert.True(t, formatCommand.ExecuteTargetCalled)
+		assert.Equal(t, "/test/path", formatCommand.LastTargetPath)
+		assert.True(t, formatCommand.LastChangedOnly)
+		assert.Equal(t, []string{"plan"}, upgradeRepository.LastArguments)
+	})
+
+	t.Run("should not format when --no-format or TERRA_NO_FORMAT is used", func(t *testing.T) {
+		t.Parallel()
+		for _, testCase := range []struct {
+			settings  *entities.Settings
+			arguments []string
+		}{
+			{entitybuilders.NewSettingsBuilder().BuildSettings(), []string{"plan", "--no-format"}},
+			{entitybuilders.NewSettingsBuilder().WithTerraNoFormat(true).BuildSettings(), []string{"plan"}},
+		} {
+			// GIVEN: A plan with the formatting disabled
+			formatCommand := &commanddoubles.StubFormatFiles{}
+			upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
+			cmd := commands.NewRunFromRootCommand(
+				testCase.settings,
+				&commanddoubles.StubInstallDependencies{},
+				formatCommand,
+				&commanddoubles.StubRunAdditionalBefore{},
+				&commanddoubles.StubParallelState{},
+				&repositorydoubles.StubShellRepositoryForRoot{},
+				upgradeRepository,
+				&repositorydoubles.StubInteractiveShellRepository{},
+			)
+
+			// WHEN: Executing the command
+			cmd.Execute("/test/path", testCase.arguments, []entities.Dependency{})
+
+			// THEN: Should skip the formatting and still run the plan
+			assert.False(t, formatCommand.ExecuteTargetCalled, "arguments: %v", testCase.arguments)
+			assert.Equal(t, []string{"plan"}, upgradeRepository.LastArguments)
+		}
+	})
 }
 
 func TestRunFromRootCommand_hasReplyFlag(t *testing.T) {
@@ -1180,3 +1240,108 @@ func TestSettings_GetProviderCacheDir(t *testing.T) {
 		assert.Contains(t, dir, ".cache/terra/providers")
 	})
 }
+
+func TestRunFromRootCommand_ExecuteWithProfile(t *testing.T) {
+	t.Run("should strip --profile and export the profile variables when a profile is selected", func(t *testing.T) {
+		// GIVEN: A target path whose config declares the selected profile
+		t.Setenv("TERRA_PROFILE", "dev")
+		t.Setenv("TF_VAR_region", "")
+		require.NoError(t, os.Unsetenv("TF_VAR_region"))
+		targetPath := t.TempDir()
+		require.NoError(t, os.WriteFile(
+			filepath.Join(targetPath, entities.ProjectConfigFileName),
+			[]byte("profiles:\n  dev:\n    workspace: dev\n    variables:\n      region: eu-west-1\n"),
+			0o600,
+		))
+		settings := entitybuilders.NewSettingsBuilder().BuildSettings()
+		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
+		cmd := commands.NewRunFromRootCommand(
+			settings,
+			&commanddoubles.StubInstallDependencies{},
+			&commanddoubles.StubFormatFiles{},
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			&repositorydoubles.StubShellRepositoryForRoot{},
+			upgradeRepository,
+			infrastructure_repositories.NewInteractiveShellRepository(nil),
+		)
+
+		// WHEN: Executing a command with the --profile flag
+		cmd.Execute(targetPath, []string{"--profile=dev", "plan"}, []entities.Dependency{})
+
+		// THEN: Should forward the command without the flag under the profile's settings
+		assert.Equal(t, []string{"plan"}, upgradeRepository.LastArguments)
+		assert.Equal(t, "dev", settings.TerraTerraformWorkspace)
+		assert.Equal(t, "eu-west-1", os.Getenv("TF_VAR_region"))
+	})
+
+	t.Run("should keep a TF_VAR already present in the environment", func(t *testing.T) {
+		// GIVEN: A profile variable that is also set in the environment
+		t.Setenv("TERRA_PROFILE", "dev")
+		t.Setenv("TF_VAR_region", "us-east-1")
+		targetPath := t.TempDir()
+		require.NoError(t, os.WriteFile(
+			filepath.Join(targetPath, entities.ProjectConfigFileName),
+			[]byte("profiles:\n  dev:\n    variables:\n      region: eu-west-1\n"),
+			0o600,
+		))
+		cmd := commands.NewRunFromRootCommand(
+			entitybuilders.NewSettingsBuilder().BuildSettings(),
+			&commanddoubles.StubInstallDependencies{},
+			&commanddoubles.StubFormatFiles{},
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			&repositorydoubles.StubShellRepositoryForRoot{},
+			&repositorydoubles.StubUpgradeShellRepository{},
+			infrastructure_repositories.NewInteractiveShellRepository(nil),
+		)
+
+		// WHEN: Executing a command under the profile
+		cmd.Execute(targetPath, []string{"plan"}, []entities.Dependency{})
+
+		// THEN: Should leave the environment value in place
+		assert.Equal(t, "us-east-1", os.Getenv("TF_VAR_region"))
+	})
+}
+
+func TestConfirmProfile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should confirm when the profile name is typed", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An interactive session answering with the profile name
+		input := strings.NewReader("prod\n")
+
+		// WHEN: Confirming the profile
+		err := commands.ConfirmProfilePublic("prod", input, true)
+
+		// THEN: Should accept the confirmation
+		require.NoError(t, err)
+	})
+
+	t.Run("should refuse when the answer does not match the profile name", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An interactive session answering "y"
+		input := strings.NewReader("y\n")
+
+		// WHEN: Confirming the profile
+		err := commands.ConfirmProfilePublic("prod", input, true)
+
+		// THEN: Should abort
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not match")
+	})
+
+	t.Run("should refuse when stdin is not a terminal", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A non-interactive session, even with the right answer piped in
+		input := strings.NewReader("prod\n")
+
+		// WHEN: Confirming the profile
+		err := commands.ConfirmProfilePublic("prod", input, false)
+
+		// THEN: Should refuse instead of approving silently
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "interactive confirmation")
+	})
+}
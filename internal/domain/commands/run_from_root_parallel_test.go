@@ -23,7 +23,7 @@ func TestRunFromRootCommand_ExecuteParallelState(t *testing.T) {
 		parallelState := &commanddoubles.StubParallelState{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -43,7 +43,7 @@ func TestRunFromRootCommand_ExecuteParallelState(t *testing.T) {
 		cmd.Execute(targetPath, arguments, dependencies)
 
 		// THEN: Should NOT execute format command (state commands skip formatting)
-		assert.False(t, formatCommand.ExecuteCalled, "Should not execute format command for state commands")
+		assert.False(t, formatCommand.ExecuteTargetCalled, "Should not execute format command for state commands")
 		assert.True(t, additionalBefore.ExecuteCalled, "Should execute additional before command")
 		assert.Equal(t, 1, upgradeRepository.ExecuteCallCount, "Should execute normal terragrunt command")
 
@@ -59,7 +59,7 @@ func TestRunFromRootCommand_ExecuteParallelState(t *testing.T) {
 		parallelState := &commanddoubles.StubParallelState{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
@@ -79,7 +79,7 @@ func TestRunFromRootCommand_ExecuteParallelState(t *testing.T) {
 		cmd.Execute(targetPath, arguments, dependencies)
 
 		// THEN: Should execute normal flow
-		assert.True(t, formatCommand.ExecuteCalled, "Should execute format command")
+		assert.True(t, formatCommand.ExecuteTargetCalled, "Should execute format command")
 		assert.True(t, additionalBefore.ExecuteCalled, "Should execute additional before command")
 		assert.Equal(t, 1, upgradeRepository.ExecuteCallCount, "Should execute normal terragrunt command")
 
@@ -95,7 +95,7 @@ func TestRunFromRootCommand_ExecuteParallelState(t *testing.T) {
 		parallelState := &commanddoubles.StubParallelState{}
 		repository := &repositorydoubles.StubShellRepositoryForRoot{}
 		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
-		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository()
+		interactiveRepository := infrastructure_repositories.NewInteractiveShellRepository(nil)
 		cmd := commands.NewRunFromRootCommand(
 			entitybuilders.NewSettingsBuilder().BuildSettings(),
 			installCommand,
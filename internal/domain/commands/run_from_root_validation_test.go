@@ -3,6 +3,8 @@
 package commands_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/rios0rios0/terra/test/domain/commanddoubles"
 	"github.com/rios0rios0/terra/test/domain/entitybuilders"
 	"github.com/rios0rios0/terra/test/infrastructure/repositorydoubles"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
 	logger "github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
@@ -34,11 +37,17 @@ func setupFatalInterceptor() (*test.Hook, func()) {
 // newRunFromRootForValidation creates a RunFromRootCommand with stub dependencies
 // suitable for testing validation paths.
 func newRunFromRootForValidation() *commands.RunFromRootCommand {
-	return commands.NewRunFromRootCommand(
+	return newRunFromRootWithSettings(
 		entitybuilders.NewSettingsBuilder().
 			WithTerraModuleCacheDir("/tmp/terra-test-modules").
 			WithTerraProviderCacheDir("/tmp/terra-test-providers").
 			BuildSettings(),
+	)
+}
+
+func newRunFromRootWithSettings(settings *entities.Settings) *commands.RunFromRootCommand {
+	return commands.NewRunFromRootCommand(
+		settings,
 		&commanddoubles.StubInstallDependencies{},
 		&commanddoubles.StubFormatFiles{},
 		&commanddoubles.StubRunAdditionalBefore{},
@@ -811,3 +820,152 @@ func TestRunFromRootCommand_Execute_terragruntFails(t *testing.T) {
 		assert.Contains(t, lastEntry.Message, "Terragrunt command failed")
 	})
 }
+
+func TestRunFromRootCommand_selectToolVersions(t *testing.T) {
+	t.Run("should fatalf when the installed binary differs from the pinned version", func(t *testing.T) {
+		// GIVEN: terraform 1.0.0 installed while the repository pins 1.9.5
+		hook, cleanup := setupFatalInterceptor()
+		defer cleanup()
+		t.Setenv("PATH", repositoryhelpers.HelperCreateMockTerraformBinary(t, "1.0.0")+
+			string(os.PathListSeparator)+os.Getenv("PATH"))
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".terraform-version"), []byte("1.9.5\n"), 0o600))
+		cmd := newRunFromRootForValidation()
+		dependency := entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency()
+
+		// WHEN: Checking the tool versions for the repository
+		cmd.SelectToolVersionsPublic(root, []entities.Dependency{dependency})
+
+		// THEN: Should fail naming both versions
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logger.FatalLevel, hook.LastEntry().Level)
+		assert.Contains(t, hook.LastEntry().Message, "terraform 1.0.0 is installed")
+		assert.Contains(t, hook.LastEntry().Message, "pins 1.9.5")
+	})
+
+	t.Run("should only warn when TERRA_VERSION_CHECK is warn", func(t *testing.T) {
+		// GIVEN: A constraint the installed binary violates and the warn mode
+		hook, cleanup := setupFatalInterceptor()
+		defer cleanup()
+		t.Setenv("PATH", repositoryhelpers.HelperCreateMockTerraformBinary(t, "1.0.0")+
+			string(os.PathListSeparator)+os.Getenv("PATH"))
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "terragrunt.hcl"),
+			[]byte("terraform_version_constraint = \">= 1.5\"\n"), 0o600))
+		settings := entitybuilders.NewSettingsBuilder().BuildSettings()
+		settings.TerraVersionCheck = entities.VersionCheckWarn
+		cmd := commands.NewRunFromRootCommand(
+			settings,
+			&commanddoubles.StubInstallDependencies{},
+			&commanddoubles.StubFormatFiles{},
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			&repositorydoubles.StubShellRepositoryForRoot{},
+			&repositorydoubles.StubUpgradeShellRepository{},
+			&repositorydoubles.StubInteractiveShellRepository{},
+		)
+		dependency := entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency()
+
+		// WHEN: Checking the tool versions for the repository
+		cmd.SelectToolVersionsPublic(root, []entities.Dependency{dependency})
+
+		// THEN: Should warn about the constraint without failing
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logger.WarnLevel, hook.LastEntry().Level)
+		assert.Contains(t, hook.LastEntry().Message, `requires ">= 1.5"`)
+	})
+
+	t.Run("should not run the binaries when nothing pins or constrains them", func(t *testing.T) {
+		// GIVEN: A repository without version files or constraints
+		hook, cleanup := setupFatalInterceptor()
+		defer cleanup()
+		cmd := newRunFromRootForValidation()
+		dependency := entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency()
+
+		// WHEN: Checking the tool versions
+		cmd.SelectToolVersionsPublic(t.TempDir(), []entities.Dependency{dependency})
+
+		// THEN: Should log nothing
+		assert.Empty(t, hook.AllEntries())
+	})
+}
+
+func TestRunFromRootCommand_selectToolVersions_activation(t *testing.T) {
+	t.Run("should put the pinned binaries in front of the PATH and set TG_TF_PATH", func(t *testing.T) {
+		// GIVEN: A repository pinning terraform 1.0.0, whose cached binary reports that version
+		hook, cleanup := setupFatalInterceptor()
+		defer cleanup()
+		cachedDir := repositoryhelpers.HelperCreateMockTerraformBinary(t, "1.0.0")
+		t.Setenv("PATH", os.Getenv("PATH"))
+		t.Setenv("TG_TF_PATH", "")
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".terraform-version"), []byte("1.0.0\n"), 0o600))
+		installCommand := &commanddoubles.StubInstallDependencies{
+			BinaryPaths: map[string]string{"terraform@1.0.0": filepath.Join(cachedDir, "terraform")},
+		}
+		cmd := commands.NewRunFromRootCommand(
+			entitybuilders.NewSettingsBuilder().BuildSettings(),
+			installCommand,
+			&commanddoubles.StubFormatFiles{},
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			&repositorydoubles.StubShellRepositoryForRoot{},
+			&repositorydoubles.StubUpgradeShellRepository{},
+			&repositorydoubles.StubInteractiveShellRepository{},
+		)
+		dependency := entitybuilders.NewDependencyBuilder().
+			WithName("Terraform").WithCLI("terraform").WithEngine(entities.EngineTerraform).BuildDependency()
+
+		// WHEN: Selecting the tool versions for the repository
+		cmd.SelectToolVersionsPublic(root, []entities.Dependency{dependency})
+
+		// THEN: Should switch to the cached binary and pass the version check
+		assert.Equal(t, []string{"terraform@1.0.0"}, installCommand.InstalledVersions)
+		assert.True(t, strings.HasPrefix(os.Getenv("PATH"), cachedDir+string(os.PathListSeparator)))
+		assert.Equal(t, filepath.Join(cachedDir, "terraform"), os.Getenv("TG_TF_PATH"))
+		for _, entry := range hook.AllEntries() {
+			assert.NotEqual(t, logger.FatalLevel, entry.Level, entry.Message)
+		}
+	})
+}
+
+func TestRunFromRootCommand_configureEngineEnvironment(t *testing.T) {
+	t.Run("should point terragrunt at tofu when OpenTofu is the engine", func(t *testing.T) {
+		// GIVEN: Settings selecting OpenTofu and no TG_TF_PATH in the environment
+		t.Setenv("TG_TF_PATH", "")
+		require.NoError(t, os.Unsetenv("TG_TF_PATH"))
+		cmd := newRunFromRootWithSettings(entitybuilders.NewSettingsBuilder().WithTerraEngine(entities.EngineTofu).BuildSettings())
+
+		// WHEN: Configuring the engine environment
+		cmd.ConfigureEngineEnvironmentPublic()
+
+		// THEN: Should export TG_TF_PATH=tofu
+		assert.Equal(t, "tofu", os.Getenv("TG_TF_PATH"))
+	})
+
+	t.Run("should keep TG_TF_PATH when it is already set", func(t *testing.T) {
+		// GIVEN: Settings selecting OpenTofu and a TG_TF_PATH chosen by the operator
+		t.Setenv("TG_TF_PATH", "/opt/tofu/bin/tofu")
+		cmd := newRunFromRootWithSettings(entitybuilders.NewSettingsBuilder().WithTerraEngine(entities.EngineTofu).BuildSettings())
+
+		// WHEN: Configuring the engine environment
+		cmd.ConfigureEngineEnvironmentPublic()
+
+		// THEN: Should leave it untouched
+		assert.Equal(t, "/opt/tofu/bin/tofu", os.Getenv("TG_TF_PATH"))
+	})
+
+	t.Run("should not set TG_TF_PATH when terraform is the engine", func(t *testing.T) {
+		// GIVEN: Default settings and no TG_TF_PATH in the environment
+		t.Setenv("TG_TF_PATH", "")
+		require.NoError(t, os.Unsetenv("TG_TF_PATH"))
+		cmd := newRunFromRootWithSettings(entitybuilders.NewSettingsBuilder().BuildSettings())
+
+		// WHEN: Configuring the engine environment
+		cmd.ConfigureEngineEnvironmentPublic()
+
+		// THEN: Should leave TG_TF_PATH unset
+		_, found := os.LookupEnv("TG_TF_PATH")
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+)
+
+// hookExecutor runs a command in directory with the extra "KEY=VALUE" environment entries.
+type hookExecutor func(command string, arguments []string, directory string, environment []string) error
+
+// hookRunner runs the shell commands declared under `hooks:` in the project configuration
+// around the command run in a module. The single and the parallel runs differ only in how
+// the hooks are executed: on the terminal, or through the worker's prefixed output and
+// along with the module's own environment.
+type hookRunner struct {
+	settings *entities.Settings
+	execute  hookExecutor
+}
+
+// before runs the before_<command> hooks in the module, stopping at the first failing one,
+// which fails the module's command too.
+func (it *hookRunner) before(modulePath string, arguments []string) error {
+	command := extractSubcommand(arguments)
+	return it.run(entities.HookContext{
+		Hook:       entities.HookBeforePrefix + strings.ReplaceAll(command, " ", "_"),
+		Command:    command,
+		ModulePath: modulePath,
+	})
+}
+
+// after runs the after_<command> hooks in the module when the command succeeded, or the
+// on_failure hooks when it (or a before hook) failed. Their failures are only logged: the
+// command already ran, and its result is what the run reports.
+func (it *hookRunner) after(modulePath string, arguments []string, duration time.Duration, commandErr error) {
+	command := extractSubcommand(arguments)
+	context := entities.HookContext{
+		Hook:       entities.HookAfterPrefix + strings.ReplaceAll(command, " ", "_"),
+		Command:    command,
+		ModulePath: modulePath,
+		Finished:   true,
+		ExitCode:   entities.ExitCode(commandErr),
+		Duration:   duration,
+	}
+	if commandErr != nil {
+		context.Hook = entities.HookOnFailure
+	}
+
+	if err := it.run(context); err != nil {
+		logger.Warnf("%s", err)
+	}
+}
+
+func (it *hookRunner) run(context entities.HookContext) error {
+	if context.Command == "" {
+		return nil
+	}
+	for _, hook := range it.settings.GetHooks(context.Hook) {
+		logger.Infof("Running the %s hook in %s", context.Hook, context.ModulePath)
+		shell, arguments := entities.HookShellCommand(hook)
+		if err := it.execute(shell, arguments, context.ModulePath, context.Environment()); err != nil {
+			return fmt.Errorf("the %s hook %q failed in %s: %w", context.Hook, hook, context.ModulePath, err)
+		}
+	}
+	return nil
+}
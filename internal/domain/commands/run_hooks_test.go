@@ -0,0 +1,131 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+	"github.com/rios0rios0/terra/test/infrastructure/repositorydoubles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperLoadHooks(t *testing.T, directory, hooks string) *entities.Settings {
+	t.Helper()
+	require.NoError(t, writeFile(filepath.Join(directory, entities.ProjectConfigFileName), "hooks:\n"+hooks))
+	settings := &entities.Settings{}
+	require.NoError(t, settings.LoadProjectConfig(directory))
+	return settings
+}
+
+func TestParallelStateCommand_Hooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run the before and after hooks around the command of every module", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Two modules and hooks declared before and after plan
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1", "mod2"})
+		settings := helperLoadHooks(t, tempDir, "  before_plan: tflint\n  after_plan: ./notify.sh\n  after_apply: never\n")
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the plan in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=2"}, []entities.Dependency{})
+
+		// THEN: Each module should run tflint, the plan and the notification, in that order
+		require.NoError(t, err)
+		require.Len(t, repository.CallHistory, 6)
+		calls := map[string][]string{}
+		for _, call := range repository.CallHistory {
+			calls[call.Prefix] = append(calls[call.Prefix], call.Arguments[len(call.Arguments)-1])
+			if call.Command == "sh" {
+				assert.Contains(t, call.Environment, "TERRA_COMMAND=plan")
+				assert.Contains(t, call.Environment, "TERRA_MODULE_PATH="+call.Directory)
+			}
+		}
+		assert.Equal(t, []string{"tflint", "plan", "./notify.sh"}, calls["mod1"])
+		assert.Equal(t, []string{"tflint", "plan", "./notify.sh"}, calls["mod2"])
+	})
+
+	t.Run("should run the on_failure hooks with the exit code when the command fails", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module whose command fails and hooks for success and failure
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1"})
+		settings := helperLoadHooks(t, tempDir, "  after_apply: ./notify.sh\n  on_failure: ./page.sh\n")
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{ShouldFail: true}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the apply in parallel
+		err := cmd.Execute(tempDir, []string{"apply", "--parallel=1", "--yes"}, []entities.Dependency{})
+
+		// THEN: Should fail and page instead of notifying
+		require.Error(t, err)
+		require.Len(t, repository.CallHistory, 2)
+		hook := repository.CallHistory[1]
+		assert.Equal(t, []string{"-c", "./page.sh"}, hook.Arguments)
+		assert.Contains(t, hook.Environment, "TERRA_HOOK=on_failure")
+		assert.Contains(t, hook.Environment, "TERRA_EXIT_CODE=-1")
+	})
+
+	t.Run("should skip the command of a module when its before hook fails", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A before hook that fails, as tflint does on findings
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1"})
+		settings := helperLoadHooks(t, tempDir, "  before_plan: tflint\n")
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{ShouldFail: true}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Executing the plan in parallel
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=1"}, []entities.Dependency{})
+
+		// THEN: Should fail the module without running terragrunt
+		require.Error(t, err)
+		require.Len(t, repository.CallHistory, 1)
+		assert.Equal(t, "sh", repository.CallHistory[0].Command)
+	})
+}
+
+func TestRunFromRootCommand_Hooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run the hooks of the command in the target path around terragrunt", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A target path declaring hooks before and after apply
+		targetPath := t.TempDir()
+		require.NoError(t, writeFile(filepath.Join(targetPath, entities.ProjectConfigFileName),
+			"hooks:\n  before_apply: ./snapshot-state.sh\n  after_apply: ./notify.sh\n  before_plan: tflint\n"))
+		repository := &repositorydoubles.StubShellRepositoryForRoot{}
+		upgradeRepository := &repositorydoubles.StubUpgradeShellRepository{}
+		cmd := commands.NewRunFromRootCommand(
+			&entities.Settings{},
+			&commanddoubles.StubInstallDependencies{},
+			&commanddoubles.StubFormatFiles{},
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			repository,
+			upgradeRepository,
+			&repositorydoubles.StubInteractiveShellRepository{},
+		)
+
+		// WHEN: Applying the target path
+		cmd.Execute(targetPath, []string{"apply", "--yes"}, []entities.Dependency{})
+
+		// THEN: Should run the apply hooks in the target path and terragrunt once
+		require.Len(t, repository.CallHistory, 2)
+		assert.Equal(t, []string{"-c", "./snapshot-state.sh"}, repository.CallHistory[0].Arguments)
+		assert.Equal(t, []string{"-c", "./notify.sh"}, repository.CallHistory[1].Arguments)
+		assert.Equal(t, targetPath, repository.CallHistory[1].Directory)
+		assert.Contains(t, repository.CallHistory[1].Environment, "TERRA_HOOK=after_apply")
+		assert.Equal(t, 1, upgradeRepository.ExecuteCallCount)
+		_, exported := os.LookupEnv("TERRA_HOOK")
+		assert.False(t, exported, "the hook variables must reach the hook process only")
+	})
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"slices"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+)
+
+// runNotifier posts the summary of a finished run to the webhooks configured by
+// TERRA_WEBHOOK_URLS, so that long unattended runs report when they finish or fail.
+type runNotifier struct {
+	settings *entities.Settings
+}
+
+// notify sends the summary of the command run in targetPath to every webhook. A webhook
+// failing is only logged: the run already finished, and its result is what terra reports.
+func (it *runNotifier) notify(
+	targetPath string,
+	arguments []string,
+	duration time.Duration,
+	modules []entities.ModuleResult,
+) {
+	command := extractSubcommand(arguments)
+	if len(it.settings.TerraWebhookURLs) == 0 || command == "" {
+		return
+	}
+	if len(it.settings.TerraWebhookCommands) > 0 && !slices.Contains(it.settings.TerraWebhookCommands, command) {
+		logger.Debugf("Not notifying the webhooks of %q, which is not in TERRA_WEBHOOK_COMMANDS", command)
+		return
+	}
+
+	summary := entities.NewRunSummary(command, targetPath, duration, modules)
+	for _, url := range it.settings.TerraWebhookURLs {
+		format := it.settings.TerraWebhookFormat
+		if format == "" {
+			format = entities.DetectWebhookFormat(url)
+		}
+
+		payload, err := entities.BuildWebhookPayload(format, summary)
+		if err == nil {
+			err = entities.PostWebhook(url, payload)
+		}
+		if err != nil {
+			logger.Warnf("Failed to notify the webhook at %s: %s", entities.WebhookHost(url), err)
+			continue
+		}
+		logger.Debugf("Notified the webhook at %s (%s)", entities.WebhookHost(url), format)
+	}
+}
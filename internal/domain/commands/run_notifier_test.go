@@ -0,0 +1,127 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+	"github.com/rios0rios0/terra/test/infrastructure/repositorydoubles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helperWebhookServer starts a webhook stand-in returning the summaries it received.
+func helperWebhookServer(t *testing.T) (string, func() []entities.RunSummary) {
+	t.Helper()
+	var mu sync.Mutex
+	var summaries []entities.RunSummary
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var summary entities.RunSummary
+		if err := json.NewDecoder(r.Body).Decode(&summary); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		summaries = append(summaries, summary)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, func() []entities.RunSummary {
+		mu.Lock()
+		defer mu.Unlock()
+		return summaries
+	}
+}
+
+func TestParallelStateCommand_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post one summary with the status of every module when the run finishes", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Two modules and a webhook
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1", "mod2"})
+		url, received := helperWebhookServer(t)
+		settings := &entities.Settings{TerraWebhookURLs: []string{url}}
+		repository := &repositorydoubles.StubShellRepositoryForParallelState{ShouldFail: true}
+		cmd := commands.NewParallelStateCommand(settings, repository, &repositorydoubles.StubOutputShellRepository{})
+
+		// WHEN: Applying both modules, which fail
+		err := cmd.Execute(tempDir, []string{"apply", "--parallel=2", "--yes"}, []entities.Dependency{})
+
+		// THEN: Should post the failed run once, with both modules in path order
+		require.Error(t, err)
+		summaries := received()
+		require.Len(t, summaries, 1)
+		assert.Equal(t, "apply", summaries[0].Command)
+		assert.Equal(t, tempDir, summaries[0].TargetPath)
+		assert.Equal(t, entities.RunStatusFailed, summaries[0].Status)
+		assert.Equal(t, 2, summaries[0].Failed)
+		require.Len(t, summaries[0].Modules, 2)
+		assert.Equal(t, filepath.Join(tempDir, "mod1"), summaries[0].Modules[0].Path)
+		assert.Equal(t, entities.RunStatusFailed, summaries[0].Modules[1].Status)
+	})
+
+	t.Run("should not post when the command is not one of the notified commands", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A webhook notified of applies only
+		tempDir := t.TempDir()
+		newTestDirectoryHelper(t).createModuleDirectories(tempDir, []string{"mod1"})
+		url, received := helperWebhookServer(t)
+		settings := &entities.Settings{TerraWebhookURLs: []string{url}, TerraWebhookCommands: []string{"apply"}}
+		cmd := commands.NewParallelStateCommand(
+			settings, &repositorydoubles.StubShellRepositoryForParallelState{}, &repositorydoubles.StubOutputShellRepository{},
+		)
+
+		// WHEN: Planning the module
+		err := cmd.Execute(tempDir, []string{"plan", "--parallel=1"}, []entities.Dependency{})
+
+		// THEN: Should stay silent
+		require.NoError(t, err)
+		assert.Empty(t, received())
+	})
+}
+
+func TestRunFromRootCommand_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post the summary of a single run with the target path as its module", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A webhook, and a webhook that is down which must not fail the run
+		targetPath := t.TempDir()
+		url, received := helperWebhookServer(t)
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+		settings := &entities.Settings{TerraWebhookURLs: []string{down.URL, url}}
+		cmd := commands.NewRunFromRootCommand(
+			settings,
+			&commanddoubles.StubInstallDependencies{},
+			&commanddoubles.StubFormatFiles{},
+			&commanddoubles.StubRunAdditionalBefore{},
+			&commanddoubles.StubParallelState{},
+			&repositorydoubles.StubShellRepositoryForRoot{},
+			&repositorydoubles.StubUpgradeShellRepository{},
+			&repositorydoubles.StubInteractiveShellRepository{},
+		)
+
+		// WHEN: Planning the target path
+		cmd.Execute(targetPath, []string{"plan"}, []entities.Dependency{})
+
+		// THEN: Should post the succeeded run to the webhook that is up
+		summaries := received()
+		require.Len(t, summaries, 1)
+		assert.Equal(t, "plan", summaries[0].Command)
+		assert.Equal(t, entities.RunStatusSucceeded, summaries[0].Status)
+		require.Len(t, summaries[0].Modules, 1)
+		assert.Equal(t, targetPath, summaries[0].Modules[0].Path)
+	})
+}
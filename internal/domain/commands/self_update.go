@@ -2,4 +2,5 @@ package commands
 
 type SelfUpdate interface {
 	Execute(dryRun, force bool) error
+	Rollback() error
 }
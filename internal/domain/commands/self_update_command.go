@@ -1,6 +1,19 @@
 package commands
 
-import "github.com/rios0rios0/cliforge/pkg/selfupdate"
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rios0rios0/cliforge/pkg/selfupdate"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+)
+
+// terraLatestReleaseURL is the release the self-update installs, read again to record its
+// version, since the terra binary prints its version through the logs only.
+const terraLatestReleaseURL = "https://api.github.com/repos/rios0rios0/terra/releases/latest"
 
 type SelfUpdateCommand struct{}
 
@@ -8,7 +21,125 @@ func NewSelfUpdateCommand() *SelfUpdateCommand {
 	return &SelfUpdateCommand{}
 }
 
+// Execute updates terra in place. The running binary is copied aside first and, when the
+// update replaced it, kept as terra.prev for `terra self-update --rollback`.
 func (c *SelfUpdateCommand) Execute(dryRun, force bool) error {
 	cmd := selfupdate.NewCommand("rios0rios0", "terra", "terra", TerraVersion)
-	return cmd.Execute(dryRun, force)
+	if dryRun {
+		return cmd.Execute(dryRun, force)
+	}
+
+	executable, err := currentExecutable()
+	if err != nil {
+		return err
+	}
+	snapshot, err := snapshotBinary(executable)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(snapshot)
+
+	if err = cmd.Execute(dryRun, force); err != nil {
+		return err
+	}
+
+	// Declining the update, or already running the latest version, leaves the binary as is
+	before, err := os.ReadFile(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", snapshot, err)
+	}
+	after, err := os.ReadFile(executable)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", executable, err)
+	}
+	if bytes.Equal(before, after) {
+		return nil
+	}
+
+	if err = os.Rename(snapshot, executable+entities.PreviousBinarySuffix); err != nil {
+		logger.Warnf("Failed to keep the previous terra binary: %s", err)
+	}
+	version, err := fetchLatestVersion(terraLatestReleaseURL, `"tag_name":"v?([^"]+)"`)
+	if err != nil {
+		logger.Warnf("Failed to determine the version terra was updated to: %s", err)
+	}
+	recordInstall(entities.InstallRecord{
+		Action:   entities.InstallActionSelfUpdate,
+		Tool:     "terra",
+		Version:  version,
+		Previous: TerraVersion,
+		Path:     executable,
+	})
+	return nil
+}
+
+// Rollback restores the terra binary replaced by the last self-update, keeping the
+// current one in its place so that rolling back again undoes the rollback.
+func (c *SelfUpdateCommand) Rollback() error {
+	executable, err := currentExecutable()
+	if err != nil {
+		return err
+	}
+	if err = entities.SwapPreviousBinary(executable); err != nil {
+		return fmt.Errorf("failed to roll back terra: %w", err)
+	}
+
+	restoredVersion := previousTerraVersion(executable)
+	logger.Infof("Rolled terra back to %s at %s", displayVersion(restoredVersion), executable)
+	recordInstall(entities.InstallRecord{
+		Action:   entities.InstallActionRollback,
+		Tool:     "terra",
+		Version:  restoredVersion,
+		Previous: TerraVersion,
+		Path:     executable,
+	})
+	return nil
+}
+
+// previousTerraVersion returns the version of the terra binary kept aside at executable,
+// which the last self-update or rollback recorded as the one it replaced.
+func previousTerraVersion(executable string) string {
+	record, found, err := entities.LastInstallRecord("terra", executable)
+	if err != nil {
+		logger.Warnf("Failed to read the install history: %s", err)
+	}
+	if !found {
+		return ""
+	}
+	return record.Previous
+}
+
+// currentExecutable returns the path of the running terra binary, with symlinks resolved.
+func currentExecutable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the terra binary: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", executable, err)
+	}
+	return resolved, nil
+}
+
+// snapshotBinary copies the binary next to itself, so that it can later be renamed to
+// its previous-binary path without crossing filesystems.
+func snapshotBinary(executable string) (string, error) {
+	snapshot, err := os.CreateTemp(filepath.Dir(executable), filepath.Base(executable)+"_*")
+	if err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", executable, err)
+	}
+	snapshotPath := snapshot.Name()
+	_ = snapshot.Close()
+
+	if err = copyFile(executable, snapshotPath); err != nil {
+		_ = os.Remove(snapshotPath)
+		return "", fmt.Errorf("failed to back up %s: %w", executable, err)
+	}
+	// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
+	if err = os.Chmod(snapshotPath, 0o755); err != nil { //nolint:gosec // the backup must stay executable
+		_ = os.Remove(snapshotPath)
+		return "", fmt.Errorf("failed to back up %s: %w", executable, err)
+	}
+	return snapshotPath, nil
 }
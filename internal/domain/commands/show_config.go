@@ -0,0 +1,7 @@
+package commands
+
+import "io"
+
+type ShowConfig interface {
+	Execute(targetPath string, output io.Writer) error
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+)
+
+type ShowConfigCommand struct {
+	settings *entities.Settings
+}
+
+func NewShowConfigCommand(settings *entities.Settings) *ShowConfigCommand {
+	return &ShowConfigCommand{settings: settings}
+}
+
+// Execute resolves the configuration for targetPath and prints every setting with its
+// effective value and the file (or environment) it came from.
+func (it *ShowConfigCommand) Execute(targetPath string, output io.Writer) error {
+	if err := it.settings.LoadProjectConfig(targetPath); err != nil {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range it.settings.Describe() {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShowConfigCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create instance when settings provided", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Settings
+		settings := &entities.Settings{}
+
+		// WHEN: Creating a new show config command
+		cmd := commands.NewShowConfigCommand(settings)
+
+		// THEN: Should create a valid command instance
+		require.NotNil(t, cmd)
+	})
+}
+
+func TestShowConfigCommand_Execute(t *testing.T) {
+	t.Run("should print each setting with its value and source", func(t *testing.T) {
+		// GIVEN: A project config declaring the workspace and an environment override
+		t.Setenv("TERRA_CLOUD", "azure")
+		root := t.TempDir()
+		configPath := filepath.Join(root, ".terra.yaml")
+		require.NoError(t, os.WriteFile(configPath, []byte("workspace: dev\nazure_client_secret: hidden\n"), 0o600))
+		cmd := commands.NewShowConfigCommand(&entities.Settings{})
+		var output bytes.Buffer
+
+		// WHEN: Showing the configuration of the project
+		err := cmd.Execute(root, &output)
+
+		// THEN: Should print values with their sources and redact secrets
+		require.NoError(t, err)
+		assert.Regexp(t, `TERRA_WORKSPACE\s+dev\s+`+regexp.QuoteMeta(configPath), output.String())
+		assert.Regexp(t, `TERRA_CLOUD\s+azure\s+environment`, output.String())
+		assert.Regexp(t, `TERRA_AZURE_CLIENT_SECRET\s+<redacted>`, output.String())
+		assert.NotContains(t, output.String(), "hidden")
+	})
+
+	t.Run("should return error when the project config is invalid", func(t *testing.T) {
+		// GIVEN: A project config with an unknown setting
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".terra.yaml"), []byte("unknown: value\n"), 0o600))
+		cmd := commands.NewShowConfigCommand(&entities.Settings{})
+
+		// WHEN: Showing the configuration
+		err := cmd.Execute(root, &bytes.Buffer{})
+
+		// THEN: Should return an error naming the setting
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown")
+	})
+}
@@ -24,6 +24,14 @@ const (
 	OnlyFlagPrefix = "--only="
 	// SkipFlagPrefix represents the prefix for the --skip flag (exclude specific modules).
 	SkipFlagPrefix = "--skip="
+	// ProfileFlagPrefix represents the prefix for the --profile flag (select a named profile).
+	ProfileFlagPrefix = "--profile="
+	// LogFormatFlagPrefix represents the prefix for the --log-format flag (text or json).
+	LogFormatFlagPrefix = "--log-format="
+	// NoFormatFlag represents the --no-format flag (skip formatting before the run).
+	NoFormatFlag = "--no-format"
+	// ChangedOnlyFlag represents the --changed-only flag (format only the changed files).
+	ChangedOnlyFlag = "--changed-only"
 
 	// YesFlag represents the --yes flag (auto-approve, non-interactive).
 	YesFlag = "--yes"
@@ -300,6 +308,59 @@ func RemoveSelectionFlags(arguments []string) []string {
 	return RemoveSkipFlag(filtered)
 }
 
+// GetProfileValue extracts the profile name from the --profile=NAME flag.
+// Returns the name and true if the flag is present, or "" and false otherwise.
+func GetProfileValue(arguments []string) (string, bool) {
+	for _, arg := range arguments {
+		if after, ok := strings.CutPrefix(arg, ProfileFlagPrefix); ok {
+			return strings.TrimSpace(after), true
+		}
+	}
+	return "", false
+}
+
+// RemoveProfileFlag removes --profile= flag from arguments.
+func RemoveProfileFlag(arguments []string) []string {
+	return removeFlagWithPrefix(arguments, ProfileFlagPrefix)
+}
+
+// GetLogFormatValue extracts the format from the --log-format=FORMAT flag.
+// Returns the format and true if the flag is present, or "" and false otherwise.
+func GetLogFormatValue(arguments []string) (string, bool) {
+	for _, arg := range arguments {
+		if after, ok := strings.CutPrefix(arg, LogFormatFlagPrefix); ok {
+			return strings.TrimSpace(after), true
+		}
+	}
+	return "", false
+}
+
+// RemoveLogFormatFlag removes --log-format= flag from arguments.
+func RemoveLogFormatFlag(arguments []string) []string {
+	return removeFlagWithPrefix(arguments, LogFormatFlagPrefix)
+}
+
+// HasNoFormatFlag checks if --no-format is present in arguments.
+func HasNoFormatFlag(arguments []string) bool {
+	return slices.Contains(arguments, NoFormatFlag)
+}
+
+// HasChangedOnlyFlag checks if --changed-only is present in arguments.
+func HasChangedOnlyFlag(arguments []string) bool {
+	return slices.Contains(arguments, ChangedOnlyFlag)
+}
+
+// RemoveFormatFlags removes --no-format and --changed-only from arguments.
+func RemoveFormatFlags(arguments []string) []string {
+	var filtered []string
+	for _, arg := range arguments {
+		if arg != NoFormatFlag && arg != ChangedOnlyFlag {
+			filtered = append(filtered, arg)
+		}
+	}
+	return filtered
+}
+
 // IsInteractiveCommand checks if the command triggers yes/no prompts in terragrunt.
 // Skips leading flags (arguments starting with "-") to find the actual command.
 func IsInteractiveCommand(arguments []string) bool {
@@ -272,6 +272,83 @@ func TestRemoveSkipFlag(t *testing.T) {
 	}
 }
 
+func TestGetProfileValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		arguments     []string
+		expectedValue string
+		expectedFound bool
+	}{
+		{"should return value when --profile=prod", []string{"--profile=prod", "plan"}, "prod", true},
+		{"should trim whitespace", []string{"plan", "--profile= prod "}, "prod", true},
+		{"should return found with empty value", []string{"--profile="}, "", true},
+		{"should return false when not present", []string{"plan"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			value, found := commands.GetProfileValue(tt.arguments)
+			assert.Equal(t, tt.expectedValue, value)
+			assert.Equal(t, tt.expectedFound, found)
+		})
+	}
+}
+
+func TestRemoveProfileFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		arguments []string
+		expected  []string
+	}{
+		{"should remove --profile=prod", []string{"--profile=prod", "plan"}, []string{"plan"}},
+		{"should return unchanged when absent", []string{"plan"}, []string{"plan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, commands.RemoveProfileFlag(tt.arguments))
+		})
+	}
+}
+
+func TestGetLogFormatValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		arguments     []string
+		expectedValue string
+		expectedFound bool
+	}{
+		{"should return value when --log-format=json", []string{"--log-format=json", "plan"}, "json", true},
+		{"should return false when not present", []string{"plan"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			value, found := commands.GetLogFormatValue(tt.arguments)
+			assert.Equal(t, tt.expectedValue, value)
+			assert.Equal(t, tt.expectedFound, found)
+		})
+	}
+}
+
+func TestRemoveLogFormatFlag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should remove --log-format=json", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"plan"}, commands.RemoveLogFormatFlag([]string{"plan", "--log-format=json"}))
+	})
+}
+
 func TestGetSelectionValues(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,7 @@
+package commands
+
+import "github.com/rios0rios0/terra/internal/domain/entities"
+
+type UseVersion interface {
+	Execute(targetPath, tool, version string, dependencies []entities.Dependency) error
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+)
+
+type UseVersionCommand struct {
+	settings       *entities.Settings
+	installCommand InstallDependencies
+}
+
+func NewUseVersionCommand(settings *entities.Settings, installCommand InstallDependencies) *UseVersionCommand {
+	return &UseVersionCommand{settings: settings, installCommand: installCommand}
+}
+
+// Execute installs version of the tool into the tool cache, then pins it for targetPath
+// by writing its `.<tool>-version` file, so every terra command run in (or below)
+// targetPath switches to it. A pin violating the root Terragrunt configuration's
+// constraint is rolled back before anything is installed.
+func (it *UseVersionCommand) Execute(
+	targetPath, tool, version string,
+	dependencies []entities.Dependency,
+) error {
+	index := findDependencyIndex(dependencies, tool)
+	if index < 0 {
+		return fmt.Errorf("unknown tool %q (expected one of: %s)", tool, strings.Join(dependencyCLIs(dependencies), ", "))
+	}
+	if !entities.IsExactVersion(version) {
+		return fmt.Errorf("%q is not an exact version, e.g. %s@1.9.8", version, tool)
+	}
+	version = strings.TrimPrefix(version, "v")
+
+	if err := it.settings.LoadProjectConfig(targetPath); err != nil {
+		return fmt.Errorf("failed to load project configuration: %w", err)
+	}
+
+	path := filepath.Join(targetPath, entities.VersionFileName(tool))
+	previous, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err = os.WriteFile(path, []byte(version+"\n"), 0o644); err != nil { //nolint:gosec // committed to the repository
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	// The pin is written only to be checked for now: a failed install exits terra, which
+	// must not leave a pin to a version that is not installed
+	requirement, err := it.settings.ResolveToolVersion(tool, targetPath)
+	restoreVersionFile(path, previous)
+	if err != nil {
+		return err
+	}
+	if requirement.Pinned != version {
+		logger.Warnf("%s still pins %s %s, which takes precedence over %s",
+			requirement.PinnedSource, tool, requirement.Pinned, path)
+	}
+
+	binaryPath := it.installCommand.InstallVersion(dependencies[index], version)
+	if err = os.WriteFile(path, []byte(version+"\n"), 0o644); err != nil { //nolint:gosec // committed to the repository
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	logger.Infof("Pinned %s %s in %s (binary: %s)", tool, version, path, binaryPath)
+	return nil
+}
+
+// restoreVersionFile puts back the content a version file had before it was rewritten, or
+// removes the file when it did not exist.
+func restoreVersionFile(path string, previous []byte) {
+	var err error
+	if previous == nil {
+		err = os.Remove(path)
+	} else {
+		err = os.WriteFile(path, previous, 0o644) //nolint:gosec // committed to the repository
+	}
+	if err != nil {
+		logger.Warnf("Failed to restore %s: %s", path, err)
+	}
+}
+
+// findDependencyIndex returns the index of the dependency whose CLI is cli, or -1.
+func findDependencyIndex(dependencies []entities.Dependency, cli string) int {
+	for index, dependency := range dependencies {
+		if dependency.CLI == cli {
+			return index
+		}
+	}
+	return -1
+}
+
+func dependencyCLIs(dependencies []entities.Dependency) []string {
+	clis := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		clis = append(clis, dependency.CLI)
+	}
+	return clis
+}
@@ -0,0 +1,101 @@
+//go:build unit
+
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+	"github.com/rios0rios0/terra/test/domain/entitybuilders"
+	logger "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useVersionDependencies() []entities.Dependency {
+	return []entities.Dependency{
+		entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency(),
+		entitybuilders.NewDependencyBuilder().WithName("Terragrunt").WithCLI("terragrunt").BuildDependency(),
+	}
+}
+
+func TestUseVersionCommand_Execute(t *testing.T) {
+	t.Run("should pin the version in the directory and install it", func(t *testing.T) {
+		// GIVEN: A directory without a version file
+		root := t.TempDir()
+		installCommand := &commanddoubles.StubInstallDependencies{}
+		cmd := commands.NewUseVersionCommand(&entities.Settings{}, installCommand)
+
+		// WHEN: Using terraform 1.9.8 in the directory
+		err := cmd.Execute(root, "terraform", "v1.9.8", useVersionDependencies())
+
+		// THEN: Should write the version file and install the version
+		require.NoError(t, err)
+		content, readErr := os.ReadFile(filepath.Join(root, ".terraform-version"))
+		require.NoError(t, readErr)
+		assert.Equal(t, "1.9.8\n", string(content))
+		assert.Equal(t, []string{"terraform@1.9.8"}, installCommand.InstalledVersions)
+	})
+
+	t.Run("should restore the previous pin when the version violates the root constraint", func(t *testing.T) {
+		// GIVEN: A root terragrunt.hcl constraining terragrunt and an existing pin
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "terragrunt.hcl"),
+			[]byte("terragrunt_version_constraint = \"~> 0.67.0\"\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".terragrunt-version"), []byte("0.67.2\n"), 0o600))
+		installCommand := &commanddoubles.StubInstallDependencies{}
+		cmd := commands.NewUseVersionCommand(&entities.Settings{}, installCommand)
+
+		// WHEN: Using a terragrunt version outside the constraint
+		err := cmd.Execute(root, "terragrunt", "0.68.0", useVersionDependencies())
+
+		// THEN: Should fail, keep the previous pin and install nothing
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `does not satisfy "~> 0.67.0"`)
+		content, readErr := os.ReadFile(filepath.Join(root, ".terragrunt-version"))
+		require.NoError(t, readErr)
+		assert.Equal(t, "0.67.2\n", string(content))
+		assert.Empty(t, installCommand.InstalledVersions)
+	})
+
+	t.Run("should keep the previous pin when the install fails", func(t *testing.T) {
+		// GIVEN: An existing pin and an install exiting terra on a failed download
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".terraform-version"), []byte("1.9.7\n"), 0o600))
+		originalExitFunc := logger.StandardLogger().ExitFunc
+		logger.StandardLogger().ExitFunc = func(code int) { panic(code) }
+		defer func() { logger.StandardLogger().ExitFunc = originalExitFunc }()
+		installCommand := &commanddoubles.StubInstallDependencies{InstallFails: true}
+		cmd := commands.NewUseVersionCommand(&entities.Settings{}, installCommand)
+
+		// WHEN: Using terraform 1.9.8 in the directory
+		assert.Panics(t, func() { _ = cmd.Execute(root, "terraform", "1.9.8", useVersionDependencies()) })
+
+		// THEN: Should have tried the install and left the previous pin unchanged
+		assert.Equal(t, []string{"terraform@1.9.8"}, installCommand.InstalledVersions)
+		content, readErr := os.ReadFile(filepath.Join(root, ".terraform-version"))
+		require.NoError(t, readErr)
+		assert.Equal(t, "1.9.7\n", string(content))
+	})
+
+	t.Run("should return an error when the tool or the version is invalid", func(t *testing.T) {
+		// GIVEN: A use version command
+		root := t.TempDir()
+		cmd := commands.NewUseVersionCommand(&entities.Settings{}, &commanddoubles.StubInstallDependencies{})
+
+		// WHEN: Using an unknown tool and a constraint instead of a version
+		unknownErr := cmd.Execute(root, "packer", "1.11.0", useVersionDependencies())
+		constraintErr := cmd.Execute(root, "terraform", "~> 1.9", useVersionDependencies())
+
+		// THEN: Should reject both without writing a version file
+		require.Error(t, unknownErr)
+		assert.Contains(t, unknownErr.Error(), "expected one of: terraform, terragrunt")
+		require.Error(t, constraintErr)
+		assert.Contains(t, constraintErr.Error(), "not an exact version")
+		assert.NoFileExists(t, filepath.Join(root, ".terraform-version"))
+	})
+}
@@ -1,5 +1,23 @@
 package commands
 
+import "io"
+
+const (
+	VersionOutputText = "text"
+	VersionOutputJSON = "json"
+)
+
+// VersionOptions selects what `terra version` looks up and how it reports it.
+type VersionOptions struct {
+	// Output is VersionOutputText (logged lines, the default) or VersionOutputJSON.
+	Output string
+	// Latest also looks up the latest available version of every dependency.
+	Latest bool
+	// Check fails when a dependency is missing, outdated or violates its version
+	// requirements. It implies Latest.
+	Check bool
+}
+
 type Version interface {
-	Execute()
+	Execute(options VersionOptions, output io.Writer) error
 }
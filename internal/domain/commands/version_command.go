@@ -2,6 +2,9 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -23,46 +26,134 @@ const (
 //nolint:gochecknoglobals // Version set at build time via ldflags
 var TerraVersion = "dev"
 
+// cliVersionPattern matches the first version number printed by a tool's `--version`.
+//
+//nolint:gochecknoglobals // Compiled once instead of on every call
+var cliVersionPattern = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
 type VersionCommand struct {
-	dependencies []entities.Dependency
+	settings       *entities.Settings
+	dependencies   []entities.Dependency
+	installCommand InstallDependencies
 }
 
-func NewVersionCommand(dependencies []entities.Dependency) *VersionCommand {
+func NewVersionCommand(
+	settings *entities.Settings,
+	dependencies []entities.Dependency,
+	installCommand InstallDependencies,
+) *VersionCommand {
 	return &VersionCommand{
-		dependencies: dependencies,
+		settings:       settings,
+		dependencies:   dependencies,
+		installCommand: installCommand,
 	}
 }
 
-func (it *VersionCommand) Execute() {
-	logger.Infof("Terra version: %s", TerraVersion)
+// Execute reports the versions of terra and its dependencies as logged lines or as JSON
+// written to output. In check mode, it returns an error listing every dependency that is
+// missing, outdated or violates the repository's version requirements.
+func (it *VersionCommand) Execute(options VersionOptions, output io.Writer) error {
+	if options.Output != "" && options.Output != VersionOutputText && options.Output != VersionOutputJSON {
+		return fmt.Errorf("unsupported output %q (expected %q or %q)",
+			options.Output, VersionOutputText, VersionOutputJSON)
+	}
 
-	// Get Terraform version
-	terraformVersion := it.getTerraformVersion()
-	logger.Infof("Terraform version: %s", terraformVersion)
+	report := it.Report(options.Latest || options.Check)
+	if options.Output == VersionOutputJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to write the version report: %w", err)
+		}
+	} else {
+		logReport(report)
+	}
 
-	// Get Terragrunt version
-	terragruntVersion := it.getTerragruntVersion()
-	logger.Infof("Terragrunt version: %s", terragruntVersion)
+	if !options.Check {
+		return nil
+	}
+	if problems := report.GetProblems(); len(problems) > 0 {
+		return fmt.Errorf("the toolchain does not match the repository: %s", strings.Join(problems, "; "))
+	}
+	return nil
 }
 
-func (it *VersionCommand) getTerraformVersion() string {
-	// Try to get version from terraform CLI first
-	if version := it.getVersionFromCLI("terraform"); version != "" {
-		return version
+// Report collects the installed version of every dependency, the one every run switches
+// to when a version is pinned, and checks it against the repository's version
+// requirements. The latest versions are only looked up when lookupLatest is set.
+func (it *VersionCommand) Report(lookupLatest bool) *entities.VersionReport {
+	report := &entities.VersionReport{Terra: TerraVersion, Dependencies: []entities.DependencyVersion{}}
+	for _, dependency := range it.dependencies {
+		report.Dependencies = append(report.Dependencies, it.reportDependency(dependency, lookupLatest))
 	}
-
-	// If terraform CLI is not available, return "not installed"
-	return notInstalledVersion
+	return report
 }
 
-func (it *VersionCommand) getTerragruntVersion() string {
-	// Try to get version from terragrunt CLI first
-	if version := it.getVersionFromCLI("terragrunt"); version != "" {
-		return version
+func (it *VersionCommand) reportDependency(
+	dependency entities.Dependency,
+	lookupLatest bool,
+) entities.DependencyVersion {
+	status := entities.DependencyVersion{Name: dependency.Name, CLI: dependency.CLI}
+	requirement, err := it.settings.ResolveToolVersion(dependency.CLI, ".")
+	if err != nil {
+		status.Problems = append(status.Problems, err.Error())
+		return status
+	}
+	status.Pinned = requirement.Pinned
+	for _, constraint := range requirement.Constraints {
+		status.Constraints = append(status.Constraints, constraint.Constraint.String())
 	}
 
-	// If terragrunt CLI is not available, return "not installed"
-	return notInstalledVersion
+	binary := dependency.CLI
+	if cached := cachedToolBinary(it.settings, dependency.CLI, requirement); cached != "" {
+		binary = cached
+	}
+	status.Installed = it.getVersionFromCLI(binary)
+	switch err = requirement.Check(status.Installed); {
+	case status.Installed == "":
+		status.Problems = append(status.Problems, notInstalledVersion)
+	case err != nil:
+		status.Problems = append(status.Problems, err.Error())
+	default:
+		status.Satisfied = true
+	}
+	if status.Installed != "" {
+		status.Path, _ = exec.LookPath(binary)
+	}
+
+	if !lookupLatest {
+		return status
+	}
+	latest, err := it.installCommand.LatestVersion(dependency)
+	if err != nil {
+		status.Problems = append(status.Problems, "could not determine the latest version: "+err.Error())
+		return status
+	}
+	status.Latest = latest
+
+	// A pinned version, or a latest release excluded by the constraints, is kept on purpose
+	status.Outdated = status.Installed != "" && requirement.Pinned == "" && requirement.Allows(latest) &&
+		entities.CompareVersions(status.Installed, latest) < 0
+	if status.Outdated {
+		status.Problems = append(status.Problems,
+			fmt.Sprintf("%s is older than the latest version %s", status.Installed, latest))
+	}
+	return status
+}
+
+// logReport logs one line per dependency, with the latest version when it was looked up.
+func logReport(report *entities.VersionReport) {
+	logger.Infof("Terra version: %s", report.Terra)
+	for _, dependency := range report.Dependencies {
+		version := dependency.Installed
+		if version == "" {
+			version = notInstalledVersion
+		}
+		if dependency.Latest != "" {
+			version += fmt.Sprintf(" (%s: %s)", latestAvailableVersion, dependency.Latest)
+		}
+		logger.Infof("%s version: %s", dependency.Name, version)
+	}
 }
 
 func (it *VersionCommand) getVersionFromCLI(tool string) string {
@@ -77,22 +168,11 @@ func (it *VersionCommand) getVersionFromCLI(tool string) string {
 
 	version := strings.TrimSpace(string(output))
 
-	// Extract version number from output
-	switch tool {
-	case "terraform":
-		// Terraform output: "Terraform v1.5.7"
-		re := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
-		matches := re.FindStringSubmatch(version)
-		if len(matches) > 1 {
-			return matches[1]
-		}
-	case "terragrunt":
-		// Terragrunt output: "terragrunt version v0.50.17"
-		re := regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
-		matches := re.FindStringSubmatch(version)
-		if len(matches) > 1 {
-			return matches[1]
-		}
+	// Terraform prints "Terraform v1.5.7", Terragrunt "terragrunt version v0.50.17" and
+	// declared tools anything up to "TFLint version 0.53.0" followed by their plugins, so
+	// only the first version number is kept
+	if matches := cliVersionPattern.FindStringSubmatch(version); len(matches) > 1 {
+		return matches[1]
 	}
 
 	return version
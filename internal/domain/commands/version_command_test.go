@@ -3,11 +3,21 @@
 package commands_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
 	"github.com/rios0rios0/terra/test/domain/entitybuilders"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	logger "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,7 +36,9 @@ func TestNewVersionCommand(t *testing.T) {
 		}
 
 		// WHEN: Creating a new version command
-		cmd := commands.NewVersionCommand(dependencies)
+		cmd := commands.NewVersionCommand(
+			&entities.Settings{}, dependencies, &commanddoubles.StubInstallDependenciesCommand{},
+		)
 
 		// THEN: Should create a valid command instance
 		require.NotNil(t, cmd)
@@ -51,21 +63,25 @@ func TestVersionCommand_Execute(t *testing.T) {
 				WithTerragruntPattern().
 				BuildDependency(),
 		}
-		cmd := commands.NewVersionCommand(dependencies)
+		cmd := commands.NewVersionCommand(
+			&entities.Settings{}, dependencies, &commanddoubles.StubInstallDependenciesCommand{},
+		)
 
 		// WHEN: Executing the version command
-		// THEN: Should complete without panicking (verified by not crashing)
-		cmd.Execute()
+		// THEN: Should complete without error
+		require.NoError(t, cmd.Execute(commands.VersionOptions{}, io.Discard))
 	})
 
 	t.Run("should complete without panic when empty dependencies provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A version command with empty dependencies
-		cmd := commands.NewVersionCommand([]entities.Dependency{})
+		cmd := commands.NewVersionCommand(
+			&entities.Settings{}, []entities.Dependency{}, &commanddoubles.StubInstallDependenciesCommand{},
+		)
 
 		// WHEN: Executing the version command
-		// THEN: Should complete without panicking (verified by not crashing)
-		cmd.Execute()
+		// THEN: Should complete without error
+		require.NoError(t, cmd.Execute(commands.VersionOptions{}, io.Discard))
 	})
 }
 
@@ -75,10 +91,158 @@ func TestVersionCommand_Execute_ToolsNotInstalled(t *testing.T) {
 	t.Run("should report not installed when tools are not found in PATH", func(t *testing.T) {
 		// GIVEN: PATH is set to empty so no CLI tools can be found
 		t.Setenv("PATH", "")
-		cmd := commands.NewVersionCommand([]entities.Dependency{})
+		cmd := commands.NewVersionCommand(
+			&entities.Settings{}, []entities.Dependency{}, &commanddoubles.StubInstallDependenciesCommand{},
+		)
 
 		// WHEN: Executing the version command with no tools available
-		// THEN: Should complete without panicking, reporting "not installed" for both tools
-		cmd.Execute()
+		// THEN: Should complete without error, reporting "not installed" for both tools
+		require.NoError(t, cmd.Execute(commands.VersionOptions{}, io.Discard))
+	})
+}
+
+func TestVersionCommand_Execute_Tofu(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because t.Setenv modifies process-wide environment
+
+	t.Run("should report the OpenTofu version when OpenTofu is the engine", func(t *testing.T) {
+		// GIVEN: A tofu binary in the PATH and OpenTofu among the dependencies
+		binDir := t.TempDir()
+		require.NoError(t, os.WriteFile(
+			filepath.Join(binDir, "tofu"), []byte("#!/bin/sh\necho 'OpenTofu v1.8.2'\n"), 0o700)) //nolint:gosec // test executable
+		t.Setenv("PATH", binDir)
+		hook := test.NewLocal(logger.StandardLogger())
+		cmd := commands.NewVersionCommand(&entities.Settings{}, []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().WithName("OpenTofu").WithCLI("tofu").
+				WithEngine(entities.EngineTofu).BuildDependency(),
+		}, &commanddoubles.StubInstallDependenciesCommand{})
+
+		// WHEN: Executing the version command
+		require.NoError(t, cmd.Execute(commands.VersionOptions{}, io.Discard))
+
+		// THEN: Should report OpenTofu instead of Terraform
+		messages := make([]string, 0, len(hook.AllEntries()))
+		for _, entry := range hook.AllEntries() {
+			messages = append(messages, entry.Message)
+		}
+		assert.Contains(t, messages, "OpenTofu version: 1.8.2")
+		assert.NotContains(t, messages, "Terraform version: not installed")
+	})
+}
+
+func TestVersionCommand_Execute_DeclaredTools(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because t.Setenv modifies process-wide environment
+
+	t.Run("should report the version of the tools declared in the project configuration", func(t *testing.T) {
+		// GIVEN: tflint in the PATH and declared among the dependencies
+		binDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, "tflint"),
+			[]byte("#!/bin/sh\necho 'TFLint version 0.53.0'\necho '+ ruleset.terraform (0.9.1-bundled)'\n"), 0o700)) //nolint:gosec // test executable
+		t.Setenv("PATH", binDir)
+		hook := test.NewLocal(logger.StandardLogger())
+		cmd := commands.NewVersionCommand(&entities.Settings{}, []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().WithName("TFLint").WithCLI("tflint").BuildDependency(),
+			entitybuilders.NewDependencyBuilder().WithName("trivy").WithCLI("trivy").BuildDependency(),
+		}, &commanddoubles.StubInstallDependenciesCommand{})
+
+		// WHEN: Executing the version command
+		require.NoError(t, cmd.Execute(commands.VersionOptions{}, io.Discard))
+
+		// THEN: Should report the installed tool's version and the missing one
+		messages := make([]string, 0, len(hook.AllEntries()))
+		for _, entry := range hook.AllEntries() {
+			messages = append(messages, entry.Message)
+		}
+		assert.Contains(t, messages, "TFLint version: 0.53.0")
+		assert.Contains(t, messages, "trivy version: not installed")
+	})
+}
+
+func TestVersionCommand_Execute_Report(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because t.Setenv modifies process-wide environment
+
+	t.Run("should write a JSON report with the install paths and the requirements", func(t *testing.T) {
+		// GIVEN: terraform 1.9.5 in the PATH, constrained by the project configuration
+		binDir := t.TempDir()
+		repositoryhelpers.HelperCreateMockBinary(t, binDir, "terraform", `echo "Terraform v1.9.5"`)
+		t.Setenv("PATH", binDir)
+		t.Setenv("TERRA_TERRAFORM_VERSION", ">= 1.5.0")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(t.TempDir()))
+		cmd := commands.NewVersionCommand(settings, []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency(),
+		}, &commanddoubles.StubInstallDependenciesCommand{})
+		var output bytes.Buffer
+
+		// WHEN: Executing the version command with JSON output
+		err := cmd.Execute(commands.VersionOptions{Output: commands.VersionOutputJSON}, &output)
+
+		// THEN: Should describe the installed version without looking up the latest one
+		require.NoError(t, err)
+		var report entities.VersionReport
+		require.NoError(t, json.Unmarshal(output.Bytes(), &report))
+		require.Len(t, report.Dependencies, 1)
+		terraform := report.Dependencies[0]
+		assert.Equal(t, "1.9.5", terraform.Installed)
+		assert.Equal(t, filepath.Join(binDir, "terraform"), terraform.Path)
+		assert.Equal(t, []string{">= 1.5.0"}, terraform.Constraints)
+		assert.True(t, terraform.Satisfied)
+		assert.Empty(t, terraform.Latest)
+	})
+
+	t.Run("should fail the check when a dependency is outdated or missing", func(t *testing.T) {
+		// GIVEN: terraform older than the latest release and terragrunt not installed
+		binDir := t.TempDir()
+		repositoryhelpers.HelperCreateMockBinary(t, binDir, "terraform", `echo "Terraform v1.9.5"`)
+		t.Setenv("PATH", binDir)
+		install := &commanddoubles.StubInstallDependenciesCommand{
+			LatestVersions: map[string]string{"terraform": "1.10.0", "terragrunt": "0.67.0"},
+		}
+		cmd := commands.NewVersionCommand(&entities.Settings{}, []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency(),
+			entitybuilders.NewDependencyBuilder().WithName("Terragrunt").WithCLI("terragrunt").BuildDependency(),
+		}, install)
+
+		// WHEN: Executing the version command in check mode
+		err := cmd.Execute(commands.VersionOptions{Check: true}, io.Discard)
+
+		// THEN: Should report both problems
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Terraform: 1.9.5 is older than the latest version 1.10.0")
+		assert.Contains(t, err.Error(), "Terragrunt: not installed")
+	})
+
+	t.Run("should pass the check when the pinned version is installed even if a newer one exists", func(t *testing.T) {
+		// GIVEN: terraform 1.9.5 pinned and installed, while 1.10.0 is the latest release
+		binDir := t.TempDir()
+		repositoryhelpers.HelperCreateMockBinary(t, binDir, "terraform", `echo "Terraform v1.9.5"`)
+		t.Setenv("PATH", binDir)
+		t.Setenv("TERRA_TOOL_CACHE_DIR", t.TempDir())
+		t.Setenv("TERRA_TERRAFORM_VERSION", "1.9.5")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(t.TempDir()))
+		install := &commanddoubles.StubInstallDependenciesCommand{LatestVersions: map[string]string{"terraform": "1.10.0"}}
+		cmd := commands.NewVersionCommand(settings, []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().WithName("Terraform").WithCLI("terraform").BuildDependency(),
+		}, install)
+
+		// WHEN: Executing the version command in check mode
+		err := cmd.Execute(commands.VersionOptions{Check: true}, io.Discard)
+
+		// THEN: Should not consider the pinned version outdated
+		require.NoError(t, err)
+	})
+
+	t.Run("should return error when the output format is unsupported", func(t *testing.T) {
+		// GIVEN: A version command
+		cmd := commands.NewVersionCommand(
+			&entities.Settings{}, []entities.Dependency{}, &commanddoubles.StubInstallDependenciesCommand{},
+		)
+
+		// WHEN: Executing it with an unknown output format
+		err := cmd.Execute(commands.VersionOptions{Output: "yaml"}, io.Discard)
+
+		// THEN: Should reject it
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported output "yaml"`)
 	})
 }
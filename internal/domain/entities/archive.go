@@ -0,0 +1,219 @@
+package entities
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ArchiveZip is a zip archive, as published by Terraform and OpenTofu.
+	ArchiveZip = "zip"
+	// ArchiveTarGz is a gzip-compressed tar archive.
+	ArchiveTarGz = "tar.gz"
+
+	extractedDirPermissions = 0o750
+)
+
+//nolint:gochecknoglobals // read-only lookup table
+var archiveSignatures = []struct {
+	format string
+	magic  []byte
+}{
+	{ArchiveZip, []byte("PK\x03\x04")},
+	{ArchiveZip, []byte("PK\x05\x06")}, // empty zip archive
+	{ArchiveTarGz, []byte{0x1f, 0x8b}},
+}
+
+// DetectArchiveFormat reads the first bytes of the file at path and returns ArchiveZip,
+// ArchiveTarGz, or "" when it is not an archive (e.g. a bare binary).
+func DetectArchiveFormat(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 4) //nolint:mnd // longest magic number
+	read, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for _, signature := range archiveSignatures {
+		if bytes.HasPrefix(header[:read], signature.magic) {
+			return signature.format, nil
+		}
+	}
+	return "", nil
+}
+
+// extractArchive extracts the zip or tar.gz archive at archivePath into destPath, keeping
+// the permissions of the entries and refusing entries escaping destPath.
+func extractArchive(archivePath, destPath string) error {
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case ArchiveZip:
+		return extractZip(archivePath, destPath)
+	case ArchiveTarGz:
+		return extractTarGz(archivePath, destPath)
+	default:
+		return fmt.Errorf("%s is neither a zip nor a tar.gz archive", archivePath)
+	}
+}
+
+func extractZip(archivePath, destPath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		target, joinErr := archiveEntryPath(destPath, entry.Name)
+		if joinErr != nil {
+			return joinErr
+		}
+		if entry.FileInfo().IsDir() {
+			if err = os.MkdirAll(target, extractedDirPermissions); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, openErr := entry.Open()
+		if openErr != nil {
+			return openErr
+		}
+		err = writeArchiveEntry(target, content, entry.Mode().Perm())
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTarGz(archivePath, destPath string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decompressed, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer decompressed.Close()
+
+	reader := tar.NewReader(decompressed)
+	for {
+		header, nextErr := reader.Next()
+		if errors.Is(nextErr, io.EOF) {
+			return nil
+		}
+		if nextErr != nil {
+			return nextErr
+		}
+
+		target, joinErr := archiveEntryPath(destPath, header.Name)
+		if joinErr != nil {
+			return joinErr
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, extractedDirPermissions); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = writeArchiveEntry(target, reader, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			// Links and special files are never part of a release binary
+			continue
+		}
+	}
+}
+
+// archiveEntryPath returns where the entry named name is extracted under destPath,
+// rejecting names that would escape it ("zip slip").
+func archiveEntryPath(destPath, name string) (string, error) {
+	target := filepath.Join(destPath, name) //nolint:gosec // checked to stay under destPath below
+	if target != filepath.Clean(destPath) &&
+		!strings.HasPrefix(target, filepath.Clean(destPath)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q escapes the extraction directory", name)
+	}
+	return target, nil
+}
+
+func writeArchiveEntry(target string, content io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), extractedDirPermissions); err != nil {
+		return err
+	}
+
+	output, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(output, content); err != nil { //nolint:gosec // release archives are verified before extraction
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// moveFile renames source to destPath, which is atomic on the same filesystem. Across
+// filesystems (e.g. from a tmpfs /tmp to the home directory), it copies source to a
+// temporary file next to destPath, renames that file over destPath and removes source.
+func moveFile(source, destPath string) error {
+	if err := os.Rename(source, destPath); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	output, err := os.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+"_*")
+	if err != nil {
+		return err
+	}
+	tempPath := output.Name()
+	defer os.Remove(tempPath) // no-op once renamed over destPath
+
+	if _, err = io.Copy(output, input); err != nil {
+		_ = output.Close()
+		return err
+	}
+	if err = output.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tempPath, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err = os.Rename(tempPath, destPath); err != nil {
+		return err
+	}
+
+	_ = input.Close()
+	return os.Remove(source)
+}
@@ -0,0 +1,160 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeZip writes a zip archive holding the given files, executable, to path.
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(0o755)
+		entry, err := writer.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0o600))
+}
+
+// writeTarGz writes a gzip-compressed tar archive holding the given files, executable, to path.
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	compressed := gzip.NewWriter(&buffer)
+	writer := tar.NewWriter(compressed)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{
+			Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg,
+		}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, compressed.Close())
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0o600))
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should detect zip and tar.gz archives and bare binaries from their magic bytes", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A zip, a tar.gz and a bare binary, without meaningful extensions
+		dir := t.TempDir()
+		zipPath, tarGzPath, binaryPath := filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "c")
+		writeZip(t, zipPath, map[string]string{"terraform": "binary"})
+		writeTarGz(t, tarGzPath, map[string]string{"tflint": "binary"})
+		require.NoError(t, os.WriteFile(binaryPath, []byte("\x7fELF"), 0o600))
+
+		// WHEN: Detecting their formats
+		zipFormat, zipErr := entities.DetectArchiveFormat(zipPath)
+		tarGzFormat, tarGzErr := entities.DetectArchiveFormat(tarGzPath)
+		binaryFormat, binaryErr := entities.DetectArchiveFormat(binaryPath)
+
+		// THEN: Should tell the archives from the binary
+		require.NoError(t, zipErr)
+		require.NoError(t, tarGzErr)
+		require.NoError(t, binaryErr)
+		assert.Equal(t, entities.ArchiveZip, zipFormat)
+		assert.Equal(t, entities.ArchiveTarGz, tarGzFormat)
+		assert.Empty(t, binaryFormat)
+	})
+}
+
+func TestOS_Extract(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should extract a zip archive without the unzip command", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A zip archive holding an executable
+		dir := t.TempDir()
+		archive := filepath.Join(dir, "terraform.zip")
+		writeZip(t, archive, map[string]string{"terraform": "#!/bin/sh\n"})
+		destDir := t.TempDir()
+
+		// WHEN: Extracting it
+		err := entities.GetOS().Extract(archive, destDir)
+
+		// THEN: Should write the executable
+		require.NoError(t, err)
+		content, readErr := os.ReadFile(filepath.Join(destDir, "terraform"))
+		require.NoError(t, readErr)
+		assert.Equal(t, "#!/bin/sh\n", string(content))
+	})
+
+	t.Run("should extract a tar.gz archive with nested directories", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A tar.gz archive holding a binary in a subdirectory
+		dir := t.TempDir()
+		archive := filepath.Join(dir, "tool.tar.gz")
+		writeTarGz(t, archive, map[string]string{"tool_1.0.0/tool": "binary", "tool_1.0.0/LICENSE": "MIT"})
+		destDir := t.TempDir()
+
+		// WHEN: Extracting it
+		err := entities.GetOS().Extract(archive, destDir)
+
+		// THEN: Should recreate the layout
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(destDir, "tool_1.0.0", "tool"))
+		assert.FileExists(t, filepath.Join(destDir, "tool_1.0.0", "LICENSE"))
+	})
+
+	t.Run("should refuse entries escaping the extraction directory", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A zip archive with a path traversal entry
+		dir := t.TempDir()
+		archive := filepath.Join(dir, "evil.zip")
+		writeZip(t, archive, map[string]string{"../evil": "payload"})
+		destDir := filepath.Join(dir, "extracted")
+
+		// WHEN: Extracting it
+		err := entities.GetOS().Extract(archive, destDir)
+
+		// THEN: Should fail without writing outside the directory
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "escapes the extraction directory")
+		assert.NoFileExists(t, filepath.Join(dir, "evil"))
+	})
+}
+
+func TestOS_Move(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should replace an existing destination", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A new binary and an older one at the destination
+		dir := t.TempDir()
+		source, destPath := filepath.Join(dir, "new"), filepath.Join(dir, "terraform")
+		require.NoError(t, os.WriteFile(source, []byte("new"), 0o600))
+		require.NoError(t, os.WriteFile(destPath, []byte("old"), 0o600))
+
+		// WHEN: Moving the new binary over the old one
+		err := entities.GetOS().Move(source, destPath)
+
+		// THEN: Should leave only the new binary
+		require.NoError(t, err)
+		content, readErr := os.ReadFile(destPath)
+		require.NoError(t, readErr)
+		assert.Equal(t, "new", string(content))
+		assert.NoFileExists(t, source)
+	})
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // the AWS CLI names its SSO token cache files after a SHA-1 digest
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// awsSSOExpiryMargin treats tokens that are about to expire as already expired, so a long
+// terragrunt run does not start with a token that lapses a few seconds later.
+const awsSSOExpiryMargin = 5 * time.Minute
+
+// awsSSOToken mirrors the fields terra needs from a file in ~/.aws/sso/cache.
+type awsSSOToken struct {
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// IsAwsSSOSessionValid reports whether the AWS CLI holds a non-expired SSO token for the
+// given profile. It follows the AWS CLI's own cache layout: the token file is named after
+// the SHA-1 of the profile's `sso_session` name, or of its legacy `sso_start_url`.
+func IsAwsSSOSessionValid(profile string, now time.Time) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+
+	configPath := os.Getenv("AWS_CONFIG_FILE")
+	if configPath == "" {
+		configPath = filepath.Join(home, ".aws", "config")
+	}
+
+	sections, err := readAwsConfig(configPath)
+	if err != nil {
+		return false
+	}
+
+	sectionName := "profile " + profile
+	if profile == "default" {
+		sectionName = "default"
+	}
+	section := sections[sectionName]
+
+	cacheKey := section["sso_session"]
+	if cacheKey == "" {
+		cacheKey = section["sso_start_url"]
+	}
+	if cacheKey == "" {
+		return false
+	}
+
+	digest := sha1.Sum([]byte(cacheKey)) //nolint:gosec // cache file naming, not a security boundary
+	content, err := os.ReadFile(filepath.Join(home, ".aws", "sso", "cache", hex.EncodeToString(digest[:])+".json"))
+	if err != nil {
+		return false
+	}
+
+	var token awsSSOToken
+	if err = json.Unmarshal(content, &token); err != nil {
+		return false
+	}
+
+	// older AWS CLI versions write "2006-01-02T15:04:05UTC" instead of RFC 3339
+	expiresAt, err := time.Parse(time.RFC3339, strings.Replace(token.ExpiresAt, "UTC", "Z", 1))
+	if err != nil {
+		return false
+	}
+
+	return now.Add(awsSSOExpiryMargin).Before(expiresAt)
+}
+
+// readAwsConfig parses the INI-style AWS config file into its sections and keys.
+func readAwsConfig(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sections := map[string]map[string]string{}
+	current := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = map[string]string{}
+			sections[strings.TrimSpace(line[1:len(line)-1])] = current
+		default:
+			if key, value, found := strings.Cut(line, "="); found {
+				current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	return sections, scanner.Err()
+}
@@ -0,0 +1,82 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"crypto/sha1" //nolint:gosec // mirrors the AWS CLI cache file naming
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAwsSSOFixture writes an AWS config with an SSO profile and its cached token.
+func writeAwsSSOFixture(t *testing.T, config, cacheKey, expiresAt string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", "")
+	cacheDir := filepath.Join(home, ".aws", "sso", "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".aws", "config"), []byte(config), 0o600))
+
+	digest := sha1.Sum([]byte(cacheKey)) //nolint:gosec // mirrors the AWS CLI cache file naming
+	token := `{"accessToken":"x","expiresAt":"` + expiresAt + `"}`
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, hex.EncodeToString(digest[:])+".json"), []byte(token), 0o600))
+}
+
+func TestIsAwsSSOSessionValid(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should return true when the sso-session token has not expired", func(t *testing.T) {
+		// GIVEN:
+		writeAwsSSOFixture(t, "[profile dev]\nsso_session = corp\n\n[sso-session corp]\nsso_start_url = https://corp.awsapps.com/start\n",
+			"corp", "2026-01-01T18:00:00Z")
+
+		// WHEN:
+		valid := entities.IsAwsSSOSessionValid("dev", now)
+
+		// THEN:
+		assert.True(t, valid)
+	})
+
+	t.Run("should return false when the token expires within the safety margin", func(t *testing.T) {
+		// GIVEN:
+		writeAwsSSOFixture(t, "[profile dev]\nsso_session = corp\n", "corp", "2026-01-01T12:01:00Z")
+
+		// WHEN:
+		valid := entities.IsAwsSSOSessionValid("dev", now)
+
+		// THEN:
+		assert.False(t, valid)
+	})
+
+	t.Run("should use the legacy start url and UTC suffix when no sso-session is declared", func(t *testing.T) {
+		// GIVEN:
+		writeAwsSSOFixture(t, "[default]\nsso_start_url = https://legacy.awsapps.com/start\n",
+			"https://legacy.awsapps.com/start", "2026-01-01T18:00:00UTC")
+
+		// WHEN:
+		valid := entities.IsAwsSSOSessionValid("default", now)
+
+		// THEN:
+		assert.True(t, valid)
+	})
+
+	t.Run("should return false when the profile is not an SSO profile", func(t *testing.T) {
+		// GIVEN:
+		writeAwsSSOFixture(t, "[profile static]\nregion = us-east-1\n", "corp", "2026-01-01T18:00:00Z")
+
+		// WHEN:
+		valid := entities.IsAwsSSOSessionValid("static", now)
+
+		// THEN:
+		assert.False(t, valid)
+	})
+}
@@ -0,0 +1,181 @@
+package entities
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	CacheKindModule   = "module"
+	CacheKindProvider = "provider"
+)
+
+// cacheEntryDepths is how deep below each cache the evictable entries are: Terragrunt
+// checks modules out into <working dir hash>/<source hash>, and the provider cache keeps
+// every provider version in <host>/<namespace>/<type>/<version>.
+var cacheEntryDepths = map[string]int{ //nolint:gochecknoglobals // fixed cache layouts
+	CacheKindModule:   2,
+	CacheKindProvider: 4,
+}
+
+// CacheEntry is a module checkout or a provider version in the centralized caches.
+type CacheEntry struct {
+	Kind string
+	// Name is the provider address and version, or the module's source when its checkout
+	// is a Git clone and its path below the cache otherwise.
+	Name string
+	Path string
+	Size int64
+	// LastUsed is the latest modification time of the files below the entry, or of the
+	// entry itself when it holds none.
+	LastUsed time.Time
+	// Locked is true while a running Terragrunt holds a lock file of the entry.
+	Locked bool
+}
+
+// ScanCacheEntries lists the entries of the cache of the given kind rooted at root, which
+// may not exist yet.
+func ScanCacheEntries(kind, root string) ([]CacheEntry, error) {
+	depth, found := cacheEntryDepths[kind]
+	if !found {
+		return nil, fmt.Errorf("unknown cache kind %q", kind)
+	}
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var entries []CacheEntry
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, _ := filepath.Rel(root, path)
+		if relative == "." || !entry.IsDir() {
+			return nil
+		}
+		if strings.Count(relative, string(filepath.Separator)) < depth-1 {
+			return nil
+		}
+
+		cacheEntry := CacheEntry{Kind: kind, Name: filepath.ToSlash(relative), Path: path}
+		if kind == CacheKindModule {
+			if source := gitRemoteURL(path); source != "" {
+				cacheEntry.Name = source
+			}
+		}
+		inspectCacheEntry(&cacheEntry)
+		entries = append(entries, cacheEntry)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return entries, nil
+}
+
+// inspectCacheEntry fills in the size, last use and lock state of the entry.
+func inspectCacheEntry(cacheEntry *CacheEntry) {
+	cacheEntry.Locked = IsFileLocked(cacheEntry.Path + ".lock")
+	_ = filepath.WalkDir(cacheEntry.Path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // unreadable entries are left out of the totals
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return nil //nolint:nilerr // unreadable entries are left out of the totals
+		}
+		if entry.Type().IsRegular() {
+			if info.ModTime().After(cacheEntry.LastUsed) {
+				cacheEntry.LastUsed = info.ModTime()
+			}
+			cacheEntry.Size += info.Size()
+			if strings.HasSuffix(entry.Name(), ".lock") && IsFileLocked(path) {
+				cacheEntry.Locked = true
+			}
+		}
+		return nil
+	})
+
+	if info, err := os.Stat(cacheEntry.Path); err == nil && cacheEntry.LastUsed.IsZero() {
+		cacheEntry.LastUsed = info.ModTime()
+	}
+}
+
+// gitRemoteURL returns the URL of the origin remote of the Git clone at directory, or "".
+func gitRemoteURL(directory string) string {
+	file, err := os.Open(filepath.Join(directory, ".git", "config"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); inOrigin && found && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// ParseByteSize parses a size such as "20GB", "512MiB" or "1.5G". Decimal and binary
+// units are both read as powers of 1024, the way `du -h` reports sizes.
+func ParseByteSize(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	index := strings.IndexFunc(trimmed, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if index < 0 {
+		index = len(trimmed)
+	}
+
+	number, err := strconv.ParseFloat(trimmed[:index], 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q, e.g. 20GB or 512MiB", value)
+	}
+
+	unit := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(trimmed[index:])), "B"), "I")
+	exponent := 0
+	if unit != "" {
+		exponent = strings.Index("KMGTPE", unit) + 1
+		if len(unit) > 1 || exponent == 0 {
+			return 0, fmt.Errorf("invalid size unit in %q, expected B, KB, MB, GB or TB", value)
+		}
+	}
+
+	multiplier := int64(1) << (10 * exponent) //nolint:mnd // binary units
+	return int64(number * float64(multiplier)), nil
+}
+
+// ParseAge parses an age such as "30d", "2w" or "12h": Go durations, plus days and weeks.
+func ParseAge(value string) (time.Duration, error) {
+	const day = 24 * time.Hour
+	trimmed := strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": day, "w": 7 * day} {
+		if number, found := strings.CutSuffix(trimmed, suffix); found {
+			count, err := strconv.ParseFloat(number, 64)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age %q, e.g. 30d, 2w or 12h", value)
+			}
+			return time.Duration(count * float64(unit)), nil
+		}
+	}
+
+	age, err := time.ParseDuration(trimmed)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q, e.g. 30d, 2w or 12h", value)
+	}
+	return age, nil
+}
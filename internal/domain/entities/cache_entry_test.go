@@ -0,0 +1,138 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanCacheEntries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list every provider version with its size and last use", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A provider cache holding two versions of a provider
+		root := t.TempDir()
+		lastUsed := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+		repositoryhelpers.HelperCreateCacheEntry(t, root, "registry.terraform.io/hashicorp/aws/5.31.0/linux_amd64", 100, lastUsed)
+		repositoryhelpers.HelperCreateCacheEntry(t, root, "registry.terraform.io/hashicorp/aws/5.40.0/linux_amd64", 300, time.Now())
+
+		// WHEN: Scanning the provider cache
+		entries, err := entities.ScanCacheEntries(entities.CacheKindProvider, root)
+
+		// THEN: Should find one entry per version
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "registry.terraform.io/hashicorp/aws/5.31.0", entries[0].Name)
+		assert.Equal(t, int64(100), entries[0].Size)
+		assert.True(t, entries[0].LastUsed.Equal(lastUsed))
+		assert.False(t, entries[0].Locked)
+		assert.Equal(t, int64(300), entries[1].Size)
+	})
+
+	t.Run("should name module checkouts after their Git source when they are clones", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module cache holding a Git clone and a plain checkout
+		root := t.TempDir()
+		clone := repositoryhelpers.HelperCreateCacheEntry(t, root, "aaa/bbb", 10, time.Now())
+		require.NoError(t, os.MkdirAll(filepath.Join(clone, ".git"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(clone, ".git", "config"), []byte(
+			"[core]\n\tbare = false\n[remote \"origin\"]\n\turl = https://github.com/acme/modules.git\n",
+		), 0o600))
+		repositoryhelpers.HelperCreateCacheEntry(t, root, "ccc/ddd", 10, time.Now())
+
+		// WHEN: Scanning the module cache
+		entries, err := entities.ScanCacheEntries(entities.CacheKindModule, root)
+
+		// THEN: Should show the source of the clone and the path of the other checkout
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "https://github.com/acme/modules.git", entries[0].Name)
+		assert.Equal(t, "ccc/ddd", entries[1].Name)
+	})
+
+	t.Run("should return no entries when the cache does not exist", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A cache directory that was never created
+		root := filepath.Join(t.TempDir(), "missing")
+
+		// WHEN: Scanning it
+		entries, err := entities.ScanCacheEntries(entities.CacheKindModule, root)
+
+		// THEN: Should find nothing without failing
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should parse sizes with decimal and binary units as powers of 1024", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Sizes written in the usual ways
+		cases := map[string]int64{
+			"20GB": 20 << 30, "512MiB": 512 << 20, "1.5G": 3 << 29, "100": 100, "64 kb": 64 << 10,
+		}
+
+		for value, expected := range cases {
+			// WHEN: Parsing the size
+			size, err := entities.ParseByteSize(value)
+
+			// THEN: Should return the size in bytes
+			require.NoError(t, err, value)
+			assert.Equal(t, expected, size, value)
+		}
+	})
+
+	t.Run("should return error when the size is invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, value := range []string{"", "GB", "20XB", "-1GB"} {
+			// WHEN: Parsing an invalid size
+			_, err := entities.ParseByteSize(value)
+
+			// THEN: Should reject it
+			require.Error(t, err, value)
+		}
+	})
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should parse days, weeks and Go durations", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Ages written in the usual ways
+		cases := map[string]time.Duration{
+			"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "12h": 12 * time.Hour, "90m": 90 * time.Minute,
+		}
+
+		for value, expected := range cases {
+			// WHEN: Parsing the age
+			age, err := entities.ParseAge(value)
+
+			// THEN: Should return the duration
+			require.NoError(t, err, value)
+			assert.Equal(t, expected, age, value)
+		}
+	})
+
+	t.Run("should return error when the age is invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, value := range []string{"", "d", "thirty days", "-3d"} {
+			// WHEN: Parsing an invalid age
+			_, err := entities.ParseAge(value)
+
+			// THEN: Should reject it
+			require.Error(t, err, value)
+		}
+	})
+}
@@ -7,7 +7,25 @@ import (
 type CLI interface {
 	GetName() string
 	CanChangeAccount() bool
+	// GetCommandChangeAccount returns the command that switches the CLI's active account, or
+	// nil when the account is fully described by GetAccountEnvironment and no switch is needed.
 	GetCommandChangeAccount() []string
+	// CanLogin reports whether a non-interactive login was requested and is fully configured.
+	CanLogin() bool
+	// IsLoginGlobal reports whether the login switches the identity of the CLI for every
+	// process of the user, which parallel workers scoped to their own accounts must not do.
+	IsLoginGlobal() bool
+	// GetCommandLogin returns the non-interactive login command run before switching account.
+	GetCommandLogin() ([]string, error)
+	// GetCommandAccountEnvironment returns the command whose captured output is translated
+	// by GetAccountEnvironment, or nil when the environment can be built from settings alone.
+	GetCommandAccountEnvironment() []string
+	// GetAccountEnvironment returns the "KEY=VALUE" variables that scope a single child
+	// process to the configured account, without mutating any global CLI or process state.
+	GetAccountEnvironment(output string) ([]string, error)
+	// GetCommandIdentity returns a command that only succeeds while the CLI holds a valid
+	// session, or nil when the account is authenticated without any CLI session.
+	GetCommandIdentity() []string
 }
 
 // NewCLI selects the cloud-specific CLI adapter for account-switching commands.
@@ -19,10 +37,10 @@ type CLI interface {
 //     guarantees the value is either one of these or empty.
 //  2. Auto-detection from the cloud-specific credential variable: a non-empty
 //     `TERRA_AZURE_SUBSCRIPTION_ID` selects the Azure adapter; a non-empty
-//     `TERRA_AWS_ROLE_ARN` selects the AWS adapter. This lets consumers wire
-//     a single variable in their pipeline / `.env` instead of repeating the
-//     cloud name -- the cloud is already implied by which credential they
-//     set.
+//     `TERRA_AWS_ROLE_ARN` or `TERRA_AWS_PROFILE` selects the AWS adapter.
+//     This lets consumers wire a single variable in their pipeline / `.env`
+//     instead of repeating the cloud name -- the cloud is already implied by
+//     which credential they set.
 //  3. If both credential variables are populated and `TERRA_CLOUD` is empty,
 //     emit a warning and return nil rather than guessing -- the operator is
 //     ambiguous, ask them to be explicit.
@@ -40,12 +58,12 @@ func NewCLI(settings *Settings) CLI {
 	}
 
 	azureSet := settings.TerraAzureSubscriptionID != ""
-	awsSet := settings.TerraAwsRoleArn != ""
+	awsSet := settings.TerraAwsRoleArn != "" || settings.TerraAwsProfile != ""
 
 	switch {
 	case azureSet && awsSet:
 		logger.Warn(
-			"Both TERRA_AZURE_SUBSCRIPTION_ID and TERRA_AWS_ROLE_ARN are set but TERRA_CLOUD is empty; " +
+			"Both TERRA_AZURE_SUBSCRIPTION_ID and TERRA_AWS_ROLE_ARN/TERRA_AWS_PROFILE are set but TERRA_CLOUD is empty; " +
 				"set TERRA_CLOUD=azure or TERRA_CLOUD=aws to disambiguate -- " +
 				"account-switch commands will be skipped",
 		)
@@ -54,7 +72,7 @@ func NewCLI(settings *Settings) CLI {
 		logger.Debugf("Auto-detected Azure cloud from TERRA_AZURE_SUBSCRIPTION_ID")
 		return mapping["azure"]
 	case awsSet:
-		logger.Debugf("Auto-detected AWS cloud from TERRA_AWS_ROLE_ARN")
+		logger.Debugf("Auto-detected AWS cloud from TERRA_AWS_ROLE_ARN/TERRA_AWS_PROFILE")
 		return mapping["aws"]
 	default:
 		logger.Debugf("No cloud CLI found, avoiding to execute customized commands...")
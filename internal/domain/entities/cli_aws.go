@@ -1,9 +1,26 @@
 package entities
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
 type CLIAws struct {
 	settings *Settings
 }
 
+// awsAssumeRoleOutput mirrors the subset of `aws sts assume-role --output json` that carries
+// the temporary credentials.
+type awsAssumeRoleOutput struct {
+	Credentials struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		SessionToken    string `json:"SessionToken"`
+	} `json:"Credentials"`
+}
+
 func NewCLIAws(settings *Settings) *CLIAws {
 	return &CLIAws{settings: settings}
 }
@@ -12,12 +29,19 @@ func (it *CLIAws) GetName() string {
 	return "aws"
 }
 
+// CanChangeAccount reports whether a role to assume or a named profile is configured.
 func (it *CLIAws) CanChangeAccount() bool {
-	return it.settings.TerraAwsRoleArn != ""
+	return it.settings.TerraAwsRoleArn != "" || it.settings.TerraAwsProfile != ""
 }
 
+// GetCommandChangeAccount returns the assume-role command, chained on top of the named
+// profile when both are set, or nil for a bare profile which needs no role switch.
 func (it *CLIAws) GetCommandChangeAccount() []string {
-	return []string{
+	if it.settings.TerraAwsRoleArn == "" {
+		return nil
+	}
+
+	command := []string{
 		"sts",
 		"assume-role",
 		"--role-arn",
@@ -25,4 +49,70 @@ func (it *CLIAws) GetCommandChangeAccount() []string {
 		"--role-session-name",
 		"session1",
 	}
+	if it.settings.TerraAwsProfile != "" {
+		command = append(command, "--profile", it.settings.TerraAwsProfile)
+	}
+	return command
+}
+
+// CanLogin reports whether SSO mode is enabled and the profile's cached SSO token is
+// missing or expired, so `aws sso login` only prompts when it is actually needed.
+func (it *CLIAws) CanLogin() bool {
+	return it.settings.TerraAwsSSO && !IsAwsSSOSessionValid(it.settings.TerraAwsProfile, time.Now())
+}
+
+// IsLoginGlobal returns false: `aws sso login` only refreshes the profile's cached token,
+// which every process using the profile needs anyway.
+func (it *CLIAws) IsLoginGlobal() bool {
+	return false
+}
+
+func (it *CLIAws) GetCommandLogin() ([]string, error) {
+	return []string{"sso", "login", "--profile", it.settings.TerraAwsProfile}, nil
+}
+
+// GetCommandAccountEnvironment returns the assume-role command with JSON output, so the
+// temporary credentials can be parsed instead of printed to the terminal. A bare profile
+// needs no command.
+func (it *CLIAws) GetCommandAccountEnvironment() []string {
+	command := it.GetCommandChangeAccount()
+	if command == nil {
+		return nil
+	}
+	return append(command, "--output", "json")
+}
+
+// GetAccountEnvironment parses the assume-role output into the standard AWS credential
+// variables understood by the AWS provider and the S3 backend. Without a role, it selects
+// the named profile through AWS_PROFILE instead.
+func (it *CLIAws) GetAccountEnvironment(output string) ([]string, error) {
+	if it.settings.TerraAwsRoleArn == "" {
+		return []string{"AWS_PROFILE=" + it.settings.TerraAwsProfile}, nil
+	}
+
+	var parsed awsAssumeRoleOutput
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse assume-role output: %w", err)
+	}
+
+	credentials := parsed.Credentials
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("assume-role output does not contain credentials")
+	}
+
+	return []string{
+		"AWS_ACCESS_KEY_ID=" + credentials.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY=" + credentials.SecretAccessKey,
+		"AWS_SESSION_TOKEN=" + credentials.SessionToken,
+	}, nil
+}
+
+// GetCommandIdentity returns `aws sts get-caller-identity` for the named profile, when set,
+// which fails once the credentials (or the SSO session) expire.
+func (it *CLIAws) GetCommandIdentity() []string {
+	command := []string{"sts", "get-caller-identity"}
+	if it.settings.TerraAwsProfile != "" {
+		command = append(command, "--profile", it.settings.TerraAwsProfile)
+	}
+	return command
 }
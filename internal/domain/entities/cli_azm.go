@@ -1,5 +1,11 @@
 package entities
 
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
 type CLIAzm struct {
 	settings *Settings
 }
@@ -16,6 +22,109 @@ func (it *CLIAzm) CanChangeAccount() bool {
 	return it.settings.TerraAzureSubscriptionID != ""
 }
 
+// GetCommandChangeAccount returns nil when a service principal is configured without an
+// `az` login (see CanLogin): the azurerm provider then authenticates from the exported
+// ARM_* variables and there is no `az` session whose subscription could be switched.
 func (it *CLIAzm) GetCommandChangeAccount() []string {
+	if it.usesServicePrincipal() && !it.CanLogin() {
+		return nil
+	}
 	return []string{"account", "set", "--subscription", it.settings.TerraAzureSubscriptionID}
 }
+
+// CanLogin reports whether `az login --service-principal` was requested (TERRA_AZURE_LOGIN)
+// for a service principal. A client secret would have to be passed on az's command line,
+// visible to every user of the machine, so a principal authenticating with a secret only
+// gets the ARM_* variables and never an `az` session.
+func (it *CLIAzm) CanLogin() bool {
+	return it.settings.TerraAzureLogin && it.usesServicePrincipal() && !it.usesClientSecret()
+}
+
+// IsLoginGlobal returns true: `az login` replaces the session in ~/.azure, shared by every
+// `az` process of the user.
+func (it *CLIAzm) IsLoginGlobal() bool {
+	return true
+}
+
+// GetCommandLogin builds `az login --service-principal` for the federated token or the
+// certificate, preferring the token. The token is read by az from its file (the `@file`
+// argument syntax of the Azure CLI), so it never appears on the command line.
+func (it *CLIAzm) GetCommandLogin() ([]string, error) {
+	command := []string{
+		"login",
+		"--service-principal",
+		"--username", it.settings.TerraAzureClientID,
+		"--tenant", it.settings.TerraAzureTenantID,
+	}
+
+	switch {
+	case it.settings.TerraAzureFederatedTokenFile != "":
+		if _, err := os.Stat(it.settings.TerraAzureFederatedTokenFile); err != nil {
+			return nil, fmt.Errorf("failed to read federated token file: %w", err)
+		}
+		return append(command, "--federated-token", "@"+it.settings.TerraAzureFederatedTokenFile), nil
+	case it.settings.TerraAzureClientCertificatePath != "":
+		return append(command, "--certificate", it.settings.TerraAzureClientCertificatePath), nil
+	default:
+		return nil, errors.New(
+			"TERRA_AZURE_LOGIN requires TERRA_AZURE_CLIENT_CERTIFICATE_PATH or TERRA_AZURE_FEDERATED_TOKEN_FILE",
+		)
+	}
+}
+
+// GetCommandAccountEnvironment returns nil: the azurerm provider reads the subscription
+// from the environment, so no command (and no global `az account set`) is needed.
+func (it *CLIAzm) GetCommandAccountEnvironment() []string {
+	return nil
+}
+
+// GetAccountEnvironment returns the ARM_* variables read by the azurerm provider and
+// backend: the subscription, plus the tenant and service principal credentials when set.
+func (it *CLIAzm) GetAccountEnvironment(_ string) ([]string, error) {
+	environment := []string{"ARM_SUBSCRIPTION_ID=" + it.settings.TerraAzureSubscriptionID}
+	if it.settings.TerraAzureTenantID != "" {
+		environment = append(environment, "ARM_TENANT_ID="+it.settings.TerraAzureTenantID)
+	}
+	if !it.usesServicePrincipal() {
+		return environment, nil
+	}
+
+	environment = append(environment, "ARM_CLIENT_ID="+it.settings.TerraAzureClientID)
+	switch {
+	case it.settings.TerraAzureFederatedTokenFile != "":
+		environment = append(environment,
+			"ARM_USE_OIDC=true",
+			"ARM_OIDC_TOKEN_FILE_PATH="+it.settings.TerraAzureFederatedTokenFile,
+		)
+	case it.settings.TerraAzureClientCertificatePath != "":
+		environment = append(environment,
+			"ARM_CLIENT_CERTIFICATE_PATH="+it.settings.TerraAzureClientCertificatePath,
+		)
+	case it.settings.TerraAzureClientSecret != "":
+		environment = append(environment, "ARM_CLIENT_SECRET="+it.settings.TerraAzureClientSecret)
+	}
+
+	return environment, nil
+}
+
+// GetCommandIdentity returns `az account show`, or nil when a service principal is
+// configured without an `az` login, since the provider then needs no `az` session.
+func (it *CLIAzm) GetCommandIdentity() []string {
+	if it.usesServicePrincipal() && !it.CanLogin() {
+		return nil
+	}
+	return []string{"account", "show"}
+}
+
+// usesClientSecret reports whether the service principal authenticates with its client
+// secret, which is used only when neither a federated token nor a certificate is set.
+func (it *CLIAzm) usesClientSecret() bool {
+	return it.settings.TerraAzureFederatedTokenFile == "" &&
+		it.settings.TerraAzureClientCertificatePath == "" &&
+		it.settings.TerraAzureClientSecret != ""
+}
+
+// usesServicePrincipal reports whether a service principal (client ID) is configured.
+func (it *CLIAzm) usesServicePrincipal() bool {
+	return it.settings.TerraAzureClientID != ""
+}
@@ -3,6 +3,7 @@
 package entities_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/entities"
@@ -106,3 +107,313 @@ func TestNewCLI(t *testing.T) {
 		assert.Nil(t, cli, "ambiguous configuration (both credentials set, no TERRA_CLOUD) should NOT silently pick one -- operator must disambiguate via TERRA_CLOUD")
 	})
 }
+
+func TestCLIAws_GetAccountEnvironment(t *testing.T) {
+	t.Run("should return credential variables when assume-role output is valid", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsRoleArn: "arn:aws:iam::1:role/x"})
+		output := `{"Credentials":{"AccessKeyId":"AKIA","SecretAccessKey":"secret","SessionToken":"token"}}`
+
+		// WHEN:
+		environment, err := cli.GetAccountEnvironment(output)
+
+		// THEN:
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"AWS_ACCESS_KEY_ID=AKIA",
+			"AWS_SECRET_ACCESS_KEY=secret",
+			"AWS_SESSION_TOKEN=token",
+		}, environment)
+	})
+
+	t.Run("should return error when assume-role output has no credentials", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsRoleArn: "arn:aws:iam::1:role/x"})
+
+		// WHEN:
+		_, err := cli.GetAccountEnvironment(`{}`)
+
+		// THEN:
+		require.Error(t, err)
+	})
+
+	t.Run("should request JSON output when building the account environment command", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsRoleArn: "arn:aws:iam::1:role/x"})
+
+		// WHEN:
+		command := cli.GetCommandAccountEnvironment()
+
+		// THEN:
+		assert.Equal(t, []string{"--output", "json"}, command[len(command)-2:])
+		assert.Len(t, cli.GetCommandChangeAccount(), 6, "the account environment command must not alter the change account command")
+	})
+}
+
+func TestCLIAzm_GetAccountEnvironment(t *testing.T) {
+	t.Run("should pin the subscription without running any command", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{TerraAzureSubscriptionID: "sub-1"})
+
+		// WHEN:
+		environment, err := cli.GetAccountEnvironment("")
+
+		// THEN:
+		require.NoError(t, err)
+		assert.Nil(t, cli.GetCommandAccountEnvironment())
+		assert.Equal(t, []string{"ARM_SUBSCRIPTION_ID=sub-1"}, environment)
+	})
+}
+
+func TestCLIAzm_ServicePrincipal(t *testing.T) {
+	t.Run("should export OIDC variables when a federated token file is configured", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureSubscriptionID:     "sub-1",
+			TerraAzureTenantID:           "tenant-1",
+			TerraAzureClientID:           "client-1",
+			TerraAzureFederatedTokenFile: "/var/run/token",
+		})
+
+		// WHEN:
+		environment, err := cli.GetAccountEnvironment("")
+
+		// THEN:
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"ARM_SUBSCRIPTION_ID=sub-1",
+			"ARM_TENANT_ID=tenant-1",
+			"ARM_CLIENT_ID=client-1",
+			"ARM_USE_OIDC=true",
+			"ARM_OIDC_TOKEN_FILE_PATH=/var/run/token",
+		}, environment)
+	})
+
+	t.Run("should export client secret when a secret is configured", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureSubscriptionID: "sub-1",
+			TerraAzureTenantID:       "tenant-1",
+			TerraAzureClientID:       "client-1",
+			TerraAzureClientSecret:   "secret",
+		})
+
+		// WHEN:
+		environment, err := cli.GetAccountEnvironment("")
+
+		// THEN:
+		require.NoError(t, err)
+		assert.Contains(t, environment, "ARM_CLIENT_SECRET=secret")
+	})
+
+	t.Run("should skip az account set when a service principal is used without login", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureSubscriptionID: "sub-1",
+			TerraAzureTenantID:       "tenant-1",
+			TerraAzureClientID:       "client-1",
+			TerraAzureClientSecret:   "secret",
+		})
+
+		// WHEN:
+		command := cli.GetCommandChangeAccount()
+
+		// THEN:
+		assert.Nil(t, command)
+		assert.False(t, cli.CanLogin())
+	})
+
+	t.Run("should build service principal login with certificate when login is requested", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureSubscriptionID:        "sub-1",
+			TerraAzureTenantID:              "tenant-1",
+			TerraAzureClientID:              "client-1",
+			TerraAzureClientCertificatePath: "/certs/sp.pem",
+			TerraAzureLogin:                 true,
+		})
+
+		// WHEN:
+		command, err := cli.GetCommandLogin()
+
+		// THEN:
+		require.NoError(t, err)
+		assert.True(t, cli.CanLogin())
+		assert.Equal(t, []string{
+			"login", "--service-principal",
+			"--username", "client-1",
+			"--tenant", "tenant-1",
+			"--certificate", "/certs/sp.pem",
+		}, command)
+		assert.Equal(t, []string{"account", "set", "--subscription", "sub-1"}, cli.GetCommandChangeAccount())
+	})
+
+	t.Run("should read the federated token when building the login command", func(t *testing.T) {
+		// GIVEN:
+		tokenFile := t.TempDir() + "/token"
+		require.NoError(t, os.WriteFile(tokenFile, []byte("jwt-token\n"), 0o600))
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureTenantID:           "tenant-1",
+			TerraAzureClientID:           "client-1",
+			TerraAzureFederatedTokenFile: tokenFile,
+			TerraAzureLogin:              true,
+		})
+
+		// WHEN:
+		command, err := cli.GetCommandLogin()
+
+		// THEN:
+		require.NoError(t, err)
+		assert.Equal(t, []string{"--federated-token", "@" + tokenFile}, command[len(command)-2:])
+		assert.NotContains(t, command, "jwt-token")
+	})
+
+	t.Run("should never log in with a client secret", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureSubscriptionID: "sub-1",
+			TerraAzureTenantID:       "tenant-1",
+			TerraAzureClientID:       "client-1",
+			TerraAzureClientSecret:   "secret",
+			TerraAzureLogin:          true,
+		})
+
+		// WHEN:
+		canLogin := cli.CanLogin()
+
+		// THEN:
+		assert.False(t, canLogin)
+		assert.Nil(t, cli.GetCommandChangeAccount())
+		assert.Nil(t, cli.GetCommandIdentity())
+		environment, err := cli.GetAccountEnvironment("")
+		require.NoError(t, err)
+		assert.Contains(t, environment, "ARM_CLIENT_SECRET=secret")
+	})
+
+	t.Run("should return error when no service principal credential is configured", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureTenantID: "tenant-1",
+			TerraAzureClientID: "client-1",
+			TerraAzureLogin:    true,
+		})
+
+		// WHEN:
+		_, err := cli.GetCommandLogin()
+
+		// THEN:
+		require.Error(t, err)
+	})
+}
+
+func TestCLIAws_Profile(t *testing.T) {
+	t.Run("should export AWS_PROFILE without any command when only a profile is set", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsProfile: "dev"})
+
+		// WHEN:
+		environment, err := cli.GetAccountEnvironment("")
+
+		// THEN:
+		require.NoError(t, err)
+		assert.True(t, cli.CanChangeAccount())
+		assert.Nil(t, cli.GetCommandChangeAccount())
+		assert.Nil(t, cli.GetCommandAccountEnvironment())
+		assert.Equal(t, []string{"AWS_PROFILE=dev"}, environment)
+	})
+
+	t.Run("should chain the role on top of the profile when both are set", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsProfile: "dev", TerraAwsRoleArn: "arn:aws:iam::1:role/x"})
+
+		// WHEN:
+		command := cli.GetCommandAccountEnvironment()
+
+		// THEN:
+		assert.Equal(t, []string{
+			"sts", "assume-role",
+			"--role-arn", "arn:aws:iam::1:role/x",
+			"--role-session-name", "session1",
+			"--profile", "dev",
+			"--output", "json",
+		}, command)
+	})
+
+	t.Run("should request SSO login when the SSO token cache is missing", func(t *testing.T) {
+		// GIVEN:
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("AWS_CONFIG_FILE", "")
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsProfile: "dev", TerraAwsSSO: true})
+
+		// WHEN:
+		command, err := cli.GetCommandLogin()
+
+		// THEN:
+		require.NoError(t, err)
+		assert.True(t, cli.CanLogin())
+		assert.Equal(t, []string{"sso", "login", "--profile", "dev"}, command)
+	})
+
+	t.Run("should not request SSO login when SSO mode is disabled", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsProfile: "dev"})
+
+		// WHEN:
+		canLogin := cli.CanLogin()
+
+		// THEN:
+		assert.False(t, canLogin)
+	})
+
+	t.Run("should auto-detect AWS when only TERRA_AWS_PROFILE is set", func(t *testing.T) {
+		// GIVEN:
+		settings := &entities.Settings{TerraAwsProfile: "dev"}
+
+		// WHEN:
+		cli := entities.NewCLI(settings)
+
+		// THEN:
+		require.NotNil(t, cli)
+		assert.Equal(t, "aws", cli.GetName())
+	})
+}
+
+func TestCLI_GetCommandIdentity(t *testing.T) {
+	t.Run("should check the caller identity of the named profile when using AWS", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAws(&entities.Settings{TerraAwsProfile: "dev"})
+
+		// WHEN:
+		command := cli.GetCommandIdentity()
+
+		// THEN:
+		assert.Equal(t, []string{"sts", "get-caller-identity", "--profile", "dev"}, command)
+	})
+
+	t.Run("should show the active account when using the Azure CLI session", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{TerraAzureSubscriptionID: "sub-1"})
+
+		// WHEN:
+		command := cli.GetCommandIdentity()
+
+		// THEN:
+		assert.Equal(t, []string{"account", "show"}, command)
+	})
+
+	t.Run("should not need any session when an Azure service principal skips the login", func(t *testing.T) {
+		// GIVEN:
+		cli := entities.NewCLIAzm(&entities.Settings{
+			TerraAzureSubscriptionID: "sub-1",
+			TerraAzureTenantID:       "tenant-1",
+			TerraAzureClientID:       "client-1",
+			TerraAzureClientSecret:   "secret",
+		})
+
+		// WHEN:
+		command := cli.GetCommandIdentity()
+
+		// THEN:
+		assert.Nil(t, command)
+	})
+}
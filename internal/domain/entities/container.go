@@ -12,5 +12,8 @@ func RegisterProviders(container *dig.Container) error {
 	if err := container.Provide(NewCLI); err != nil {
 		return err
 	}
+	if err := container.Provide(NewRedactor); err != nil {
+		return err
+	}
 	return nil
 }
@@ -12,6 +12,34 @@ type Dependency struct {
 	BinaryURL         string
 	RegexVersion      string
 	FormattingCommand []string
+	// FormattingCheckCommand verifies the formatting without rewriting anything, printing
+	// the diff and exiting non-zero when a file is not formatted; `terra format --check`
+	// skips dependencies without one.
+	FormattingCheckCommand []string
+	// FormattingFileCommand formats the single file appended to it, so that a run formats
+	// only the files it reads; FormattingFileExtensions are the extensions it handles.
+	FormattingFileCommand    []string
+	FormattingFileExtensions []string
+	// Engine is the TERRA_ENGINE value selecting the dependency as the engine running the
+	// modules; dependencies required whatever the engine leave it empty.
+	Engine string
+	// ChecksumsURL points to the SHA256SUMS file of a release (formatted with the version
+	// like BinaryURL); downloads are not verified when it is empty.
+	ChecksumsURL string
+	// SignatureURL points to the detached signature of the SHA256SUMS file, verified with
+	// the armored SigningKey; the checksums are trusted as is when it is empty.
+	SignatureURL string
+	SigningKey   string
+}
+
+// GetChecksumsURL returns the URL of the release's SHA256SUMS file.
+func (d *Dependency) GetChecksumsURL(version string) string {
+	return fmt.Sprintf(d.ChecksumsURL, version)
+}
+
+// GetSignatureURL returns the URL of the detached signature of the release's SHA256SUMS file.
+func (d *Dependency) GetSignatureURL(version string) string {
+	return fmt.Sprintf(d.SignatureURL, version)
 }
 
 // GetBinaryURL returns the binary URL with platform information dynamically inserted.
@@ -0,0 +1,19 @@
+package entities
+
+// DiagnosticStatus is the outcome of a single `terra doctor` check.
+type DiagnosticStatus string
+
+const (
+	DiagnosticPass DiagnosticStatus = "PASS"
+	DiagnosticWarn DiagnosticStatus = "WARN"
+	DiagnosticFail DiagnosticStatus = "FAIL"
+)
+
+// Diagnostic is the result of a `terra doctor` check: what was checked, what was found
+// and, unless it passed, how to fix it.
+type Diagnostic struct {
+	Check  string
+	Status DiagnosticStatus
+	Detail string
+	Fix    string
+}
@@ -0,0 +1,181 @@
+package entities
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/joho/godotenv"
+)
+
+// EncryptedEnvFileName is the encrypted counterpart of `.env`. A profile-specific file,
+// `.env.<profile>.enc`, is read after it and overrides its values.
+const EncryptedEnvFileName = ".env.enc"
+
+const (
+	ageKeyFileVariable  = "TERRA_AGE_KEY_FILE"
+	sopsKeyFileVariable = "SOPS_AGE_KEY_FILE"
+	ageBinaryHeader     = "age-encryption.org/"
+	sopsVersionKey      = "sops_version="
+)
+
+// ReadEnvFiles reads `.env` in directory, when present, and adds the variables of its
+// encrypted files that `.env` does not set: a plain `.env` always wins over `.env.enc`
+// and `.env.<profile>.enc`, whether they are loaded for the whole run or for one module.
+func ReadEnvFiles(directory, profile string) (map[string]string, error) {
+	values := map[string]string{}
+
+	path := filepath.Join(directory, ".env")
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		if values, err = godotenv.Read(path); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	encrypted, err := ReadEncryptedEnvFiles(directory, profile)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range encrypted {
+		if _, found := values[key]; !found {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// ReadEncryptedEnvFiles decrypts `.env.enc` and `.env.<profile>.enc` in directory, when
+// present, and returns their merged variables. The plaintext only ever lives in memory.
+func ReadEncryptedEnvFiles(directory, profile string) (map[string]string, error) {
+	names := []string{EncryptedEnvFileName}
+	if profile != "" {
+		names = append(names, ".env."+profile+".enc")
+	}
+
+	values := map[string]string{}
+	for _, name := range names {
+		path := filepath.Join(directory, name)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		fileValues, err := ReadEncryptedEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(values, fileValues)
+	}
+
+	return values, nil
+}
+
+// ReadEncryptedEnvFile decrypts a single env file encrypted either with age (binary or
+// ASCII-armored) or with SOPS in its dotenv format, and parses the result.
+func ReadEncryptedEnvFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var plaintext []byte
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(ageBinaryHeader)) || bytes.HasPrefix(trimmed, []byte(armor.Header)):
+		plaintext, err = decryptAge(content)
+	case bytes.Contains(content, []byte(sopsVersionKey)):
+		plaintext, err = decryptSops(path)
+	default:
+		return nil, fmt.Errorf("%s is neither age- nor SOPS-encrypted; keep plaintext variables in .env", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+	defer clear(plaintext)
+
+	values, err := godotenv.Parse(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// decryptAge decrypts an age payload with the identities of the local key file.
+func decryptAge(content []byte) ([]byte, error) {
+	keyFile, err := ageKeyFile()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the age key file (set %s): %w", ageKeyFileVariable, err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the age key file %s: %w", keyFile, err)
+	}
+
+	var reader io.Reader = bytes.NewReader(content)
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte(armor.Header)) {
+		reader = armor.NewReader(reader)
+	}
+
+	decrypted, err := age.Decrypt(reader, identities...)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(decrypted)
+}
+
+// decryptSops runs `sops --decrypt`, capturing the plaintext from its standard output.
+// SOPS resolves its own keys (age, KMS, PGP); TERRA_AGE_KEY_FILE is forwarded as
+// SOPS_AGE_KEY_FILE unless that is already set.
+func decryptSops(path string) ([]byte, error) {
+	//nolint:gosec // the command is fixed and the path is an env file next to the module
+	cmd := exec.Command("sops", "--decrypt", "--input-type", "dotenv", "--output-type", "dotenv", path)
+	cmd.Env = os.Environ()
+	if keyFile, found := os.LookupEnv(ageKeyFileVariable); found {
+		if _, sopsFound := os.LookupEnv(sopsKeyFileVariable); !sopsFound {
+			cmd.Env = append(cmd.Env, sopsKeyFileVariable+"="+keyFile)
+		}
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("sops: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return output, nil
+}
+
+// ageKeyFile returns the age key file: TERRA_AGE_KEY_FILE, then SOPS_AGE_KEY_FILE, then
+// the default location used by SOPS (`<user config dir>/sops/age/keys.txt`).
+func ageKeyFile() (string, error) {
+	for _, variable := range []string{ageKeyFileVariable, sopsKeyFileVariable} {
+		if value := os.Getenv(variable); value != "" {
+			return value, nil
+		}
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the age key file (set %s): %w", ageKeyFileVariable, err)
+	}
+
+	return filepath.Join(configDir, "sops", "age", "keys.txt"), nil
+}
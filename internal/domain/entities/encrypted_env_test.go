@@ -0,0 +1,180 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAgeKeyFile writes a fresh age identity to a key file exported as TERRA_AGE_KEY_FILE.
+func newAgeKeyFile(t *testing.T) *age.X25519Identity {
+	t.Helper()
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte(identity.String()+"\n"), 0o600))
+	t.Setenv("TERRA_AGE_KEY_FILE", keyFile)
+	return identity
+}
+
+// writeAgeEncrypted encrypts content to the identity's recipient and writes it to path.
+func writeAgeEncrypted(t *testing.T, path, content string, identity *age.X25519Identity, armored bool) {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	var output io.WriteCloser = nopWriteCloser{&buffer}
+	if armored {
+		output = armor.NewWriter(&buffer)
+	}
+	writer, err := age.Encrypt(output, identity.Recipient())
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, output.Close())
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0o600))
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestReadEnvFiles(t *testing.T) {
+	t.Run("should let .env win over the encrypted files", func(t *testing.T) {
+		// GIVEN: A .env and a .env.enc both setting one variable
+		identity := newAgeKeyFile(t)
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, ".env"), []byte("TERRA_WORKSPACE=plain\n"), 0o600))
+		writeAgeEncrypted(t, filepath.Join(directory, entities.EncryptedEnvFileName),
+			"TERRA_WORKSPACE=encrypted\nTF_VAR_db_password=s3cr3t\n", identity, false)
+
+		// WHEN: Reading the env files
+		values, err := entities.ReadEnvFiles(directory, "")
+
+		// THEN: Should keep the plain value and add the encrypted-only variable
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"TERRA_WORKSPACE": "plain", "TF_VAR_db_password": "s3cr3t"}, values)
+	})
+
+	t.Run("should read only the encrypted files when there is no .env", func(t *testing.T) {
+		// GIVEN: A .env.prod.enc without a .env
+		identity := newAgeKeyFile(t)
+		directory := t.TempDir()
+		writeAgeEncrypted(t, filepath.Join(directory, ".env.prod.enc"), "TF_VAR_db_password=prod\n", identity, true)
+
+		// WHEN: Reading the env files for the prod profile
+		values, err := entities.ReadEnvFiles(directory, "prod")
+
+		// THEN: Should return the decrypted variables
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"TF_VAR_db_password": "prod"}, values)
+	})
+}
+
+func TestReadEncryptedEnvFiles(t *testing.T) {
+	t.Run("should decrypt a binary age file with the local key file", func(t *testing.T) {
+		// GIVEN: An age-encrypted .env.enc and the matching key file
+		identity := newAgeKeyFile(t)
+		directory := t.TempDir()
+		writeAgeEncrypted(t, filepath.Join(directory, entities.EncryptedEnvFileName),
+			"TF_VAR_db_password=s3cr3t\nTERRA_WORKSPACE=dev\n", identity, false)
+
+		// WHEN: Reading the encrypted env files
+		values, err := entities.ReadEncryptedEnvFiles(directory, "")
+
+		// THEN: Should return the decrypted variables
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"TF_VAR_db_password": "s3cr3t", "TERRA_WORKSPACE": "dev"}, values)
+	})
+
+	t.Run("should let the profile file override the shared file", func(t *testing.T) {
+		// GIVEN: An armored .env.enc and a .env.prod.enc redefining one variable
+		identity := newAgeKeyFile(t)
+		directory := t.TempDir()
+		writeAgeEncrypted(t, filepath.Join(directory, ".env.enc"),
+			"TF_VAR_db_password=shared\nTF_VAR_region=eu-west-1\n", identity, true)
+		writeAgeEncrypted(t, filepath.Join(directory, ".env.prod.enc"),
+			"TF_VAR_db_password=prod\n", identity, true)
+
+		// WHEN: Reading the encrypted env files for the prod profile
+		values, err := entities.ReadEncryptedEnvFiles(directory, "prod")
+
+		// THEN: Should merge both files with the profile file winning
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"TF_VAR_db_password": "prod", "TF_VAR_region": "eu-west-1"}, values)
+	})
+
+	t.Run("should return no variables when no encrypted file exists", func(t *testing.T) {
+		// GIVEN: A directory without encrypted env files
+
+		// WHEN: Reading the encrypted env files
+		values, err := entities.ReadEncryptedEnvFiles(t.TempDir(), "dev")
+
+		// THEN: Should return an empty map
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("should return error when the key file does not match", func(t *testing.T) {
+		// GIVEN: A file encrypted to a different identity than the key file
+		newAgeKeyFile(t)
+		other, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+		directory := t.TempDir()
+		writeAgeEncrypted(t, filepath.Join(directory, ".env.enc"), "TF_VAR_x=1\n", other, false)
+
+		// WHEN: Reading the encrypted env files
+		_, err = entities.ReadEncryptedEnvFiles(directory, "")
+
+		// THEN: Should fail to decrypt
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decrypt")
+	})
+
+	t.Run("should refuse a plaintext .env.enc", func(t *testing.T) {
+		// GIVEN: A .env.enc that was never encrypted
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, ".env.enc"), []byte("TF_VAR_x=1\n"), 0o600))
+
+		// WHEN: Reading the encrypted env files
+		_, err := entities.ReadEncryptedEnvFiles(directory, "")
+
+		// THEN: Should reject it instead of loading plaintext
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "neither age- nor SOPS-encrypted")
+	})
+
+	t.Run("should decrypt a SOPS dotenv file through the sops binary", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the fake sops binary is a shell script")
+		}
+		// GIVEN: A SOPS dotenv file and a sops binary that prints the plaintext
+		binDir := t.TempDir()
+		script := "#!/bin/sh\nprintf 'TF_VAR_db_password=s3cr3t\\n'\n"
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, "sops"), []byte(script), 0o700))
+		t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, ".env.enc"), []byte(
+			"TF_VAR_db_password=ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]\nsops_version=3.9.0\n",
+		), 0o600))
+
+		// WHEN: Reading the encrypted env files
+		values, err := entities.ReadEncryptedEnvFiles(directory, "")
+
+		// THEN: Should return the variables printed by sops
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"TF_VAR_db_password": "s3cr3t"}, values)
+	})
+}
@@ -0,0 +1,29 @@
+package entities
+
+const (
+	// EngineTerraform runs the modules with Terraform, the default engine.
+	EngineTerraform = "terraform"
+	// EngineTofu runs the modules with OpenTofu.
+	EngineTofu = "tofu"
+)
+
+// GetEngine returns the engine Terragrunt runs the modules with, Terraform unless
+// TERRA_ENGINE selects OpenTofu.
+func (s *Settings) GetEngine() string {
+	if s.TerraEngine == "" {
+		return EngineTerraform
+	}
+	return s.TerraEngine
+}
+
+// SelectEngineDependencies returns the dependencies required with the given engine: the
+// ones that are not an engine (e.g. Terragrunt) and the engine itself.
+func SelectEngineDependencies(dependencies []Dependency, engine string) []Dependency {
+	selected := make([]Dependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		if dependency.Engine == "" || dependency.Engine == engine {
+			selected = append(selected, dependency)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettings_GetEngine(t *testing.T) {
+	t.Run("should default to terraform when no engine is configured", func(t *testing.T) {
+		// GIVEN: Settings without an engine
+		settings := &entities.Settings{}
+
+		// WHEN: Getting the engine
+		engine := settings.GetEngine()
+
+		// THEN: Should select terraform
+		assert.Equal(t, entities.EngineTerraform, engine)
+	})
+
+	t.Run("should select the engine of the project configuration", func(t *testing.T) {
+		// GIVEN: A project configuration selecting OpenTofu
+		root := t.TempDir()
+		writeProjectConfig(t, root, "engine: tofu\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should select tofu
+		require.NoError(t, err)
+		assert.Equal(t, entities.EngineTofu, settings.GetEngine())
+	})
+
+	t.Run("should return an error when the engine is unknown", func(t *testing.T) {
+		// GIVEN: An engine that is neither terraform nor tofu
+		t.Setenv("TERRA_ENGINE", "pulumi")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the configuration
+		err := settings.LoadProjectConfig(t.TempDir())
+
+		// THEN: Should fail the validation
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TerraEngine")
+	})
+}
+
+func TestSelectEngineDependencies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should keep the selected engine and the dependencies that are not an engine", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Terraform, OpenTofu and Terragrunt
+		dependencies := []entities.Dependency{
+			{CLI: "terraform", Engine: entities.EngineTerraform},
+			{CLI: "tofu", Engine: entities.EngineTofu},
+			{CLI: "terragrunt"},
+		}
+
+		// WHEN: Selecting the OpenTofu engine
+		selected := entities.SelectEngineDependencies(dependencies, entities.EngineTofu)
+
+		// THEN: Should drop Terraform only
+		require.Len(t, selected, 2)
+		assert.Equal(t, "tofu", selected[0].CLI)
+		assert.Equal(t, "terragrunt", selected[1].CLI)
+	})
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package entities
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// IsFileLocked reports whether another process holds an flock(2) lock on the file, the way
+// Terragrunt locks the provider cache entries it is writing or reading.
+func IsFileLocked(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	fd := int(file.Fd()) //nolint:gosec // file descriptors fit in an int
+	if err = syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		return errors.Is(err, syscall.EWOULDBLOCK)
+	}
+	_ = syscall.Flock(fd, syscall.LOCK_UN)
+	return false
+}
@@ -0,0 +1,78 @@
+//go:build unit && !windows
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockFile holds an exclusive flock on path until the test ends, like a running terragrunt.
+func lockFile(t *testing.T, path string) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB))
+	t.Cleanup(func() { _ = file.Close() })
+}
+
+func TestIsFileLocked(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report a lock file held by another descriptor as locked", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A lock file held with flock
+		path := filepath.Join(t.TempDir(), "linux_amd64.lock")
+		lockFile(t, path)
+
+		// WHEN: Checking the lock
+		locked := entities.IsFileLocked(path)
+
+		// THEN: Should report it locked
+		assert.True(t, locked)
+	})
+
+	t.Run("should report a lock file left behind as unlocked", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A lock file nobody holds
+		path := filepath.Join(t.TempDir(), "linux_amd64.lock")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		// WHEN: Checking the lock
+		locked := entities.IsFileLocked(path)
+
+		// THEN: Should report it unlocked
+		assert.False(t, locked)
+	})
+}
+
+func TestScanCacheEntries_Locked(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should mark a provider version locked when one of its lock files is held", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A provider version whose platform lock file is held by a running terragrunt
+		root := t.TempDir()
+		entry := repositoryhelpers.HelperCreateCacheEntry(
+			t, root, "registry.terraform.io/hashicorp/aws/5.31.0/linux_amd64", 10, time.Now(),
+		)
+		lockFile(t, entry+".lock")
+
+		// WHEN: Scanning the provider cache
+		entries, err := entities.ScanCacheEntries(entities.CacheKindProvider, root)
+
+		// THEN: Should mark the version locked
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.True(t, entries[0].Locked)
+	})
+}
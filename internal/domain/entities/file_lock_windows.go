@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+)
+
+var (
+	procLockFileEx   = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+	procUnlockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("UnlockFileEx")
+)
+
+// IsFileLocked reports whether another process holds a LockFileEx lock on the file, the way
+// Terragrunt locks the provider cache entries it is writing or reading.
+func IsFileLocked(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	overlapped := new(syscall.Overlapped)
+	locked, _, _ := procLockFileEx.Call(
+		file.Fd(), lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0, uintptr(unsafe.Pointer(overlapped)),
+	)
+	if locked == 0 {
+		return true
+	}
+	_, _, _ = procUnlockFileEx.Call(file.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(overlapped)))
+	return false
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitChangedFiles returns the absolute paths of the files of the Git repository containing
+// directory that are modified, staged or untracked (ignored and deleted files left out).
+func GitChangedFiles(directory string) ([]string, error) {
+	topLevel, err := exec.Command("git", "-C", directory, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a Git repository: %w", directory, err)
+	}
+	root := filepath.FromSlash(strings.TrimSpace(string(topLevel)))
+
+	output, err := exec.Command(
+		"git", "-C", directory, "status", "--porcelain", "-z", "--untracked-files=all",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the changed files of %s: %w", root, err)
+	}
+
+	// Each entry is "XY path", followed by the original path for renames and copies
+	var files []string
+	fields := bytes.Split(output, []byte{0})
+	for index := 0; index < len(fields); index++ {
+		const statusLength = 3
+		entry := string(fields[index])
+		if len(entry) <= statusLength {
+			continue
+		}
+		status := entry[:2]
+		if strings.ContainsAny(status, "RC") {
+			index++
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(entry[statusLength:]))
+		if _, statErr := os.Stat(path); statErr == nil {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,75 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperRunGit(t *testing.T, directory string, arguments ...string) {
+	t.Helper()
+	command := exec.Command("git", append([]string{
+		"-C", directory, "-c", "user.name=terra", "-c", "user.email=terra@example.com",
+	}, arguments...)...)
+	output, err := command.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestGitChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the modified, staged, renamed and untracked files", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A repository with a file of each kind of change, an ignored and a deleted one
+		root, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		helperRunGit(t, root, "init", "-q")
+		helperWriteFile(t, filepath.Join(root, ".gitignore"), "*.tfstate\n")
+		helperWriteFile(t, filepath.Join(root, "modified.tf"), "a")
+		helperWriteFile(t, filepath.Join(root, "staged.tf"), "a")
+		helperWriteFile(t, filepath.Join(root, "renamed.tf"), "a")
+		helperWriteFile(t, filepath.Join(root, "deleted.tf"), "a")
+		helperWriteFile(t, filepath.Join(root, "unchanged.tf"), "a")
+		helperRunGit(t, root, "add", ".")
+		helperRunGit(t, root, "commit", "-q", "-m", "initial")
+		helperWriteFile(t, filepath.Join(root, "modified.tf"), "b")
+		helperWriteFile(t, filepath.Join(root, "staged.tf"), "b")
+		helperRunGit(t, root, "add", "staged.tf")
+		helperRunGit(t, root, "mv", "renamed.tf", "moved.tf")
+		require.NoError(t, os.Remove(filepath.Join(root, "deleted.tf")))
+		helperWriteFile(t, filepath.Join(root, "live", "new.tf"), "a")
+		helperWriteFile(t, filepath.Join(root, "live", "terraform.tfstate"), "a")
+
+		// WHEN: Listing the changed files from a subdirectory
+		files, err := entities.GitChangedFiles(filepath.Join(root, "live"))
+
+		// THEN: Should return the changed files of the whole repository
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			filepath.Join(root, "modified.tf"),
+			filepath.Join(root, "staged.tf"),
+			filepath.Join(root, "moved.tf"),
+			filepath.Join(root, "live", "new.tf"),
+		}, files)
+	})
+
+	t.Run("should return error when the directory is not in a Git repository", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A directory outside any repository
+		directory := t.TempDir()
+
+		// WHEN: Listing its changed files
+		_, err := entities.GitChangedFiles(directory)
+
+		// THEN: Should report it
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not inside a Git repository")
+	})
+}
@@ -0,0 +1,172 @@
+package entities
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// InstallHistoryFileName is the JSON Lines file, under ~/.cache/terra, recording every
+// install, update and rollback of terra and its dependencies.
+const InstallHistoryFileName = "history.jsonl"
+
+// PreviousBinarySuffix is appended to an installed binary's path to keep the binary it
+// replaced, e.g. ~/.local/bin/terraform.prev.
+const PreviousBinarySuffix = ".prev"
+
+const (
+	InstallActionInstall    = "install"
+	InstallActionSelfUpdate = "self-update"
+	InstallActionRollback   = "rollback"
+)
+
+// InstallRecord is one line of the install history: which version of a tool was
+// installed where and when, and the version it replaced.
+type InstallRecord struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Tool     string    `json:"tool"`
+	Version  string    `json:"version,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+	Path     string    `json:"path"`
+}
+
+// GetInstallHistoryPath returns the install history file, ~/.cache/terra/history.jsonl.
+func GetInstallHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".cache", "terra", InstallHistoryFileName), nil
+}
+
+// AppendInstallRecord adds the record to the install history, stamping it with the
+// current time when it has none.
+func AppendInstallRecord(record InstallRecord) error {
+	path, err := GetInstallHistoryPath()
+	if err != nil {
+		return err
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode the install record: %w", err)
+	}
+	// nosemgrep: go.lang.correctness.permissions.file_permission.incorrect-default-permission
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// LastInstallRecord returns the latest record of the install history for the tool at path,
+// and whether there is one. Its Previous is the version of the binary kept aside by that
+// install, update or rollback, which the next rollback restores.
+func LastInstallRecord(tool, path string) (InstallRecord, bool, error) {
+	historyPath, err := GetInstallHistoryPath()
+	if err != nil {
+		return InstallRecord{}, false, err
+	}
+
+	file, err := os.Open(historyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return InstallRecord{}, false, nil
+	}
+	if err != nil {
+		return InstallRecord{}, false, fmt.Errorf("failed to open %s: %w", historyPath, err)
+	}
+	defer file.Close()
+
+	var last InstallRecord
+	found := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record InstallRecord
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue // a line cut short by an interrupted write
+		}
+		if record.Tool == tool && record.Path == path {
+			last, found = record, true
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return InstallRecord{}, false, fmt.Errorf("failed to read %s: %w", historyPath, err)
+	}
+	return last, found, nil
+}
+
+// KeepPreviousBinary moves the binary at path aside to its PreviousBinarySuffix path,
+// replacing the one kept by an earlier install. It reports whether there was a binary
+// to keep.
+func KeepPreviousBinary(path string) (bool, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err := os.Rename(path, path+PreviousBinarySuffix); err != nil {
+		return false, fmt.Errorf("failed to keep the previous binary %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// ReplaceBinary moves the binary staged by an install to path. With keepPrevious, the
+// binary it replaces is kept by KeepPreviousBinary first, and put back at path when the
+// staged binary cannot be moved in.
+func ReplaceBinary(staged, path string, keepPrevious bool) error {
+	kept := false
+	if keepPrevious {
+		var err error
+		if kept, err = KeepPreviousBinary(path); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(staged, path); err != nil {
+		if kept {
+			_ = os.Rename(path+PreviousBinarySuffix, path)
+		}
+		return fmt.Errorf("failed to move %s to %s: %w", staged, path, err)
+	}
+	return nil
+}
+
+// SwapPreviousBinary puts the binary kept by KeepPreviousBinary back at path and keeps
+// the one it replaces in its place, so a second rollback undoes the first.
+func SwapPreviousBinary(path string) error {
+	previous := path + PreviousBinarySuffix
+	if _, err := os.Stat(previous); err != nil {
+		return fmt.Errorf("no previous binary to roll back to: %w", err)
+	}
+
+	swap := path + ".swap"
+	if err := os.Rename(previous, swap); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", previous, err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err = os.Rename(path, previous); err != nil {
+			_ = os.Rename(swap, previous)
+			return fmt.Errorf("failed to keep %s: %w", path, err)
+		}
+	}
+	if err := os.Rename(swap, path); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", path, err)
+	}
+	return nil
+}
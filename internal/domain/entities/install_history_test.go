@@ -0,0 +1,231 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendInstallRecord(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because the history lives under the HOME set here
+
+	t.Run("should append one JSON line per record under the home cache when called", func(t *testing.T) {
+		// GIVEN: An empty home directory
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+
+		// WHEN: Recording an install and its rollback
+		require.NoError(t, entities.AppendInstallRecord(entities.InstallRecord{
+			Action: entities.InstallActionInstall, Tool: "terraform", Version: "1.9.8", Previous: "1.9.7", Path: "/bin/terraform",
+		}))
+		require.NoError(t, entities.AppendInstallRecord(entities.InstallRecord{
+			Action: entities.InstallActionRollback, Tool: "terraform", Version: "1.9.7", Previous: "1.9.8", Path: "/bin/terraform",
+		}))
+
+		// THEN: Should find both records, in order and timestamped
+		file, err := os.Open(filepath.Join(home, ".cache", "terra", entities.InstallHistoryFileName))
+		require.NoError(t, err)
+		defer file.Close()
+		var records []entities.InstallRecord
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record entities.InstallRecord
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		require.Len(t, records, 2)
+		assert.Equal(t, entities.InstallActionInstall, records[0].Action)
+		assert.Equal(t, "1.9.7", records[0].Previous)
+		assert.Equal(t, entities.InstallActionRollback, records[1].Action)
+		assert.False(t, records[1].Time.IsZero())
+	})
+}
+
+func TestLastInstallRecord(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because the history lives under the HOME set here
+
+	t.Run("should return the latest record of the tool at the path when it was recorded", func(t *testing.T) {
+		// GIVEN: A self-update of terra followed by installs of other binaries
+		t.Setenv("HOME", t.TempDir())
+		require.NoError(t, entities.AppendInstallRecord(entities.InstallRecord{
+			Action: entities.InstallActionSelfUpdate, Tool: "terra", Version: "1.5.0", Previous: "1.4.0", Path: "/bin/terra",
+		}))
+		require.NoError(t, entities.AppendInstallRecord(entities.InstallRecord{
+			Action: entities.InstallActionInstall, Tool: "terraform", Version: "1.9.8", Previous: "1.9.7", Path: "/bin/terraform",
+		}))
+		require.NoError(t, entities.AppendInstallRecord(entities.InstallRecord{
+			Action: entities.InstallActionSelfUpdate, Tool: "terra", Version: "9.9.9", Previous: "9.9.8", Path: "/other/terra",
+		}))
+
+		// WHEN: Looking up the last record of terra at /bin/terra
+		record, found, err := entities.LastInstallRecord("terra", "/bin/terra")
+
+		// THEN: Should return the self-update with the versions it recorded
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, entities.InstallActionSelfUpdate, record.Action)
+		assert.Equal(t, "1.5.0", record.Version)
+		assert.Equal(t, "1.4.0", record.Previous)
+	})
+
+	t.Run("should report no record when there is no history", func(t *testing.T) {
+		// GIVEN: An empty home directory
+		t.Setenv("HOME", t.TempDir())
+
+		// WHEN: Looking up the last record of terra
+		_, found, err := entities.LastInstallRecord("terra", "/bin/terra")
+
+		// THEN: Should find nothing
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestKeepPreviousBinary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should move the binary to its previous path when it exists", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An installed binary
+		path := filepath.Join(t.TempDir(), "terraform")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+
+		// WHEN: Keeping it before an install
+		kept, err := entities.KeepPreviousBinary(path)
+
+		// THEN: Should find it at the previous path only
+		require.NoError(t, err)
+		assert.True(t, kept)
+		content, err := os.ReadFile(path + entities.PreviousBinarySuffix)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("should keep nothing when the binary is not installed", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: No installed binary
+		path := filepath.Join(t.TempDir(), "terraform")
+
+		// WHEN: Keeping it before an install
+		kept, err := entities.KeepPreviousBinary(path)
+
+		// THEN: Should report there was nothing to keep
+		require.NoError(t, err)
+		assert.False(t, kept)
+		assert.NoFileExists(t, path+entities.PreviousBinarySuffix)
+	})
+}
+
+func TestReplaceBinary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should move the staged binary in and keep the one it replaces", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An installed binary and a staged one
+		dir := t.TempDir()
+		path, staged := filepath.Join(dir, "terraform"), filepath.Join(dir, "staged")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+		require.NoError(t, os.WriteFile(staged, []byte("new"), 0o600))
+
+		// WHEN: Replacing the installed binary
+		err := entities.ReplaceBinary(staged, path, true)
+
+		// THEN: Should install the staged binary and keep the old one
+		require.NoError(t, err)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+		content, err = os.ReadFile(path + entities.PreviousBinarySuffix)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+	})
+
+	t.Run("should leave the kept binary alone when not keeping the one it replaces", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A reinstall of the same version, with a binary kept by the install before
+		dir := t.TempDir()
+		path, staged := filepath.Join(dir, "terraform"), filepath.Join(dir, "staged")
+		require.NoError(t, os.WriteFile(path, []byte("current"), 0o600))
+		require.NoError(t, os.WriteFile(path+entities.PreviousBinarySuffix, []byte("previous"), 0o600))
+		require.NoError(t, os.WriteFile(staged, []byte("current"), 0o600))
+
+		// WHEN: Replacing the installed binary without keeping it
+		err := entities.ReplaceBinary(staged, path, false)
+
+		// THEN: Should still be able to roll back to the previous binary
+		require.NoError(t, err)
+		content, err := os.ReadFile(path + entities.PreviousBinarySuffix)
+		require.NoError(t, err)
+		assert.Equal(t, "previous", string(content))
+	})
+
+	t.Run("should put the installed binary back when the staged binary cannot be moved in", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An installed binary and a staged binary that does not exist
+		dir := t.TempDir()
+		path := filepath.Join(dir, "terraform")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+
+		// WHEN: Replacing the installed binary
+		err := entities.ReplaceBinary(filepath.Join(dir, "missing"), path, true)
+
+		// THEN: Should report it and leave the installed binary in place
+		require.Error(t, err)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+		assert.NoFileExists(t, path+entities.PreviousBinarySuffix)
+	})
+}
+
+func TestSwapPreviousBinary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should swap the binary with the previous one when rolling back twice", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A binary and the one it replaced
+		path := filepath.Join(t.TempDir(), "terraform")
+		require.NoError(t, os.WriteFile(path, []byte("new"), 0o600))
+		require.NoError(t, os.WriteFile(path+entities.PreviousBinarySuffix, []byte("old"), 0o600))
+
+		// WHEN: Rolling back
+		require.NoError(t, entities.SwapPreviousBinary(path))
+
+		// THEN: Should restore the old binary and keep the new one as previous
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "old", string(content))
+		content, _ = os.ReadFile(path + entities.PreviousBinarySuffix)
+		assert.Equal(t, "new", string(content))
+
+		// WHEN: Rolling back again
+		require.NoError(t, entities.SwapPreviousBinary(path))
+
+		// THEN: Should undo the first rollback
+		content, _ = os.ReadFile(path)
+		assert.Equal(t, "new", string(content))
+	})
+
+	t.Run("should return error when no previous binary was kept", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A binary without a previous one
+		path := filepath.Join(t.TempDir(), "terraform")
+		require.NoError(t, os.WriteFile(path, []byte("new"), 0o600))
+
+		// WHEN: Rolling back
+		err := entities.SwapPreviousBinary(path)
+
+		// THEN: Should refuse and leave the binary in place
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no previous binary")
+		assert.FileExists(t, path)
+	})
+}
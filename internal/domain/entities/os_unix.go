@@ -3,17 +3,11 @@
 package entities
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"os/exec"
-	"time"
 )
 
-const (
-	osOrwxGrxUx      = 0o755
-	operationTimeout = 30 * time.Second
-)
+const osOrwxGrxUx = 0o755
 
 type OSUnix struct{}
 
@@ -22,38 +16,24 @@ func (it *OSUnix) Download(url, tempFilePath string) error {
 }
 
 func (it *OSUnix) Extract(tempFilePath, destPath string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), operationTimeout)
-	defer cancel()
-	unzipCmd := exec.CommandContext(ctx, "unzip", "-o", tempFilePath, "-d", destPath)
-	unzipCmd.Stderr = os.Stderr
-	unzipCmd.Stdout = os.Stdout
-	err := unzipCmd.Run()
-	if err != nil {
-		err = fmt.Errorf("failed to perform decompressing using 'zip': %w", err)
+	if err := extractArchive(tempFilePath, destPath); err != nil {
+		return fmt.Errorf("failed to perform decompressing %s: %w", tempFilePath, err)
 	}
-	return err
+	return nil
 }
 
 func (it *OSUnix) Move(tempFilePath, destPath string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), operationTimeout)
-	defer cancel()
-	mvCmd := exec.CommandContext(ctx, "mv", tempFilePath, destPath)
-	err := mvCmd.Run()
-	if err != nil {
-		err = fmt.Errorf("failed to perform moving folder using 'mv': %w", err)
+	if err := moveFile(tempFilePath, destPath); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", tempFilePath, destPath, err)
 	}
-	return err
+	return nil
 }
 
 func (it *OSUnix) Remove(tempFilePath string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), operationTimeout)
-	defer cancel()
-	rmCmd := exec.CommandContext(ctx, "rm", tempFilePath)
-	err := rmCmd.Run()
-	if err != nil {
-		err = fmt.Errorf("failed to perform deleting folder using 'rm': %w", err)
+	if err := os.Remove(tempFilePath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", tempFilePath, err)
 	}
-	return err
+	return nil
 }
 
 func (it *OSUnix) MakeExecutable(filePath string) error {
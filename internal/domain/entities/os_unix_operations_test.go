@@ -46,6 +46,6 @@ func TestOSUnix_Move(t *testing.T) {
 
 		// then
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "mv")
+		assert.Contains(t, err.Error(), "failed to move")
 	})
 }
@@ -3,7 +3,6 @@ package entities
 import (
 	"fmt"
 	"os"
-	"os/exec"
 )
 
 type OSWindows struct{}
@@ -13,40 +12,24 @@ func (it *OSWindows) Download(url, tempFilePath string) error {
 }
 
 func (it *OSWindows) Extract(tempFilePath, destPath string) error {
-	unzipCmd := exec.Command(
-		"powershell",
-		"Expand-Archive",
-		"-Path",
-		tempFilePath,
-		"-DestinationPath",
-		destPath,
-		"-Force",
-	)
-	unzipCmd.Stderr = os.Stderr
-	unzipCmd.Stdout = os.Stdout
-	err := unzipCmd.Run()
-	if err != nil {
-		err = fmt.Errorf("failed to perform decompressing using 'powershell': %w", err)
+	if err := extractArchive(tempFilePath, destPath); err != nil {
+		return fmt.Errorf("failed to perform decompressing %s: %w", tempFilePath, err)
 	}
-	return err
+	return nil
 }
 
 func (it *OSWindows) Move(tempFilePath, destPath string) error {
-	mvCmd := exec.Command("move", tempFilePath, destPath)
-	err := mvCmd.Run()
-	if err != nil {
-		err = fmt.Errorf("failed to perform moving folder using 'move': %w", err)
+	if err := moveFile(tempFilePath, destPath); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", tempFilePath, destPath, err)
 	}
-	return err
+	return nil
 }
 
 func (it *OSWindows) Remove(tempFilePath string) error {
-	rmCmd := exec.Command("del", tempFilePath)
-	err := rmCmd.Run()
-	if err != nil {
-		err = fmt.Errorf("failed to perform deleting folder using 'del': %w", err)
+	if err := os.Remove(tempFilePath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", tempFilePath, err)
 	}
-	return err
+	return nil
 }
 
 func (it *OSWindows) MakeExecutable(_ string) error {
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectRootMarkers are the files and directories found at the root of a Terraform or
+// Terragrunt project.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var projectRootMarkers = []string{".git", ProjectConfigFileName, "root.hcl", "terragrunt.hcl"}
+
+// ErrNoProjectRoot is returned when a directory does not belong to any project.
+var ErrNoProjectRoot = errors.New("no project root found")
+
+// FindProjectRoot returns the nearest directory between targetPath and the filesystem root
+// holding one of projectRootMarkers. The home directory and the filesystem root are never
+// project roots, even when they hold a marker (e.g. a dotfiles repository), and the
+// search stops there.
+func FindProjectRoot(targetPath string) (string, error) {
+	directory, err := filepath.Abs(targetPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", targetPath, err)
+	}
+	home, _ := os.UserHomeDir()
+
+	for {
+		parent := filepath.Dir(directory)
+		if directory == home || parent == directory {
+			return "", fmt.Errorf("%w for %s: none of %s was found in it or its parents",
+				ErrNoProjectRoot, targetPath, strings.Join(projectRootMarkers, ", "))
+		}
+
+		for _, marker := range projectRootMarkers {
+			if _, statErr := os.Lstat(filepath.Join(directory, marker)); statErr == nil {
+				return directory, nil
+			}
+		}
+		directory = parent
+	}
+}
@@ -0,0 +1,62 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectRoot(t *testing.T) {
+	// NOTE: Cannot use t.Parallel() because these tests set HOME
+
+	t.Run("should return the nearest directory holding a project marker", func(t *testing.T) {
+		// GIVEN: A repository whose module sits two levels below its root
+		t.Setenv("HOME", t.TempDir())
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+		module := filepath.Join(root, "live", "dev")
+		require.NoError(t, os.MkdirAll(module, 0o755))
+
+		// WHEN: Looking for the project root of the module
+		found, err := entities.FindProjectRoot(module)
+
+		// THEN: Should return the repository root
+		require.NoError(t, err)
+		assert.Equal(t, root, found)
+	})
+
+	t.Run("should return error when no parent holds a project marker", func(t *testing.T) {
+		// GIVEN: A directory outside any project
+		t.Setenv("HOME", t.TempDir())
+		directory := t.TempDir()
+
+		// WHEN: Looking for its project root
+		_, err := entities.FindProjectRoot(directory)
+
+		// THEN: Should report that there is none
+		require.ErrorIs(t, err, entities.ErrNoProjectRoot)
+	})
+
+	t.Run("should return error when only the home directory holds a project marker", func(t *testing.T) {
+		// GIVEN: A home directory that is itself a Git repository (e.g. dotfiles)
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		require.NoError(t, os.Mkdir(filepath.Join(home, ".git"), 0o755))
+		checkouts := filepath.Join(home, "checkouts")
+		require.NoError(t, os.Mkdir(checkouts, 0o755))
+
+		// WHEN: Looking for the project root of a directory below it, or of the home itself
+		_, belowErr := entities.FindProjectRoot(checkouts)
+		_, homeErr := entities.FindProjectRoot(home)
+
+		// THEN: Should never treat the home directory as a project
+		require.ErrorIs(t, belowErr, entities.ErrNoProjectRoot)
+		require.ErrorIs(t, homeErr, entities.ErrNoProjectRoot)
+	})
+}
@@ -0,0 +1,211 @@
+package entities
+
+import (
+	"cmp"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// sensitiveFlags lists the command-line flags whose values carry secrets: Terraform
+// variables and backend settings, and the credentials terra passes to the cloud CLIs.
+// Both single-dash and double-dash variants are listed. "-var-file" is NOT included
+// because its value is a filename, not a secret.
+func sensitiveFlags() []string {
+	return []string{
+		"-var",
+		"--var",
+		"-backend-config",
+		"--backend-config",
+		"--password",
+		"--federated-token",
+	}
+}
+
+// Redactor masks secrets in logged command lines and streamed command output: the values
+// of sensitive flags and sensitive settings, the values of the TF_VAR_* variables listed
+// in TERRA_SECRET_VARIABLES, and every match of the TERRA_REDACT_PATTERNS expressions.
+// It reads the settings on every call, so values loaded after it was built (a profile,
+// the target path's project configuration) are still honored. A nil Redactor leaves
+// its input untouched.
+type Redactor struct {
+	settings    *Settings
+	environment map[string]string
+	patterns    *redactorPatterns
+}
+
+// redactorPatterns caches the compiled TERRA_REDACT_PATTERNS, recompiling them only when
+// the configured expressions change.
+type redactorPatterns struct {
+	mu       sync.Mutex
+	source   string
+	compiled []*regexp.Regexp
+}
+
+func NewRedactor(settings *Settings) *Redactor {
+	return &Redactor{settings: settings, patterns: &redactorPatterns{}}
+}
+
+// ForEnvironment returns a Redactor that also looks up secret variables in the given
+// "KEY=VALUE" entries, for child processes whose environment differs from terra's own
+// (e.g. a parallel module's .env).
+func (r *Redactor) ForEnvironment(environment []string) *Redactor {
+	if r == nil || len(environment) == 0 {
+		return r
+	}
+
+	values := make(map[string]string, len(environment))
+	for _, entry := range environment {
+		if key, value, found := strings.Cut(entry, "="); found {
+			values[key] = value
+		}
+	}
+	return &Redactor{settings: r.settings, environment: values, patterns: r.patterns}
+}
+
+// RedactArguments returns a copy of arguments fit for logging: the values of sensitive
+// flags are replaced, in both the "-var=key=secret" and "-var key=secret" forms, and
+// every remaining argument goes through Redact.
+func (r *Redactor) RedactArguments(arguments []string) []string {
+	redacted := RedactSensitiveFlags(arguments)
+	for index, argument := range redacted {
+		redacted[index] = r.Redact(argument)
+	}
+	return redacted
+}
+
+// Redact replaces every secret value and every pattern match in text.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || r.settings == nil || text == "" {
+		return text
+	}
+
+	for _, value := range r.secretValues() {
+		text = strings.ReplaceAll(text, value, redactedValue)
+	}
+
+	for _, pattern := range r.patterns.get(r.settings.TerraRedactPatterns) {
+		text = redactPattern(pattern, text)
+	}
+
+	return text
+}
+
+// secretValues returns the values of the settings tagged `sensitive:"true"` and of the
+// secret TF_VAR_* variables, longest first so a secret containing another one is masked
+// as a whole.
+func (r *Redactor) secretValues() []string {
+	var values []string
+	target := reflect.ValueOf(r.settings).Elem()
+	for index := range target.NumField() {
+		field := target.Type().Field(index)
+		if field.Tag.Get("sensitive") != "true" {
+			continue
+		}
+		switch value := target.Field(index).Interface().(type) {
+		case string:
+			if value != "" {
+				values = append(values, value)
+			}
+		case []string:
+			for _, item := range value {
+				if item != "" {
+					values = append(values, item)
+				}
+			}
+		}
+	}
+
+	for _, name := range r.settings.TerraSecretVariables {
+		key := profileVariablesPrefix + strings.TrimPrefix(name, profileVariablesPrefix)
+		value, found := r.environment[key]
+		if !found {
+			value = os.Getenv(key)
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	slices.SortFunc(values, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	return values
+}
+
+// get returns the compiled expressions. Invalid expressions are rejected when the
+// settings are loaded; any that still slip through (e.g. a module .env) are skipped.
+func (p *redactorPatterns) get(expressions []string) []*regexp.Regexp {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	source := strings.Join(expressions, "\x00")
+	if source == p.source {
+		return p.compiled
+	}
+
+	p.source = source
+	p.compiled = nil
+	for _, expression := range expressions {
+		compiled, err := regexp.Compile(expression)
+		if err != nil {
+			logger.Warnf("Ignoring invalid redaction pattern %q: %s", expression, err)
+			continue
+		}
+		p.compiled = append(p.compiled, compiled)
+	}
+	return p.compiled
+}
+
+// redactPattern replaces each match of pattern in text. When the expression has capture
+// groups, only the first group is replaced, so `password=(\S+)` keeps "password=" in the
+// output for context.
+func redactPattern(pattern *regexp.Regexp, text string) string {
+	if pattern.NumSubexp() == 0 {
+		return pattern.ReplaceAllLiteralString(text, redactedValue)
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		if start < 0 {
+			continue
+		}
+		builder.WriteString(text[last:start])
+		builder.WriteString(redactedValue)
+		last = end
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// RedactSensitiveFlags returns a copy of arguments in which the values of the sensitive
+// flags (see sensitiveFlags) are replaced with "<redacted>". Both "-var=key=secret" and
+// "-var key=secret" forms are handled.
+func RedactSensitiveFlags(arguments []string) []string {
+	flags := sensitiveFlags()
+	redacted := make([]string, 0, len(arguments))
+	redactNext := false
+
+	for _, argument := range arguments {
+		if redactNext {
+			redacted = append(redacted, redactedValue)
+			redactNext = false
+			continue
+		}
+		if flag, _, found := strings.Cut(argument, "="); found && slices.Contains(flags, flag) {
+			redacted = append(redacted, flag+"="+redactedValue)
+			continue
+		}
+		if slices.Contains(flags, argument) {
+			redactNext = true
+		}
+		redacted = append(redacted, argument)
+	}
+
+	return redacted
+}
@@ -0,0 +1,156 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_RedactArguments(t *testing.T) {
+	t.Run("should redact the values of sensitive flags in both forms", func(t *testing.T) {
+		// GIVEN: Arguments passing secrets through -var, -backend-config and --password
+		redactor := entities.NewRedactor(&entities.Settings{})
+		arguments := []string{
+			"apply", "-var=db_password=s3cr3t", "-backend-config", "access_key=AKIA",
+			"--password", "hunter2", "-var-file=prod.tfvars",
+		}
+
+		// WHEN: Redacting the arguments
+		redacted := redactor.RedactArguments(arguments)
+
+		// THEN: Should mask only the flag values and leave the original slice untouched
+		assert.Equal(t, []string{
+			"apply", "-var=<redacted>", "-backend-config", "<redacted>",
+			"--password", "<redacted>", "-var-file=prod.tfvars",
+		}, redacted)
+		assert.Equal(t, "-var=db_password=s3cr3t", arguments[1])
+	})
+
+	t.Run("should redact secret variable values anywhere in an argument", func(t *testing.T) {
+		// GIVEN: A secret variable whose value appears inside an unrelated argument
+		t.Setenv("TF_VAR_db_password", "s3cr3t")
+		redactor := entities.NewRedactor(&entities.Settings{TerraSecretVariables: []string{"db_password"}})
+
+		// WHEN: Redacting the arguments
+		redacted := redactor.RedactArguments([]string{"import", "aws_db.main", "id-s3cr3t"})
+
+		// THEN: Should mask the value
+		assert.Equal(t, []string{"import", "aws_db.main", "id-<redacted>"}, redacted)
+	})
+
+	t.Run("should return the arguments unchanged when the redactor is nil", func(t *testing.T) {
+		// GIVEN: A nil redactor
+		var redactor *entities.Redactor
+
+		// WHEN: Redacting the arguments
+		redacted := redactor.RedactArguments([]string{"plan", "-var=x=1"})
+
+		// THEN: Should still mask the sensitive flags
+		assert.Equal(t, []string{"plan", "-var=<redacted>"}, redacted)
+	})
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	t.Run("should mask only the first capture group of a configured pattern", func(t *testing.T) {
+		// GIVEN: A pattern with a capture group and one without
+		redactor := entities.NewRedactor(&entities.Settings{
+			TerraRedactPatterns: []string{`token=(\S+)`, `ghp_[A-Za-z0-9]+`},
+		})
+
+		// WHEN: Redacting a line matching both
+		redacted := redactor.Redact("token=abc123 and ghp_XYZ789 leaked\n")
+
+		// THEN: Should keep the pattern context and mask the matches
+		assert.Equal(t, "token=<redacted> and <redacted> leaked\n", redacted)
+	})
+
+	t.Run("should mask the values of sensitive settings", func(t *testing.T) {
+		// GIVEN: A configured Azure client secret
+		redactor := entities.NewRedactor(&entities.Settings{TerraAzureClientSecret: "azure-secret"})
+
+		// WHEN: Redacting output echoing it
+		redacted := redactor.Redact("login with azure-secret")
+
+		// THEN: Should mask the value
+		assert.Equal(t, "login with <redacted>", redacted)
+	})
+
+	t.Run("should mask every value of a sensitive list setting", func(t *testing.T) {
+		// GIVEN: Two webhook URLs carrying their tokens
+		redactor := entities.NewRedactor(&entities.Settings{
+			TerraWebhookURLs: []string{"https://hooks.slack.com/services/T0/B0/token", "https://ci.example.com/hook"},
+		})
+
+		// WHEN: Redacting output echoing them
+		redacted := redactor.Redact("posting to https://hooks.slack.com/services/T0/B0/token and https://ci.example.com/hook")
+
+		// THEN: Should mask both
+		assert.Equal(t, "posting to <redacted> and <redacted>", redacted)
+	})
+
+	t.Run("should look up secret variables in the child environment", func(t *testing.T) {
+		// GIVEN: A secret variable set only in a module's environment entries
+		settings := &entities.Settings{TerraSecretVariables: []string{"TF_VAR_api_key"}}
+		redactor := entities.NewRedactor(settings).ForEnvironment([]string{"TF_VAR_api_key=module-key"})
+
+		// WHEN: Redacting output echoing it
+		redacted := redactor.Redact("using module-key")
+
+		// THEN: Should mask the module's value
+		assert.Equal(t, "using <redacted>", redacted)
+	})
+
+	t.Run("should honor settings changed after the redactor was built", func(t *testing.T) {
+		// GIVEN: A redactor whose settings gain a pattern later
+		settings := &entities.Settings{}
+		redactor := entities.NewRedactor(settings)
+		assert.Equal(t, "key=abc", redactor.Redact("key=abc"))
+		settings.TerraRedactPatterns = []string{`key=(\w+)`}
+
+		// WHEN: Redacting again
+		redacted := redactor.Redact("key=abc")
+
+		// THEN: Should apply the new pattern
+		assert.Equal(t, "key=<redacted>", redacted)
+	})
+}
+
+func TestSettings_LoadProjectConfigWithRedactPatterns(t *testing.T) {
+	t.Run("should return error when a redaction pattern does not compile", func(t *testing.T) {
+		// GIVEN: A config file declaring an invalid regular expression
+		root := t.TempDir()
+		writeProjectConfig(t, root, "redact_patterns:\n  - \"token=(\"\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should reject the pattern
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TERRA_REDACT_PATTERNS")
+	})
+
+	t.Run("should redact secret variables in the described settings", func(t *testing.T) {
+		// GIVEN: A profile variable listed as secret
+		t.Setenv("TERRA_PROFILE", "prod")
+		root := t.TempDir()
+		writeProjectConfig(t, root,
+			"secret_variables: [db_password]\nprofiles:\n  prod:\n    variables:\n      db_password: s3cr3t\n")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(root))
+
+		// WHEN: Describing the settings
+		described := map[string]string{}
+		for _, setting := range settings.Describe() {
+			described[setting.Key] = setting.Value
+		}
+
+		// THEN: Should hide the secret value
+		assert.Equal(t, "<redacted>", described["TF_VAR_db_password"])
+		assert.Equal(t, "db_password", described["TERRA_SECRET_VARIABLES"])
+	})
+}
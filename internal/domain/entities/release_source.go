@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ReleaseIndexFileName is the file at the root of a mirror or an offline directory that
+// maps each dependency CLI to its latest version, e.g. {"terraform": "1.9.5"}.
+const ReleaseIndexFileName = "index.json"
+
+// IsOffline reports whether dependencies are installed from TERRA_OFFLINE_DIR, without any
+// network access.
+func (s *Settings) IsOffline() bool {
+	return s.TerraOfflineDir != ""
+}
+
+// HasReleaseSource reports whether dependencies are installed from a mirror or an offline
+// directory instead of their upstream release sites.
+func (s *Settings) HasReleaseSource() bool {
+	return s.IsOffline() || s.TerraMirrorURL != ""
+}
+
+// GetReleaseAssetLocation returns where the release asset published upstream at
+// upstreamURL is fetched from: upstreamURL itself, or `<cli>/<version>/<file name>` under
+// the offline directory or the mirror.
+func (s *Settings) GetReleaseAssetLocation(cli, version, upstreamURL string) string {
+	name := path.Base(upstreamURL)
+	switch {
+	case s.IsOffline():
+		return filepath.Join(s.TerraOfflineDir, cli, version, name)
+	case s.TerraMirrorURL != "":
+		return strings.TrimSuffix(s.TerraMirrorURL, "/") + "/" + path.Join(cli, version, name)
+	default:
+		return upstreamURL
+	}
+}
+
+// GetReleaseIndexLocation returns the location of the index of the offline directory or
+// the mirror, or "" when dependencies come from their upstream release sites.
+func (s *Settings) GetReleaseIndexLocation() string {
+	switch {
+	case s.IsOffline():
+		return filepath.Join(s.TerraOfflineDir, ReleaseIndexFileName)
+	case s.TerraMirrorURL != "":
+		return strings.TrimSuffix(s.TerraMirrorURL, "/") + "/" + ReleaseIndexFileName
+	default:
+		return ""
+	}
+}
+
+// ParseReleaseIndex reads the latest version of the dependency CLI from the content of an
+// index file.
+func ParseReleaseIndex(content []byte, cli string) (string, error) {
+	var index map[string]string
+	if err := json.Unmarshal(content, &index); err != nil {
+		return "", fmt.Errorf("malformed %s: %w", ReleaseIndexFileName, err)
+	}
+
+	version := strings.TrimPrefix(strings.TrimSpace(index[cli]), "v")
+	if version == "" {
+		return "", fmt.Errorf("%s lists no version for %s", ReleaseIndexFileName, cli)
+	}
+	if !IsExactVersion(version) {
+		return "", fmt.Errorf("%s lists an invalid version %q for %s", ReleaseIndexFileName, version, cli)
+	}
+	return version, nil
+}
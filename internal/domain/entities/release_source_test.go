@@ -0,0 +1,104 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettings_GetReleaseAssetLocation(t *testing.T) {
+	t.Parallel()
+
+	upstreamURL := "https://releases.hashicorp.com/terraform/1.9.5/terraform_1.9.5_linux_amd64.zip"
+
+	t.Run("should keep the upstream URL when no release source is configured", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Default settings
+		settings := &entities.Settings{}
+
+		// WHEN: Locating the asset
+		location := settings.GetReleaseAssetLocation("terraform", "1.9.5", upstreamURL)
+
+		// THEN: Should download from upstream
+		assert.Equal(t, upstreamURL, location)
+		assert.Empty(t, settings.GetReleaseIndexLocation())
+	})
+
+	t.Run("should locate the asset by tool and version under the mirror", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A mirror base URL with a trailing slash
+		settings := &entities.Settings{TerraMirrorURL: "https://artifactory.example.com/generic/terra/"}
+
+		// WHEN: Locating the asset and the index
+		location := settings.GetReleaseAssetLocation("terraform", "1.9.5", upstreamURL)
+
+		// THEN: Should use the mirror layout
+		assert.Equal(t,
+			"https://artifactory.example.com/generic/terra/terraform/1.9.5/terraform_1.9.5_linux_amd64.zip", location)
+		assert.Equal(t, "https://artifactory.example.com/generic/terra/index.json", settings.GetReleaseIndexLocation())
+	})
+
+	t.Run("should prefer the offline directory over the mirror", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Both an offline directory and a mirror
+		settings := &entities.Settings{
+			TerraOfflineDir: "/srv/terra-releases",
+			TerraMirrorURL:  "https://artifactory.example.com/generic/terra",
+		}
+
+		// WHEN: Locating the asset
+		location := settings.GetReleaseAssetLocation("terraform", "1.9.5", upstreamURL)
+
+		// THEN: Should read the local copy
+		assert.True(t, settings.IsOffline())
+		assert.Equal(t, filepath.Join("/srv/terra-releases", "terraform", "1.9.5", "terraform_1.9.5_linux_amd64.zip"), location)
+	})
+}
+
+func TestParseReleaseIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the version listed for the tool", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An index with a "v" prefixed version
+		content := []byte(`{"terraform": "1.9.5", "terragrunt": "v0.67.0"}`)
+
+		// WHEN: Parsing the terragrunt version
+		version, err := entities.ParseReleaseIndex(content, "terragrunt")
+
+		// THEN: Should return it without the prefix
+		require.NoError(t, err)
+		assert.Equal(t, "0.67.0", version)
+	})
+
+	t.Run("should return an error when the tool is not listed", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An index without tofu
+		content := []byte(`{"terraform": "1.9.5"}`)
+
+		// WHEN: Parsing the tofu version
+		_, err := entities.ParseReleaseIndex(content, "tofu")
+
+		// THEN: Should report the missing entry
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lists no version for tofu")
+	})
+
+	t.Run("should return an error when the index is not JSON", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A malformed index
+		content := []byte("terraform=1.9.5")
+
+		// WHEN: Parsing it
+		_, err := entities.ParseReleaseIndex(content, "terraform")
+
+		// THEN: Should report the malformed index
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "malformed index.json")
+	})
+}
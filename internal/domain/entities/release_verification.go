@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// ErrChecksumMismatch is returned when a downloaded file does not match its published
+// SHA-256 checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ParseChecksums reads a SHA256SUMS file ("<hex digest>  <file name>" per line) into the
+// digests keyed by file name.
+func ParseChecksums(content []byte) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("malformed checksum line %q", line)
+		}
+		// A leading "*" marks a file hashed in binary mode
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	return checksums, nil
+}
+
+// VerifySignature checks that signature, binary or armored, is a valid detached signature
+// of signed made by the armored public key. The key must be valid, neither revoked nor
+// expired, at the time of the check. A signing subkey that expired since only had to be
+// valid when it signed, as with GnuPG: HashiCorp rotates its subkeys, and the releases
+// signed before a rotation keep verifying.
+func VerifySignature(armoredKey string, signed, signature []byte) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return fmt.Errorf("failed to read the signing key: %w", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		block, decodeErr := armor.Decode(bytes.NewReader(signature))
+		if decodeErr != nil {
+			return fmt.Errorf("invalid signature: %w", decodeErr)
+		}
+		if signature, err = io.ReadAll(block.Body); err != nil {
+			return fmt.Errorf("invalid signature: %w", err)
+		}
+	}
+
+	details, signer, err := openpgp.VerifyDetachedSignature(
+		keyring, bytes.NewReader(signed), bytes.NewReader(signature), nil)
+	if errors.Is(err, pgperrors.ErrKeyExpired) {
+		err = checkExpiredSubkey(signer, details, time.Now())
+	}
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	return nil
+}
+
+// checkExpiredSubkey accepts a signature whose only fault is an expired key, when the
+// primary key is still valid and the subkey that made the signature was valid then.
+func checkExpiredSubkey(signer *openpgp.Entity, signature *packet.Signature, now time.Time) error {
+	primarySelfSignature, _ := signer.PrimarySelfSignature()
+	if primarySelfSignature == nil || signer.PrimaryKey.KeyExpired(primarySelfSignature, now) ||
+		signature.SigExpired(now) {
+		return pgperrors.ErrKeyExpired
+	}
+
+	for _, subkey := range signer.Subkeys {
+		if subkey.PublicKey.KeyId == *signature.IssuerKeyId {
+			if subkey.PublicKey.KeyExpired(subkey.Sig, signature.CreationTime) {
+				return pgperrors.ErrKeyExpired
+			}
+			return nil
+		}
+	}
+	return pgperrors.ErrKeyExpired
+}
+
+// VerifyFileChecksum checks that the SHA-256 digest of the file at path is expected.
+func VerifyFileChecksum(path, expected string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != strings.ToLower(expected) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+	return nil
+}
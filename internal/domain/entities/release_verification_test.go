@@ -0,0 +1,148 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/test/infrastructure/repositoryhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecksums(t *testing.T) {
+	t.Run("should map each file name to its digest", func(t *testing.T) {
+		// GIVEN: A SHA256SUMS file with a text and a binary mode entry
+		digest := strings.Repeat("ab", 32)
+		content := []byte(digest + "  terraform_1.9.5_linux_amd64.zip\n" + strings.ToUpper(digest) + " *terragrunt_linux_amd64\n")
+
+		// WHEN: Parsing it
+		checksums, err := entities.ParseChecksums(content)
+
+		// THEN: Should key the lowercase digests by file name
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"terraform_1.9.5_linux_amd64.zip": digest,
+			"terragrunt_linux_amd64":          digest,
+		}, checksums)
+	})
+
+	t.Run("should return an error when a line is malformed", func(t *testing.T) {
+		// GIVEN: A line without a SHA-256 digest
+		content := []byte("not-a-digest terraform.zip\n")
+
+		// WHEN: Parsing it
+		_, err := entities.ParseChecksums(content)
+
+		// THEN: Should reject the file
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Run("should accept a signature made by the key and reject tampered content", func(t *testing.T) {
+		// GIVEN: Checksums signed by a key
+		publicKey, sign := repositoryhelpers.HelperCreateSigningKey(t)
+		checksums := []byte("checksums\n")
+		signature := sign(checksums)
+
+		// WHEN: Verifying the original and a tampered copy
+		validErr := entities.VerifySignature(publicKey, checksums, signature)
+		tamperedErr := entities.VerifySignature(publicKey, []byte("tampered\n"), signature)
+
+		// THEN: Should only accept the original
+		require.NoError(t, validErr)
+		assert.ErrorContains(t, tamperedErr, "invalid signature")
+	})
+
+	t.Run("should reject a signature made by another key", func(t *testing.T) {
+		// GIVEN: Checksums signed by an unknown key
+		trustedKey, _ := repositoryhelpers.HelperCreateSigningKey(t)
+		_, signWithOtherKey := repositoryhelpers.HelperCreateSigningKey(t)
+		checksums := []byte("checksums\n")
+
+		// WHEN: Verifying them with the trusted key
+		err := entities.VerifySignature(trustedKey, checksums, signWithOtherKey(checksums))
+
+		// THEN: Should reject the signature
+		assert.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("should reject a signature made by a key that expired since", func(t *testing.T) {
+		// GIVEN: Checksums signed two days ago by a key valid for a day
+		publicKey, sign := repositoryhelpers.HelperCreateSigningKeyAt(t, time.Now().Add(-48*time.Hour), 24*time.Hour)
+		checksums := []byte("checksums\n")
+
+		// WHEN: Verifying them now
+		err := entities.VerifySignature(publicKey, checksums, sign(checksums))
+
+		// THEN: Should reject the expired key
+		assert.ErrorIs(t, err, pgperrors.ErrKeyExpired)
+	})
+
+	t.Run("should verify a real HashiCorp release with the embedded key", func(t *testing.T) {
+		// GIVEN: The SHA256SUMS of a HashiCorp release and its signature, made in 2021 with a
+		// signing subkey that expired in 2022
+		checksums, err := os.ReadFile(filepath.Join("testdata", "terraform-provider-null_3.1.0_SHA256SUMS"))
+		require.NoError(t, err)
+		signature, err := os.ReadFile(filepath.Join("testdata", "terraform-provider-null_3.1.0_SHA256SUMS.sig"))
+		require.NoError(t, err)
+
+		// WHEN: Verifying them, and a tampered copy of the checksums
+		validErr := entities.VerifySignature(entities.HashiCorpPublicKey, checksums, signature)
+		tamperedErr := entities.VerifySignature(
+			entities.HashiCorpPublicKey, []byte(strings.Replace(string(checksums), "fea4", "0000", 1)), signature)
+
+		// THEN: Should accept the release, signed while its subkey was valid, and reject the copy
+		require.NoError(t, validErr)
+		assert.ErrorContains(t, tamperedErr, "invalid signature")
+	})
+}
+
+func TestVerifyFileChecksum(t *testing.T) {
+	t.Run("should return a checksum mismatch when the file differs", func(t *testing.T) {
+		// GIVEN: A downloaded file and the digests of two contents
+		path := filepath.Join(t.TempDir(), "terraform.zip")
+		require.NoError(t, os.WriteFile(path, []byte("binary"), 0o600))
+		sums, err := entities.ParseChecksums(repositoryhelpers.HelperSHA256SUMS(map[string][]byte{
+			"original": []byte("binary"),
+			"other":    []byte("something else"),
+		}))
+		require.NoError(t, err)
+
+		// WHEN: Verifying the file against both
+		matchErr := entities.VerifyFileChecksum(path, sums["original"])
+		mismatchErr := entities.VerifyFileChecksum(path, sums["other"])
+
+		// THEN: Should only accept the matching digest
+		require.NoError(t, matchErr)
+		assert.ErrorIs(t, mismatchErr, entities.ErrChecksumMismatch)
+	})
+}
+
+func TestHashiCorpPublicKey(t *testing.T) {
+	t.Run("should embed HashiCorp's renewed release signing key", func(t *testing.T) {
+		// GIVEN: The embedded armored key, whose first copy expired in April 2026
+		renewedAfter := time.Date(2026, time.April, 21, 0, 0, 0, 0, time.UTC)
+
+		// WHEN: Reading it
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(entities.HashiCorpPublicKey))
+
+		// THEN: Should hold the key with ID 72D7468F, and its signing subkey, renewed
+		require.NoError(t, err)
+		require.Len(t, keyring, 1)
+		assert.Equal(t, "72D7468F", keyring[0].PrimaryKey.KeyIdShortString())
+		selfSignature, _ := keyring[0].PrimarySelfSignature()
+		assert.False(t, keyring[0].PrimaryKey.KeyExpired(selfSignature, renewedAfter))
+		signingKeys := keyring.KeysByIdUsage(0xC820C6D5CD27AB87, 0)
+		require.Len(t, signingKeys, 1)
+		assert.False(t, signingKeys[0].PublicKey.KeyExpired(signingKeys[0].SelfSignature, renewedAfter))
+	})
+}
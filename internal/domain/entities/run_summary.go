@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+	"time"
+)
+
+const (
+	// RunStatusSucceeded is the status of a module, or of a whole run, that succeeded.
+	RunStatusSucceeded = "succeeded"
+	// RunStatusFailed is the status of a module that failed, or of a run with a failed module.
+	RunStatusFailed = "failed"
+)
+
+// ModuleResult is the outcome of the command in one module of a run.
+type ModuleResult struct {
+	Path            string  `json:"path"`
+	Status          string  `json:"status"`
+	ExitCode        int     `json:"exit_code"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// NewModuleResult describes the command run in the module at path, which failed when err
+// is not nil.
+func NewModuleResult(path string, duration time.Duration, err error) ModuleResult {
+	result := ModuleResult{
+		Path:            path,
+		Status:          RunStatusSucceeded,
+		ExitCode:        ExitCode(err),
+		DurationSeconds: roundSeconds(duration),
+	}
+	if err != nil {
+		result.Status = RunStatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// RunSummary describes a finished single or parallel run, as sent to the webhooks.
+type RunSummary struct {
+	Command         string         `json:"command"`
+	TargetPath      string         `json:"target_path"`
+	Status          string         `json:"status"`
+	Succeeded       int            `json:"succeeded"`
+	Failed          int            `json:"failed"`
+	DurationSeconds float64        `json:"duration_seconds"`
+	GitSHA          string         `json:"git_sha,omitempty"`
+	User            string         `json:"user,omitempty"`
+	FinishedAt      time.Time      `json:"finished_at"`
+	Modules         []ModuleResult `json:"modules"`
+}
+
+// NewRunSummary summarizes the run of command in targetPath from the results of its
+// modules. The Git commit is the one checked out in targetPath, if any.
+func NewRunSummary(command, targetPath string, duration time.Duration, modules []ModuleResult) RunSummary {
+	summary := RunSummary{
+		Command:         command,
+		TargetPath:      targetPath,
+		Status:          RunStatusSucceeded,
+		DurationSeconds: roundSeconds(duration),
+		GitSHA:          gitHeadSHA(targetPath),
+		User:            currentUser(),
+		FinishedAt:      time.Now().UTC(),
+		Modules:         modules,
+	}
+	for _, module := range modules {
+		if module.Status == RunStatusFailed {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+	}
+	if summary.Failed > 0 {
+		summary.Status = RunStatusFailed
+	}
+	return summary
+}
+
+// gitHeadSHA returns the commit checked out in directory, or "" outside a Git repository.
+func gitHeadSHA(directory string) string {
+	output, err := exec.Command("git", "-C", directory, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// currentUser returns the name of the user running terra, falling back to the USER and
+// USERNAME variables where the user database is not available (e.g. in some containers).
+func currentUser() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+func roundSeconds(duration time.Duration) float64 {
+	const precision = 10 * time.Millisecond
+	return duration.Round(precision).Seconds()
+}
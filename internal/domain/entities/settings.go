@@ -1,42 +1,80 @@
 package entities
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	validator "github.com/go-playground/validator/v10"
-	"github.com/kelseyhightower/envconfig"
 	logger "github.com/sirupsen/logrus"
 )
 
 type Settings struct {
-	TerraCloud               string `envconfig:"TERRA_CLOUD"                  required:"false" validate:"omitempty,oneof=aws azure"`
-	TerraTerraformWorkspace  string `envconfig:"TERRA_WORKSPACE"              required:"false"`
-	TerraAwsRoleArn          string `envconfig:"TERRA_AWS_ROLE_ARN"           required:"false"`
-	TerraAzureSubscriptionID string `envconfig:"TERRA_AZURE_SUBSCRIPTION_ID"  required:"false"`
-	TerraModuleCacheDir      string `envconfig:"TERRA_MODULE_CACHE_DIR"       required:"false"`
-	TerraProviderCacheDir    string `envconfig:"TERRA_PROVIDER_CACHE_DIR"     required:"false"`
-	TerraNoCAS               bool   `envconfig:"TERRA_NO_CAS"                 required:"false"`
-	TerraNoProviderCache     bool   `envconfig:"TERRA_NO_PROVIDER_CACHE"      required:"false"`
-	TerraNoPartialParseCache bool   `envconfig:"TERRA_NO_PARTIAL_PARSE_CACHE" required:"false"`
-	TerraNoWorkspace         bool   `envconfig:"TERRA_NO_WORKSPACE"           required:"false"`
+	TerraProfile                    string   `envconfig:"TERRA_PROFILE"                       yaml:"profile"                       required:"false"`
+	TerraCloud                      string   `envconfig:"TERRA_CLOUD"                         yaml:"cloud"                         required:"false" validate:"omitempty,oneof=aws azure"`
+	TerraTerraformWorkspace         string   `envconfig:"TERRA_WORKSPACE"                     yaml:"workspace"                     required:"false"`
+	TerraAwsRoleArn                 string   `envconfig:"TERRA_AWS_ROLE_ARN"                  yaml:"aws_role_arn"                  required:"false"`
+	TerraAwsProfile                 string   `envconfig:"TERRA_AWS_PROFILE"                   yaml:"aws_profile"                   required:"false"`
+	TerraAwsSSO                     bool     `envconfig:"TERRA_AWS_SSO"                       yaml:"aws_sso"                       required:"false" validate:"excluded_without=TerraAwsProfile"`
+	TerraAzureSubscriptionID        string   `envconfig:"TERRA_AZURE_SUBSCRIPTION_ID"         yaml:"azure_subscription_id"         required:"false"`
+	TerraAzureTenantID              string   `envconfig:"TERRA_AZURE_TENANT_ID"               yaml:"azure_tenant_id"               required:"false" validate:"required_with=TerraAzureClientID"`
+	TerraAzureClientID              string   `envconfig:"TERRA_AZURE_CLIENT_ID"               yaml:"azure_client_id"               required:"false"`
+	TerraAzureClientSecret          string   `envconfig:"TERRA_AZURE_CLIENT_SECRET"           yaml:"azure_client_secret"           required:"false" sensitive:"true"`
+	TerraAzureClientCertificatePath string   `envconfig:"TERRA_AZURE_CLIENT_CERTIFICATE_PATH" yaml:"azure_client_certificate_path" required:"false"`
+	TerraAzureFederatedTokenFile    string   `envconfig:"TERRA_AZURE_FEDERATED_TOKEN_FILE"    yaml:"azure_federated_token_file"    required:"false"`
+	TerraAzureLogin                 bool     `envconfig:"TERRA_AZURE_LOGIN"                   yaml:"azure_login"                   required:"false"`
+	TerraModuleCacheDir             string   `envconfig:"TERRA_MODULE_CACHE_DIR"              yaml:"module_cache_dir"              required:"false"`
+	TerraProviderCacheDir           string   `envconfig:"TERRA_PROVIDER_CACHE_DIR"            yaml:"provider_cache_dir"            required:"false"`
+	TerraToolCacheDir               string   `envconfig:"TERRA_TOOL_CACHE_DIR"                yaml:"tool_cache_dir"                required:"false"`
+	TerraMirrorURL                  string   `envconfig:"TERRA_MIRROR_URL"                    yaml:"mirror_url"                    required:"false" validate:"omitempty,url"`
+	TerraOfflineDir                 string   `envconfig:"TERRA_OFFLINE_DIR"                   yaml:"offline_dir"                   required:"false"`
+	TerraNoCAS                      bool     `envconfig:"TERRA_NO_CAS"                        yaml:"no_cas"                        required:"false"`
+	TerraNoProviderCache            bool     `envconfig:"TERRA_NO_PROVIDER_CACHE"             yaml:"no_provider_cache"             required:"false"`
+	TerraNoPartialParseCache        bool     `envconfig:"TERRA_NO_PARTIAL_PARSE_CACHE"        yaml:"no_partial_parse_cache"        required:"false"`
+	TerraNoWorkspace                bool     `envconfig:"TERRA_NO_WORKSPACE"                  yaml:"no_workspace"                  required:"false"`
+	TerraNoFormat                   bool     `envconfig:"TERRA_NO_FORMAT"                     yaml:"no_format"                     required:"false"`
+	TerraParallelism                int      `envconfig:"TERRA_PARALLELISM"                   yaml:"parallelism"                   required:"false" validate:"min=0"`
+	TerraSkip                       []string `envconfig:"TERRA_SKIP"                          yaml:"skip"                          required:"false"`
+	TerraDiscoveryExclude           []string `envconfig:"TERRA_DISCOVERY_EXCLUDE"             yaml:"discovery_exclude"             required:"false"`
+	TerraSecretVariables            []string `envconfig:"TERRA_SECRET_VARIABLES"              yaml:"secret_variables"              required:"false"`
+	TerraRedactPatterns             []string `envconfig:"TERRA_REDACT_PATTERNS"               yaml:"redact_patterns"               required:"false"`
+	TerraEngine                     string   `envconfig:"TERRA_ENGINE"                        yaml:"engine"                        required:"false" validate:"omitempty,oneof=terraform tofu"`
+	TerraTerraformVersion           string   `envconfig:"TERRA_TERRAFORM_VERSION"             yaml:"terraform_version"             required:"false"`
+	TerraTerragruntVersion          string   `envconfig:"TERRA_TERRAGRUNT_VERSION"            yaml:"terragrunt_version"            required:"false"`
+	TerraTofuVersion                string   `envconfig:"TERRA_TOFU_VERSION"                  yaml:"tofu_version"                  required:"false"`
+	TerraVersionCheck               string   `envconfig:"TERRA_VERSION_CHECK"                 yaml:"version_check"                 required:"false" validate:"omitempty,oneof=fail warn"`
+	TerraWebhookURLs                []string `envconfig:"TERRA_WEBHOOK_URLS"                  yaml:"webhook_urls"                  required:"false" validate:"omitempty,dive,url" sensitive:"true"`
+	TerraWebhookFormat              string   `envconfig:"TERRA_WEBHOOK_FORMAT"                yaml:"webhook_format"                required:"false" validate:"omitempty,oneof=json slack teams"`
+	TerraWebhookCommands            []string `envconfig:"TERRA_WEBHOOK_COMMANDS"              yaml:"webhook_commands"              required:"false"`
+
+	// sources maps each setting key to where its value was loaded from (a config file
+	// path or SourceEnvironment); keys without an entry hold their default value.
+	sources map[string]string
+	// profile is the active profile resolved by LoadProjectConfig, or nil when none is selected.
+	profile *profile
+	// tools are the dependencies declared under `tools:` by the project configuration.
+	tools map[string]Dependency
+	// hooks are the shell commands declared under `hooks:` by the project configuration.
+	hooks map[string][]string
 }
 
 func NewSettings() *Settings {
-	var settings Settings
-	err := envconfig.Process("", &settings)
-	if err != nil {
-		logger.Fatalf("Failed to process environment variables: %s", err)
-	}
-
-	validate := validator.New()
-	err = validate.Struct(settings)
-	if err != nil {
-		logger.Fatalf("Environment variables validation error: %v", err)
+	settings := &Settings{}
+	if err := settings.LoadProjectConfig("."); err != nil {
+		// The selected profile may be declared only above the target path, where the
+		// project configuration is resolved again once the command knows it
+		if !errors.Is(err, ErrProfileNotFound) {
+			logger.Fatalf("Failed to load settings: %s", err)
+		}
+		logger.Debugf("Deferring profile selection to the target path: %s", err)
 	}
 
-	return &settings
+	return settings
 }
 
 // GetModuleCacheDir returns the module cache directory path.
@@ -68,3 +106,121 @@ func (s *Settings) GetProviderCacheDir() (string, error) {
 
 	return filepath.Join(home, ".cache", "terra", "providers"), nil
 }
+
+// GetToolCacheDir returns the directory keeping every installed version of the dependency
+// CLIs side by side. It uses the configured value or falls back to ~/.cache/terra/bin.
+func (s *Settings) GetToolCacheDir() (string, error) {
+	if s.TerraToolCacheDir != "" {
+		return s.TerraToolCacheDir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".cache", "terra", "bin"), nil
+}
+
+// GetToolBinaryPath returns where the given version of the dependency CLI is kept in the
+// tool cache, e.g. ~/.cache/terra/bin/terraform/1.9.8/terraform.
+func (s *Settings) GetToolBinaryPath(cli, version string) (string, error) {
+	cacheDir, err := s.GetToolCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, cli, strings.TrimPrefix(version, "v"), cli), nil
+}
+
+// WithOverrides returns a copy of the settings where every field whose `envconfig` key is
+// present in values is replaced by the parsed value. The receiver and the process
+// environment are left untouched, which lets parallel workers layer a module's own .env
+// over the process-wide settings.
+func (s *Settings) WithOverrides(values map[string]string) (*Settings, error) {
+	overridden := *s
+	if err := overridden.assign(values); err != nil {
+		return nil, err
+	}
+
+	if err := overridden.validate(); err != nil {
+		return nil, err
+	}
+
+	return &overridden, nil
+}
+
+// IsSecretVariable reports whether key, a Terraform variable with or without its TF_VAR_
+// prefix, is listed in TERRA_SECRET_VARIABLES.
+func (s *Settings) IsSecretVariable(key string) bool {
+	name := strings.TrimPrefix(key, profileVariablesPrefix)
+	for _, secret := range s.TerraSecretVariables {
+		if strings.TrimPrefix(secret, profileVariablesPrefix) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the struct's `validate` tags and that every redaction pattern compiles.
+func (s *Settings) validate() error {
+	if err := validator.New().Struct(s); err != nil {
+		return fmt.Errorf("settings validation error: %w", err)
+	}
+
+	for _, pattern := range s.TerraRedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("settings validation error: invalid TERRA_REDACT_PATTERNS entry %q: %w", pattern, err)
+		}
+	}
+
+	for _, cli := range versionedTools() {
+		if err := validateToolVersion(s.GetToolVersion(cli)); err != nil {
+			return fmt.Errorf("settings validation error: invalid %s: %w", toolVersionKey(cli), err)
+		}
+	}
+
+	return nil
+}
+
+// assign parses and stores every value whose key matches a field's `envconfig` tag,
+// using the same formats as envconfig (comma-separated lists, strconv booleans).
+func (s *Settings) assign(values map[string]string) error {
+	target := reflect.ValueOf(s).Elem()
+
+	for index := range target.NumField() {
+		field := target.Type().Field(index)
+		key := field.Tag.Get("envconfig")
+		raw, found := values[key]
+		if key == "" || !found {
+			continue
+		}
+
+		switch field.Type.Kind() { //nolint:exhaustive // only the kinds used by Settings are supported
+		case reflect.String:
+			target.Field(index).SetString(raw)
+		case reflect.Bool:
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for %s: %w", raw, key, err)
+			}
+			target.Field(index).SetBool(parsed)
+		case reflect.Int:
+			parsed, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for %s: %w", raw, key, err)
+			}
+			target.Field(index).SetInt(int64(parsed))
+		case reflect.Slice:
+			var items []string
+			if raw != "" {
+				items = strings.Split(raw, ",")
+			}
+			target.Field(index).Set(reflect.ValueOf(items))
+		default:
+			return fmt.Errorf("unsupported setting type %s for %s", field.Type.Kind(), key)
+		}
+	}
+
+	return nil
+}
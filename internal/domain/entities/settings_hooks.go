@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+const (
+	hooksConfigKey = "hooks"
+
+	// HookBeforePrefix names the hooks run before a command, e.g. before_plan.
+	HookBeforePrefix = "before_"
+	// HookAfterPrefix names the hooks run after a command succeeded, e.g. after_apply.
+	HookAfterPrefix = "after_"
+	// HookOnFailure names the hooks run after any command failed.
+	HookOnFailure = "on_failure"
+
+	unknownExitCode = -1
+)
+
+// hookNamePattern matches the before and after hooks of a command; multi-word commands
+// join their words with underscores, e.g. before_state_rm.
+var hookNamePattern = regexp.MustCompile(`^(before|after)_[a-z][a-z0-9_-]*$`)
+
+// HookContext is what a hook is told about the command it runs around, through the TERRA_*
+// variables added to its environment.
+type HookContext struct {
+	Hook       string
+	Command    string
+	ModulePath string
+	// Finished is set for the after and on_failure hooks, which also get the exit code
+	// and the duration of the command.
+	Finished bool
+	ExitCode int
+	Duration time.Duration
+}
+
+// Environment returns the "KEY=VALUE" entries describing the context to the hook.
+func (c HookContext) Environment() []string {
+	environment := []string{
+		"TERRA_HOOK=" + c.Hook,
+		"TERRA_COMMAND=" + c.Command,
+		"TERRA_MODULE_PATH=" + c.ModulePath,
+	}
+	if c.Finished {
+		environment = append(environment,
+			"TERRA_EXIT_CODE="+strconv.Itoa(c.ExitCode),
+			"TERRA_DURATION_SECONDS="+strconv.FormatFloat(c.Duration.Seconds(), 'f', 2, 64),
+		)
+	}
+	return environment
+}
+
+// GetHooks returns the shell commands declared for the hook, in declaration order.
+func (s *Settings) GetHooks(name string) []string {
+	return s.hooks[name]
+}
+
+// HookShellCommand returns the shell and the arguments running the hook's command line.
+func HookShellCommand(hook string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return "cmd", []string{"/C", hook}
+	}
+	return "sh", []string{"-c", hook}
+}
+
+// ExitCode returns the process exit code carried by err, 0 when err is nil, or -1 when the
+// command did not run to completion (e.g. the binary was not found).
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return unknownExitCode
+}
+
+// parseHooks reads the `hooks:` section of the config file at path, mapping each hook to
+// its shell commands, given as a single string or a list.
+func parseHooks(path string, raw any) (map[string][]string, error) {
+	definitions, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q must map hook names to shell commands", path, hooksConfigKey)
+	}
+
+	hooks := make(map[string][]string, len(definitions))
+	for name, definition := range definitions {
+		if name != HookOnFailure && !hookNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s: %q: invalid hook %q, expected before_<command>, after_<command> or %s",
+				path, hooksConfigKey, name, HookOnFailure)
+		}
+
+		var commands []string
+		switch value := definition.(type) {
+		case string:
+			commands = []string{value}
+		case []any:
+			for _, item := range value {
+				command, isString := item.(string)
+				if !isString {
+					return nil, fmt.Errorf("%s: %q: hook %q: expected shell commands", path, hooksConfigKey, name)
+				}
+				commands = append(commands, command)
+			}
+		default:
+			return nil, fmt.Errorf("%s: %q: hook %q: expected a shell command or a list", path, hooksConfigKey, name)
+		}
+		hooks[name] = commands
+	}
+	return hooks, nil
+}
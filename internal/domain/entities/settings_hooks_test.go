@@ -0,0 +1,89 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettings_LoadProjectConfig_Hooks(t *testing.T) {
+	t.Run("should declare the hooks of every config file, the closest replacing the others", func(t *testing.T) {
+		// GIVEN: A root config declaring two hooks and a module config replacing one of them
+		root := t.TempDir()
+		module := filepath.Join(root, "live", "prod")
+		writeProjectConfig(t, root, `hooks:
+  before_plan: tflint
+  on_failure:
+    - ./notify.sh failed
+    - echo "$TERRA_EXIT_CODE"
+`)
+		writeProjectConfig(t, module, "hooks:\n  before_plan: [tflint --recursive]\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the configuration for the module
+		err := settings.LoadProjectConfig(module)
+
+		// THEN: Should keep the root's on_failure hooks and the module's before_plan one
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tflint --recursive"}, settings.GetHooks("before_plan"))
+		assert.Equal(t, []string{"./notify.sh failed", `echo "$TERRA_EXIT_CODE"`}, settings.GetHooks(entities.HookOnFailure))
+		assert.Empty(t, settings.GetHooks("after_apply"))
+	})
+
+	t.Run("should return an error when a hook name or its commands are invalid", func(t *testing.T) {
+		// GIVEN: One config with an unknown hook and another with a hook that is not a command
+		unknown, invalid := t.TempDir(), t.TempDir()
+		writeProjectConfig(t, unknown, "hooks:\n  during_plan: tflint\n")
+		writeProjectConfig(t, invalid, "hooks:\n  after_apply:\n    command: notify\n")
+
+		// WHEN: Loading both configurations
+		unknownErr := (&entities.Settings{}).LoadProjectConfig(unknown)
+		invalidErr := (&entities.Settings{}).LoadProjectConfig(invalid)
+
+		// THEN: Should report both mistakes
+		require.Error(t, unknownErr)
+		assert.Contains(t, unknownErr.Error(), `invalid hook "during_plan"`)
+		require.Error(t, invalidErr)
+		assert.Contains(t, invalidErr.Error(), `hook "after_apply": expected a shell command or a list`)
+	})
+}
+
+func TestHookContext_Environment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should describe the command without its result when it has not run yet", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: The context of a before hook
+		context := entities.HookContext{Hook: "before_plan", Command: "plan", ModulePath: "/live/prod"}
+
+		// WHEN: Building its environment
+		environment := context.Environment()
+
+		// THEN: Should leave out the exit code and the duration
+		assert.Equal(t, []string{
+			"TERRA_HOOK=before_plan", "TERRA_COMMAND=plan", "TERRA_MODULE_PATH=/live/prod",
+		}, environment)
+	})
+
+	t.Run("should add the exit code and the duration once the command finished", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: The context of an on_failure hook
+		context := entities.HookContext{
+			Hook: entities.HookOnFailure, Command: "apply", ModulePath: "/live/prod",
+			Finished: true, ExitCode: 1, Duration: 1500 * time.Millisecond,
+		}
+
+		// WHEN: Building its environment
+		environment := context.Environment()
+
+		// THEN: Should report them
+		assert.Contains(t, environment, "TERRA_EXIT_CODE=1")
+		assert.Contains(t, environment, "TERRA_DURATION_SECONDS=1.50")
+	})
+}
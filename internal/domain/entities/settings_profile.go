@@ -0,0 +1,189 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrProfileNotFound is returned when the selected profile is not declared by any
+// project config file between the filesystem root and the target path.
+var ErrProfileNotFound = errors.New("profile not found")
+
+const (
+	profileSettingKey      = "TERRA_PROFILE"
+	profileVariablesPrefix = "TF_VAR_"
+
+	profilesConfigKey   = "profiles"
+	profileConfirmKey   = "confirm"
+	profilePathKey      = "path"
+	profileVariablesKey = "variables"
+)
+
+// profile is a named set of settings, Terraform variables and defaults declared under
+// `profiles:` in the project configuration and selected with `--profile=NAME`.
+type profile struct {
+	values     map[string]string
+	sources    map[string]string
+	variables  map[string]string
+	targetPath string
+	confirm    *bool
+}
+
+// GetProfileVariables returns the active profile's Terraform variables keyed by their
+// environment variable name (e.g. "TF_VAR_region").
+func (s *Settings) GetProfileVariables() map[string]string {
+	variables := map[string]string{}
+	if s.profile == nil {
+		return variables
+	}
+
+	for name, value := range s.profile.variables {
+		variables[profileVariablesPrefix+name] = value
+	}
+	return variables
+}
+
+// GetProfileTargetPath returns the directory the active profile runs against when no
+// directory is given on the command line, or "" when it declares none.
+func (s *Settings) GetProfileTargetPath() string {
+	if s.profile == nil {
+		return ""
+	}
+	return s.profile.targetPath
+}
+
+// IsProfileConfirmationRequired reports whether the active profile asks for an
+// interactive confirmation before any command that changes infrastructure or state.
+func (s *Settings) IsProfileConfirmationRequired() bool {
+	return s.profile != nil && s.profile.confirm != nil && *s.profile.confirm
+}
+
+// applyProfile layers the selected profile over the config file values. The profile is
+// selected by TERRA_PROFILE (which `--profile` sets) or else by the `profile` setting.
+func (s *Settings) applyProfile(profiles map[string]*profile, sources map[string]string) error {
+	s.profile = nil
+
+	name := s.TerraProfile
+	if value, found := os.LookupEnv(profileSettingKey); found {
+		name = value
+	}
+	if name == "" {
+		return nil
+	}
+
+	selected, found := profiles[name]
+	if !found {
+		declared := slices.Sorted(maps.Keys(profiles))
+		if len(declared) == 0 {
+			return fmt.Errorf("%w: %q (no profiles are declared)", ErrProfileNotFound, name)
+		}
+		return fmt.Errorf("%w: %q (declared profiles: %s)", ErrProfileNotFound, name, strings.Join(declared, ", "))
+	}
+
+	if err := s.assign(selected.values); err != nil {
+		return fmt.Errorf("profile %q: %w", name, err)
+	}
+	for key, file := range selected.sources {
+		sources[key] = fmt.Sprintf("profile %s (%s)", name, file)
+	}
+	s.profile = selected
+
+	return nil
+}
+
+// merge layers other over the profile, so a config file closer to the target path can
+// refine a profile declared above it.
+func (p *profile) merge(other *profile) {
+	maps.Copy(p.values, other.values)
+	maps.Copy(p.sources, other.sources)
+	maps.Copy(p.variables, other.variables)
+	if other.targetPath != "" {
+		p.targetPath = other.targetPath
+	}
+	if other.confirm != nil {
+		p.confirm = other.confirm
+	}
+}
+
+// parseProfiles reads the `profiles:` section of the config file at path.
+func parseProfiles(path string, raw any, keys map[string]string) (map[string]*profile, error) {
+	definitions, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q must map profile names to their settings", path, profilesConfigKey)
+	}
+
+	profiles := make(map[string]*profile, len(definitions))
+	for name, definition := range definitions {
+		fields, isMap := definition.(map[string]any)
+		if !isMap && definition != nil {
+			return nil, fmt.Errorf("%s: profile %q must be a map of settings", path, name)
+		}
+
+		parsed, err := parseProfile(path, fields, keys)
+		if err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+		profiles[name] = parsed
+	}
+
+	return profiles, nil
+}
+
+// parseProfile reads a single profile: its reserved keys (confirm, path, variables) and
+// any setting that may also appear at the top level of the config file.
+func parseProfile(path string, fields map[string]any, keys map[string]string) (*profile, error) {
+	parsed := &profile{values: map[string]string{}, sources: map[string]string{}, variables: map[string]string{}}
+
+	for name, raw := range fields {
+		switch name {
+		case profileConfirmKey:
+			confirm, ok := raw.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%q must be a boolean", name)
+			}
+			parsed.confirm = &confirm
+		case profilePathKey:
+			targetPath, ok := raw.(string)
+			if !ok || targetPath == "" {
+				return nil, fmt.Errorf("%q must be a directory path", name)
+			}
+			if !filepath.IsAbs(targetPath) {
+				targetPath = filepath.Join(filepath.Dir(path), targetPath)
+			}
+			parsed.targetPath = targetPath
+		case profileVariablesKey:
+			variables, ok := raw.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%q must map variable names to values", name)
+			}
+			for variable, value := range variables {
+				if _, isList := value.([]any); isList {
+					return nil, fmt.Errorf("variable %q: write lists as an HCL string, e.g. '[\"a\", \"b\"]'", variable)
+				}
+				formatted, err := formatConfigValue(value)
+				if err != nil {
+					return nil, fmt.Errorf("variable %q: %w", variable, err)
+				}
+				parsed.variables[variable] = formatted
+			}
+		default:
+			key, found := keys[name]
+			if !found || key == profileSettingKey {
+				return nil, fmt.Errorf("unknown setting %q", name)
+			}
+			formatted, err := formatConfigValue(raw)
+			if err != nil {
+				return nil, fmt.Errorf("setting %q: %w", name, err)
+			}
+			parsed.values[key] = formatted
+			parsed.sources[key] = path
+		}
+	}
+
+	return parsed, nil
+}
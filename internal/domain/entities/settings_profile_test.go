@@ -0,0 +1,145 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesConfig = `cloud: aws
+workspace: default
+profiles:
+  dev:
+    aws_role_arn: arn:aws:iam::111111111111:role/terraform
+    workspace: dev
+  prod:
+    aws_role_arn: arn:aws:iam::222222222222:role/terraform
+    workspace: prod
+    path: environments/prod
+    confirm: true
+    variables:
+      region: eu-west-1
+      replicas: 3
+`
+
+func TestSettings_LoadProjectConfigWithProfile(t *testing.T) {
+	t.Run("should layer the selected profile over the config file values", func(t *testing.T) {
+		// GIVEN: A config file declaring two profiles and TERRA_PROFILE selecting one
+		t.Setenv("TERRA_PROFILE", "prod")
+		root := t.TempDir()
+		configFile := writeProjectConfig(t, root, profilesConfig)
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should apply the profile's settings, variables and defaults
+		require.NoError(t, err)
+		assert.Equal(t, "aws", settings.TerraCloud)
+		assert.Equal(t, "prod", settings.TerraTerraformWorkspace)
+		assert.Equal(t, "arn:aws:iam::222222222222:role/terraform", settings.TerraAwsRoleArn)
+		assert.Equal(t, map[string]string{"TF_VAR_region": "eu-west-1", "TF_VAR_replicas": "3"},
+			settings.GetProfileVariables())
+		assert.Equal(t, filepath.Join(root, "environments", "prod"), settings.GetProfileTargetPath())
+		assert.True(t, settings.IsProfileConfirmationRequired())
+		described := map[string]entities.SettingValue{}
+		for _, setting := range settings.Describe() {
+			described[setting.Key] = setting
+		}
+		assert.Equal(t, "profile prod ("+configFile+")", described["TERRA_WORKSPACE"].Source)
+		assert.Equal(t, "profile prod", described["TF_VAR_region"].Source)
+	})
+
+	t.Run("should let environment variables override the profile", func(t *testing.T) {
+		// GIVEN: A selected profile and an environment variable for one of its settings
+		t.Setenv("TERRA_PROFILE", "prod")
+		t.Setenv("TERRA_WORKSPACE", "hotfix")
+		root := t.TempDir()
+		writeProjectConfig(t, root, profilesConfig)
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should keep the environment value
+		require.NoError(t, err)
+		assert.Equal(t, "hotfix", settings.TerraTerraformWorkspace)
+		assert.Equal(t, "arn:aws:iam::222222222222:role/terraform", settings.TerraAwsRoleArn)
+	})
+
+	t.Run("should select the profile named by the config file when TERRA_PROFILE is unset", func(t *testing.T) {
+		// GIVEN: A root config declaring profiles and a leaf config selecting one
+		root := t.TempDir()
+		leaf := filepath.Join(root, "environments", "dev")
+		writeProjectConfig(t, root, profilesConfig)
+		writeProjectConfig(t, leaf, "profile: dev\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration for the leaf
+		err := settings.LoadProjectConfig(leaf)
+
+		// THEN: Should apply the dev profile without requiring confirmation
+		require.NoError(t, err)
+		assert.Equal(t, "dev", settings.TerraTerraformWorkspace)
+		assert.False(t, settings.IsProfileConfirmationRequired())
+		assert.Empty(t, settings.GetProfileTargetPath())
+	})
+
+	t.Run("should let a config file closer to the target path refine a profile", func(t *testing.T) {
+		// GIVEN: A leaf config redefining part of a profile declared at the root
+		t.Setenv("TERRA_PROFILE", "prod")
+		root := t.TempDir()
+		leaf := filepath.Join(root, "environments", "prod")
+		writeProjectConfig(t, root, profilesConfig)
+		writeProjectConfig(t, leaf, "profiles:\n  prod:\n    confirm: false\n    variables:\n      region: eu-central-1\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration for the leaf
+		err := settings.LoadProjectConfig(leaf)
+
+		// THEN: Should merge both definitions with the leaf winning
+		require.NoError(t, err)
+		assert.Equal(t, "prod", settings.TerraTerraformWorkspace)
+		assert.Equal(t, "eu-central-1", settings.GetProfileVariables()["TF_VAR_region"])
+		assert.Equal(t, "3", settings.GetProfileVariables()["TF_VAR_replicas"])
+		assert.False(t, settings.IsProfileConfirmationRequired())
+	})
+
+	t.Run("should return ErrProfileNotFound but still resolve the settings when the profile is unknown", func(t *testing.T) {
+		// GIVEN: TERRA_PROFILE naming a profile no config file declares
+		t.Setenv("TERRA_PROFILE", "stage")
+		t.Setenv("TERRA_AWS_PROFILE", "from-env")
+		root := t.TempDir()
+		writeProjectConfig(t, root, profilesConfig)
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should list the declared profiles and keep the other values
+		require.ErrorIs(t, err, entities.ErrProfileNotFound)
+		assert.Contains(t, err.Error(), "dev, prod")
+		assert.Equal(t, "default", settings.TerraTerraformWorkspace)
+		assert.Equal(t, "from-env", settings.TerraAwsProfile)
+	})
+
+	t.Run("should return error when a profile declares an unknown setting", func(t *testing.T) {
+		// GIVEN: A profile with a typo
+		root := t.TempDir()
+		writeProjectConfig(t, root, "profiles:\n  dev:\n    workspce: dev\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should name the profile and the unknown setting
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `profile "dev"`)
+		assert.Contains(t, err.Error(), `"workspce"`)
+	})
+}
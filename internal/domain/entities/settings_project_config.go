@@ -0,0 +1,315 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.yaml.in/yaml/v3"
+)
+
+// ProjectConfigFileName is the project configuration file terra discovers by walking up
+// from the target path. Files closer to the target path override those above them.
+const ProjectConfigFileName = ".terra.yaml"
+
+const (
+	// SourceDefault marks a setting that no config file or environment variable sets.
+	SourceDefault = "default"
+	// SourceEnvironment marks a setting read from an environment variable (or `.env`).
+	SourceEnvironment = "environment"
+
+	redactedValue = "<redacted>"
+)
+
+// projectConfig is what a single config file declares.
+type projectConfig struct {
+	values   map[string]string
+	profiles map[string]*profile
+	tools    map[string]Dependency
+	hooks    map[string][]string
+}
+
+// SettingValue is the effective value of a single setting and where it came from.
+type SettingValue struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// LoadProjectConfig resolves the settings for targetPath in place: every `.terra.yaml`
+// from the filesystem root down to targetPath is merged root-to-leaf, the selected
+// profile is layered on top, then environment variables override the result. Values
+// loaded from a previous config file are cleared first, so the settings can be
+// re-resolved once the actual target path is known.
+func (s *Settings) LoadProjectConfig(targetPath string) error {
+	files, err := findProjectConfigFiles(targetPath)
+	if err != nil {
+		return err
+	}
+
+	s.clearConfigFileValues()
+	sources := map[string]string{}
+	profiles := map[string]*profile{}
+	tools := map[string]Dependency{}
+	hooks := map[string][]string{}
+
+	for _, file := range files {
+		config, readErr := readProjectConfig(file)
+		if readErr != nil {
+			return readErr
+		}
+		if readErr = s.assign(config.values); readErr != nil {
+			return fmt.Errorf("%s: %w", file, readErr)
+		}
+		for key := range config.values {
+			sources[key] = file
+		}
+		// A tool or hook declared closer to the target path replaces the declaration above it
+		maps.Copy(tools, config.tools)
+		maps.Copy(hooks, config.hooks)
+		for name, fileProfile := range config.profiles {
+			if existing, found := profiles[name]; found {
+				existing.merge(fileProfile)
+			} else {
+				profiles[name] = fileProfile
+			}
+		}
+	}
+
+	// An unknown profile is reported only once the environment is applied, so callers
+	// that tolerate it (see NewSettings) still get fully resolved settings
+	profileErr := s.applyProfile(profiles, sources)
+	if profileErr != nil && !errors.Is(profileErr, ErrProfileNotFound) {
+		return profileErr
+	}
+
+	if err = envconfig.Process("", s); err != nil {
+		return fmt.Errorf("failed to process environment variables: %w", err)
+	}
+	for _, key := range settingKeys() {
+		if _, found := os.LookupEnv(key); found {
+			sources[key] = SourceEnvironment
+		}
+	}
+	s.sources = sources
+	s.tools = tools
+	s.hooks = hooks
+
+	if err = s.validate(); err != nil {
+		return err
+	}
+
+	return profileErr
+}
+
+// Describe lists every setting with its effective value and source, in declaration
+// order, followed by the active profile's Terraform variables. Values of settings
+// tagged `sensitive:"true"` and of secret variables are redacted.
+func (s *Settings) Describe() []SettingValue {
+	target := reflect.ValueOf(s).Elem()
+	var described []SettingValue
+
+	for index := range target.NumField() {
+		field := target.Type().Field(index)
+		key := field.Tag.Get("envconfig")
+		if key == "" {
+			continue
+		}
+
+		value := formatSettingValue(target.Field(index))
+		if field.Tag.Get("sensitive") == "true" && value != "" {
+			value = redactedValue
+		}
+
+		source, found := s.sources[key]
+		if !found {
+			source = SourceDefault
+		}
+
+		described = append(described, SettingValue{Key: key, Value: value, Source: source})
+	}
+
+	variables := s.GetProfileVariables()
+	for _, key := range slices.Sorted(maps.Keys(variables)) {
+		value, source := variables[key], "profile "+s.TerraProfile
+		if environment, found := os.LookupEnv(key); found {
+			value, source = environment, SourceEnvironment
+		}
+		if s.IsSecretVariable(key) && value != "" {
+			value = redactedValue
+		}
+		described = append(described, SettingValue{Key: key, Value: value, Source: source})
+	}
+
+	return described
+}
+
+// clearConfigFileValues resets the fields whose value came from a config file.
+func (s *Settings) clearConfigFileValues() {
+	target := reflect.ValueOf(s).Elem()
+
+	for index := range target.NumField() {
+		key := target.Type().Field(index).Tag.Get("envconfig")
+		source, found := s.sources[key]
+		if key != "" && found && source != SourceEnvironment {
+			target.Field(index).SetZero()
+		}
+	}
+}
+
+// findProjectConfigFiles returns the config files between the filesystem root and
+// targetPath, ordered root-to-leaf.
+func findProjectConfigFiles(targetPath string) ([]string, error) {
+	directory, err := filepath.Abs(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", targetPath, err)
+	}
+
+	var files []string
+	for {
+		candidate := filepath.Join(directory, ProjectConfigFileName)
+		if info, statErr := os.Stat(candidate); statErr == nil && !info.IsDir() {
+			files = append([]string{candidate}, files...)
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return files, nil
+		}
+		directory = parent
+	}
+}
+
+// readProjectConfig parses a config file into values keyed by the settings' `envconfig`
+// keys, so they can be assigned exactly like environment variables, the profiles and the
+// tools and hooks it declares.
+func readProjectConfig(path string) (*projectConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var document map[string]any
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	keys := settingKeysByYAMLName()
+	config := &projectConfig{values: make(map[string]string, len(document))}
+	for name, raw := range document {
+		switch name {
+		case profilesConfigKey:
+			if config.profiles, err = parseProfiles(path, raw, keys); err != nil {
+				return nil, err
+			}
+			continue
+		case versionsConfigKey:
+			versions, versionsErr := parseVersions(path, raw, keys)
+			if versionsErr != nil {
+				return nil, versionsErr
+			}
+			maps.Copy(config.values, versions)
+			continue
+		case toolsConfigKey:
+			if config.tools, err = parseTools(path, raw); err != nil {
+				return nil, err
+			}
+			continue
+		case hooksConfigKey:
+			if config.hooks, err = parseHooks(path, raw); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		key, found := keys[name]
+		if !found {
+			return nil, fmt.Errorf("%s: unknown setting %q", path, name)
+		}
+
+		value, formatErr := formatConfigValue(raw)
+		if formatErr != nil {
+			return nil, fmt.Errorf("%s: setting %q: %w", path, name, formatErr)
+		}
+		config.values[key] = value
+	}
+
+	return config, nil
+}
+
+// formatConfigValue converts a decoded YAML scalar or list into its environment variable
+// representation.
+func formatConfigValue(raw any) (string, error) {
+	switch value := raw.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int:
+		return strconv.Itoa(value), nil
+	case float64:
+		// unquoted versions such as `terraform: 1.9` decode as floats
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			formatted, err := formatConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, formatted)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", errors.New("expected a string, boolean, integer or list")
+	}
+}
+
+// formatSettingValue renders a settings field the way it would be written in the
+// environment.
+func formatSettingValue(value reflect.Value) string {
+	switch value.Kind() { //nolint:exhaustive // only the kinds used by Settings are supported
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Slice:
+		return strings.Join(value.Interface().([]string), ",")
+	default:
+		return value.String()
+	}
+}
+
+// settingKeys returns the `envconfig` key of every setting.
+func settingKeys() []string {
+	settingsType := reflect.TypeFor[Settings]()
+	keys := make([]string, 0, settingsType.NumField())
+	for index := range settingsType.NumField() {
+		if key := settingsType.Field(index).Tag.Get("envconfig"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// settingKeysByYAMLName maps each setting's config file name to its `envconfig` key.
+func settingKeysByYAMLName() map[string]string {
+	settingsType := reflect.TypeFor[Settings]()
+	keys := make(map[string]string, settingsType.NumField())
+	for index := range settingsType.NumField() {
+		field := settingsType.Field(index)
+		if name := field.Tag.Get("yaml"); name != "" {
+			keys[name] = field.Tag.Get("envconfig")
+		}
+	}
+	return keys
+}
@@ -0,0 +1,108 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProjectConfig writes a .terra.yaml with the given content into directory.
+func writeProjectConfig(t *testing.T, directory, content string) string {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(directory, 0o755))
+	path := filepath.Join(directory, entities.ProjectConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestSettings_LoadProjectConfig(t *testing.T) {
+	t.Run("should merge config files root-to-leaf when walking up from the target path", func(t *testing.T) {
+		// GIVEN: A root config and a leaf config overriding the workspace
+		root := t.TempDir()
+		leaf := filepath.Join(root, "environments", "prod")
+		rootConfig := writeProjectConfig(t, root, "cloud: aws\nworkspace: dev\nparallelism: 4\nskip: [legacy, sandbox]\n")
+		leafConfig := writeProjectConfig(t, leaf, "workspace: prod\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration for the leaf
+		err := settings.LoadProjectConfig(leaf)
+
+		// THEN: Should apply the root values and let the leaf win
+		require.NoError(t, err)
+		assert.Equal(t, "aws", settings.TerraCloud)
+		assert.Equal(t, "prod", settings.TerraTerraformWorkspace)
+		assert.Equal(t, 4, settings.TerraParallelism)
+		assert.Equal(t, []string{"legacy", "sandbox"}, settings.TerraSkip)
+		described := map[string]entities.SettingValue{}
+		for _, setting := range settings.Describe() {
+			described[setting.Key] = setting
+		}
+		assert.Equal(t, rootConfig, described["TERRA_CLOUD"].Source)
+		assert.Equal(t, leafConfig, described["TERRA_WORKSPACE"].Source)
+		assert.Equal(t, entities.SourceDefault, described["TERRA_AWS_ROLE_ARN"].Source)
+	})
+
+	t.Run("should let environment variables override config files", func(t *testing.T) {
+		// GIVEN: A config file and an environment variable for the same setting
+		t.Setenv("TERRA_WORKSPACE", "from-env")
+		root := t.TempDir()
+		writeProjectConfig(t, root, "workspace: from-file\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should keep the environment value
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", settings.TerraTerraformWorkspace)
+	})
+
+	t.Run("should drop values of a previous config file when reloading for another path", func(t *testing.T) {
+		// GIVEN: Settings loaded from a project that sets a workspace
+		first := t.TempDir()
+		writeProjectConfig(t, first, "workspace: dev\n")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(first))
+
+		// WHEN: Reloading for an unrelated directory
+		err := settings.LoadProjectConfig(t.TempDir())
+
+		// THEN: Should no longer hold the first project's workspace
+		require.NoError(t, err)
+		assert.Empty(t, settings.TerraTerraformWorkspace)
+	})
+
+	t.Run("should return error when the config file declares an unknown setting", func(t *testing.T) {
+		// GIVEN: A config file with a typo
+		root := t.TempDir()
+		writeProjectConfig(t, root, "workspce: dev\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should name the unknown setting
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"workspce"`)
+	})
+
+	t.Run("should return error when the merged settings fail validation", func(t *testing.T) {
+		// GIVEN: A config file with an unsupported cloud
+		root := t.TempDir()
+		writeProjectConfig(t, root, "cloud: gcp\n")
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the project configuration
+		err := settings.LoadProjectConfig(root)
+
+		// THEN: Should return a validation error
+		require.Error(t, err)
+	})
+}
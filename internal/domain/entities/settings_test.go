@@ -212,6 +212,58 @@ func TestCLIAzm_GetCommandChangeAccount(t *testing.T) {
 	})
 }
 
+func TestSettings_WithOverrides(t *testing.T) {
+	t.Run("should replace only the overridden fields when values are valid", func(t *testing.T) {
+		// GIVEN: Settings with a workspace and an override map
+		settings := &entities.Settings{TerraTerraformWorkspace: "dev", TerraCloud: "aws"}
+		values := map[string]string{"TERRA_WORKSPACE": "prod", "TERRA_NO_CAS": "true", "UNRELATED": "x"}
+
+		// WHEN: Applying the overrides
+		overridden, err := settings.WithOverrides(values)
+
+		// THEN: Should return a copy with the new values and keep the original intact
+		require.NoError(t, err)
+		assert.Equal(t, "prod", overridden.TerraTerraformWorkspace)
+		assert.True(t, overridden.TerraNoCAS)
+		assert.Equal(t, "aws", overridden.TerraCloud)
+		assert.Equal(t, "dev", settings.TerraTerraformWorkspace)
+	})
+
+	t.Run("should return error when a boolean value is invalid", func(t *testing.T) {
+		// GIVEN: An override map with a malformed boolean
+		settings := &entities.Settings{}
+
+		// WHEN: Applying the overrides
+		_, err := settings.WithOverrides(map[string]string{"TERRA_NO_WORKSPACE": "maybe"})
+
+		// THEN: Should return an error naming the variable
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TERRA_NO_WORKSPACE")
+	})
+
+	t.Run("should return error when SSO mode is enabled without a profile", func(t *testing.T) {
+		// GIVEN: An override map enabling SSO without TERRA_AWS_PROFILE
+		settings := &entities.Settings{}
+
+		// WHEN: Applying the overrides
+		_, err := settings.WithOverrides(map[string]string{"TERRA_AWS_SSO": "true"})
+
+		// THEN: Should return a validation error
+		require.Error(t, err)
+	})
+
+	t.Run("should return error when the result fails validation", func(t *testing.T) {
+		// GIVEN: An override map with an unsupported cloud
+		settings := &entities.Settings{}
+
+		// WHEN: Applying the overrides
+		_, err := settings.WithOverrides(map[string]string{"TERRA_CLOUD": "gcp"})
+
+		// THEN: Should return a validation error
+		require.Error(t, err)
+	})
+}
+
 // Note: Additional tests that were using table-driven tests with loops have been
 // removed in accordance with the contributing guidelines that state:
 // "NEVER use loops (for range) to create test cases inside a test method."
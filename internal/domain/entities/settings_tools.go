@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	toolsConfigKey = "tools"
+
+	// defaultToolVersionRegex reads the version from a GitHub "latest release" response,
+	// where most companion tools are published.
+	defaultToolVersionRegex = `"tag_name":"v?([^"]+)"`
+)
+
+// toolNamePattern matches the name of a tool's executable.
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// toolDefinition is a dependency declared under `tools:` in the project configuration.
+// The URLs are formatted like the built-in dependencies' ones: `%[1]s` is the version,
+// `%[2]s` the operating system and `%[3]s` the architecture.
+type toolDefinition struct {
+	Name               string   `yaml:"name"`
+	BinaryURL          string   `yaml:"binary_url"`
+	VersionURL         string   `yaml:"version_url"`
+	VersionRegex       string   `yaml:"version_regex"`
+	ChecksumsURL       string   `yaml:"checksums_url"`
+	FormatCommand      []string `yaml:"format_command"`
+	FormatCheckCommand []string `yaml:"format_check_command"`
+	FormatFileCommand  []string `yaml:"format_file_command"`
+	FormatExtensions   []string `yaml:"format_extensions"`
+}
+
+// GetTools returns the dependencies declared under `tools:` by the project configuration,
+// sorted by CLI.
+func (s *Settings) GetTools() []Dependency {
+	tools := make([]Dependency, 0, len(s.tools))
+	for _, cli := range slices.Sorted(maps.Keys(s.tools)) {
+		tools = append(tools, s.tools[cli])
+	}
+	return tools
+}
+
+// IsBuiltInTool reports whether cli is one of the dependencies terra always knows about.
+func IsBuiltInTool(cli string) bool {
+	return slices.Contains(versionedTools(), cli)
+}
+
+// parseTools reads the `tools:` section of the config file at path, mapping each CLI to
+// the URLs it is installed from.
+func parseTools(path string, raw any) (map[string]Dependency, error) {
+	definitions, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q must map tool names to their definitions", path, toolsConfigKey)
+	}
+
+	tools := make(map[string]Dependency, len(definitions))
+	for cli, definition := range definitions {
+		if !toolNamePattern.MatchString(cli) {
+			return nil, fmt.Errorf("%s: %q: invalid tool name %q", path, toolsConfigKey, cli)
+		}
+		if IsBuiltInTool(cli) {
+			return nil, fmt.Errorf("%s: %q: %q is a built-in tool and cannot be redeclared", path, toolsConfigKey, cli)
+		}
+
+		tool, err := parseTool(cli, definition)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q: tool %q: %w", path, toolsConfigKey, cli, err)
+		}
+		tools[cli] = tool
+	}
+	return tools, nil
+}
+
+func parseTool(cli string, raw any) (Dependency, error) {
+	// Round-trip the section through YAML so unknown keys are reported
+	encoded, err := yaml.Marshal(raw)
+	if err != nil {
+		return Dependency{}, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(encoded))
+	decoder.KnownFields(true)
+	var definition toolDefinition
+	if err = decoder.Decode(&definition); err != nil {
+		return Dependency{}, fmt.Errorf("invalid definition: %w", err)
+	}
+
+	if definition.BinaryURL == "" || definition.VersionURL == "" {
+		return Dependency{}, errors.New("binary_url and version_url are required")
+	}
+	if definition.VersionRegex == "" {
+		definition.VersionRegex = defaultToolVersionRegex
+	}
+	if _, err = regexp.Compile(definition.VersionRegex); err != nil {
+		return Dependency{}, fmt.Errorf("invalid version_regex: %w", err)
+	}
+	if definition.Name == "" {
+		definition.Name = cli
+	}
+
+	return Dependency{
+		Name:                     definition.Name,
+		CLI:                      cli,
+		BinaryURL:                definition.BinaryURL,
+		VersionURL:               definition.VersionURL,
+		RegexVersion:             definition.VersionRegex,
+		FormattingCommand:        definition.FormatCommand,
+		FormattingCheckCommand:   definition.FormatCheckCommand,
+		FormattingFileCommand:    definition.FormatFileCommand,
+		FormattingFileExtensions: definition.FormatExtensions,
+		ChecksumsURL:             definition.ChecksumsURL,
+	}, nil
+}
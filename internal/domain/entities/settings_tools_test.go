@@ -0,0 +1,80 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettings_LoadProjectConfig_Tools(t *testing.T) {
+	t.Run("should declare the tools of every config file with their defaults", func(t *testing.T) {
+		// GIVEN: A root config declaring tflint and a module config declaring terraform-docs
+		root := t.TempDir()
+		module := filepath.Join(root, "modules", "vpc")
+		writeProjectConfig(t, root, `tools:
+  tflint:
+    name: TFLint
+    binary_url: https://github.com/terraform-linters/tflint/releases/download/v%[1]s/tflint_%[2]s_%[3]s.zip
+    version_url: https://api.github.com/repos/terraform-linters/tflint/releases/latest
+    checksums_url: https://github.com/terraform-linters/tflint/releases/download/v%s/checksums.txt
+`)
+		writeProjectConfig(t, module, `tools:
+  terraform-docs:
+    binary_url: https://github.com/terraform-docs/terraform-docs/releases/download/v%[1]s/terraform-docs-v%[1]s-%[2]s-%[3]s.tar.gz
+    version_url: https://api.github.com/repos/terraform-docs/terraform-docs/releases/latest
+    format_command: ["markdown", "table", "--output-file", "README.md", "."]
+`)
+		settings := &entities.Settings{}
+
+		// WHEN: Loading the configuration for the module
+		err := settings.LoadProjectConfig(module)
+
+		// THEN: Should return both tools sorted by CLI
+		require.NoError(t, err)
+		tools := settings.GetTools()
+		require.Len(t, tools, 2)
+		assert.Equal(t, "terraform-docs", tools[0].CLI)
+		assert.Equal(t, "terraform-docs", tools[0].Name)
+		assert.Equal(t, []string{"markdown", "table", "--output-file", "README.md", "."}, tools[0].FormattingCommand)
+		assert.Equal(t, "tflint", tools[1].CLI)
+		assert.Equal(t, "TFLint", tools[1].Name)
+		assert.Equal(t, `"tag_name":"v?([^"]+)"`, tools[1].RegexVersion)
+		assert.Empty(t, tools[1].FormattingCommand)
+	})
+
+	t.Run("should return an error when a built-in tool is redeclared", func(t *testing.T) {
+		// GIVEN: A config redeclaring terraform
+		root := t.TempDir()
+		writeProjectConfig(t, root, "tools:\n  terraform:\n    binary_url: https://example.com/%s\n    version_url: https://example.com\n")
+
+		// WHEN: Loading the configuration
+		err := (&entities.Settings{}).LoadProjectConfig(root)
+
+		// THEN: Should refuse it
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is a built-in tool")
+	})
+
+	t.Run("should return an error when a tool definition is incomplete or has unknown keys", func(t *testing.T) {
+		// GIVEN: One tool without a version URL and another with a misspelled key
+		missing, unknown := t.TempDir(), t.TempDir()
+		writeProjectConfig(t, missing, "tools:\n  tflint:\n    binary_url: https://example.com/%s\n")
+		writeProjectConfig(t, unknown,
+			"tools:\n  tflint:\n    binary_url: https://example.com/%s\n    version_url: https://example.com\n    binary: x\n")
+
+		// WHEN: Loading both configurations
+		missingErr := (&entities.Settings{}).LoadProjectConfig(missing)
+		unknownErr := (&entities.Settings{}).LoadProjectConfig(unknown)
+
+		// THEN: Should report both mistakes
+		require.Error(t, missingErr)
+		assert.Contains(t, missingErr.Error(), "binary_url and version_url are required")
+		require.Error(t, unknownErr)
+		assert.Contains(t, unknownErr.Error(), "field binary not found")
+	})
+}
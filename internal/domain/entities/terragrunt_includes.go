@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// terragruntConfigFileName is the configuration file of a Terragrunt module, and the file
+// find_in_parent_folders() looks for when called without a name.
+const terragruntConfigFileName = "terragrunt.hcl"
+
+var (
+	// parentFolderLookupPattern matches the find_in_parent_folders() calls, capturing the
+	// quoted name of the file looked up, if any.
+	parentFolderLookupPattern = regexp.MustCompile(`find_in_parent_folders\(\s*("[^"]*")?`)
+	// includedPathPattern matches the literal paths of include blocks and of
+	// read_terragrunt_config() calls.
+	includedPathPattern = regexp.MustCompile(`(?:\bpath\s*=\s*|read_terragrunt_config\(\s*)"([^"$]+\.hcl)"`)
+)
+
+// FindTerragruntIncludes returns the configuration files the Terragrunt modules at or below
+// targetPath include or read: the files found by find_in_parent_folders() and the literal
+// paths of include blocks and read_terragrunt_config() calls, followed through the included
+// files. Paths built from other functions or interpolations are not resolved.
+func FindTerragruntIncludes(targetPath string) []string {
+	included := make(map[string]bool)
+	_ = filepath.WalkDir(targetPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // unreadable directories include nothing
+		}
+		if entry.IsDir() {
+			// Skip the Git, Terraform and Terragrunt caches the modules carry
+			if path != targetPath &&
+				(strings.HasPrefix(entry.Name(), ".") || entry.Name() == "terragrunt-cache") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() == terragruntConfigFileName {
+			collectIncludes(filepath.Dir(path), path, included)
+		}
+		return nil
+	})
+	return slices.Sorted(maps.Keys(included))
+}
+
+// collectIncludes adds the files configPath includes to included, and then the ones they
+// include. Like Terragrunt does, find_in_parent_folders() searches above the module even
+// when called from an included file.
+func collectIncludes(moduleDir, configPath string, included map[string]bool) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return
+	}
+
+	var found []string
+	for _, match := range parentFolderLookupPattern.FindAllStringSubmatch(string(content), -1) {
+		name := strings.Trim(match[1], `"`)
+		if strings.Contains(name, "${") {
+			continue
+		}
+		if name == "" {
+			name = terragruntConfigFileName
+		}
+		if path := findInParentFolders(filepath.Dir(moduleDir), name); path != "" {
+			found = append(found, path)
+		}
+	}
+	for _, match := range includedPathPattern.FindAllStringSubmatch(string(content), -1) {
+		path := match[1]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(configPath), path)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && !info.IsDir() {
+			found = append(found, path)
+		}
+	}
+
+	for _, path := range found {
+		path = filepath.Clean(path)
+		if included[path] {
+			continue
+		}
+		included[path] = true
+		collectIncludes(moduleDir, path, included)
+	}
+}
+
+// findInParentFolders returns the path of the file named name in directory or the nearest
+// directory above it, or "".
+func findInParentFolders(directory, name string) string {
+	for {
+		path := filepath.Join(directory, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return ""
+		}
+		directory = parent
+	}
+}
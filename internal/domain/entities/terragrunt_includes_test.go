@@ -0,0 +1,94 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperWriteFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestFindTerragruntIncludes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the files found in the parent folders and their own includes", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module including root.hcl, which reads the env.hcl of the environment
+		root := t.TempDir()
+		helperWriteFile(t, filepath.Join(root, "root.hcl"),
+			`locals { env = read_terragrunt_config(find_in_parent_folders("env.hcl")) }`)
+		helperWriteFile(t, filepath.Join(root, "prod", "env.hcl"), `locals { name = "prod" }`)
+		helperWriteFile(t, filepath.Join(root, "dev", "env.hcl"), `locals { name = "dev" }`)
+		module := filepath.Join(root, "prod", "network")
+		helperWriteFile(t, filepath.Join(module, "terragrunt.hcl"),
+			"include \"root\" {\n  path = find_in_parent_folders(\"root.hcl\")\n}\n")
+
+		// WHEN: Looking for the includes of the module
+		includes := entities.FindTerragruntIncludes(module)
+
+		// THEN: Should return root.hcl and the env.hcl above the module, not the other one
+		assert.Equal(t, []string{
+			filepath.Join(root, "prod", "env.hcl"),
+			filepath.Join(root, "root.hcl"),
+		}, includes)
+	})
+
+	t.Run("should resolve the literal paths relative to the including file", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module including a sibling configuration by its relative path
+		root := t.TempDir()
+		helperWriteFile(t, filepath.Join(root, "common", "tags.hcl"), `inputs = {}`)
+		helperWriteFile(t, filepath.Join(root, "app", "terragrunt.hcl"),
+			"include \"tags\" {\n  path = \"../common/tags.hcl\"\n}\n")
+
+		// WHEN: Looking for the includes of the module
+		includes := entities.FindTerragruntIncludes(filepath.Join(root, "app"))
+
+		// THEN: Should return the sibling configuration
+		assert.Equal(t, []string{filepath.Join(root, "common", "tags.hcl")}, includes)
+	})
+
+	t.Run("should collect the includes of every module below the target and skip the caches", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Two modules below the target and a cached copy of a third one
+		root := t.TempDir()
+		helperWriteFile(t, filepath.Join(root, "root.hcl"), "")
+		helperWriteFile(t, filepath.Join(root, "shared.hcl"), "")
+		helperWriteFile(t, filepath.Join(root, "cached.hcl"), "")
+		target := filepath.Join(root, "prod")
+		helperWriteFile(t, filepath.Join(target, "a", "terragrunt.hcl"), `path = find_in_parent_folders("root.hcl")`)
+		helperWriteFile(t, filepath.Join(target, "b", "terragrunt.hcl"), `x = read_terragrunt_config("../../shared.hcl")`)
+		helperWriteFile(t, filepath.Join(target, "b", ".terragrunt-cache", "x", "terragrunt.hcl"),
+			`path = find_in_parent_folders("cached.hcl")`)
+
+		// WHEN: Looking for the includes of the tree
+		includes := entities.FindTerragruntIncludes(target)
+
+		// THEN: Should return the includes of both modules only
+		assert.Equal(t, []string{filepath.Join(root, "root.hcl"), filepath.Join(root, "shared.hcl")}, includes)
+	})
+
+	t.Run("should return nothing when the include path is interpolated", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A module including a file through an interpolation terra cannot evaluate
+		root := t.TempDir()
+		helperWriteFile(t, filepath.Join(root, "app", "terragrunt.hcl"),
+			`include "root" { path = "${get_repo_root()}/root.hcl" }`)
+
+		// WHEN: Looking for the includes of the module
+		includes := entities.FindTerragruntIncludes(filepath.Join(root, "app"))
+
+		// THEN: Should not guess the path
+		assert.Empty(t, includes)
+	})
+}
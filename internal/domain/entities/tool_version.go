@@ -0,0 +1,322 @@
+package entities
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// VersionCheckWarn makes a version mismatch only log a warning instead of failing.
+	VersionCheckWarn = "warn"
+
+	versionsConfigKey = "versions"
+)
+
+// rootTerragruntConfigNames are the files declaring `*_version_constraint`; root.hcl is
+// the name newer Terragrunt layouts use for the configuration included by every module.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var rootTerragruntConfigNames = []string{"root.hcl", "terragrunt.hcl"}
+
+// versionConstraintPatterns match, per dependency CLI, the root Terragrunt configuration
+// attribute constraining its version. Terragrunt has no attribute for OpenTofu:
+// `terraform_version_constraint` applies to whichever engine binary it runs.
+//
+//nolint:gochecknoglobals // compiled once instead of on every resolution
+var versionConstraintPatterns = map[string]*regexp.Regexp{
+	EngineTerraform: versionConstraintPattern("terraform_version_constraint"),
+	EngineTofu:      versionConstraintPattern("terraform_version_constraint"),
+	"terragrunt":    versionConstraintPattern("terragrunt_version_constraint"),
+}
+
+// versionFileIgnoredPrefixes are tfenv/tgenv keywords that resolve a version dynamically
+// and therefore do not pin anything.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var versionFileIgnoredPrefixes = []string{"latest", "min-required"}
+
+// ToolVersion is what a repository requires of a dependency: an exact version to install
+// and run, constraints the installed binary must satisfy, or both.
+type ToolVersion struct {
+	CLI          string
+	Pinned       string
+	PinnedSource string
+	Constraints  []ToolConstraint
+}
+
+// ToolConstraint is a version constraint and the file or setting declaring it.
+type ToolConstraint struct {
+	Constraint *VersionConstraint
+	Source     string
+}
+
+// GetToolVersion returns the version (or constraint) configured for the dependency CLI
+// through TERRA_<CLI>_VERSION or the `versions` section of the project configuration.
+func (s *Settings) GetToolVersion(cli string) string {
+	switch cli {
+	case "terraform":
+		return s.TerraTerraformVersion
+	case "terragrunt":
+		return s.TerraTerragruntVersion
+	case "tofu":
+		return s.TerraTofuVersion
+	default:
+		return ""
+	}
+}
+
+// ResolveToolVersion collects the version requirements of the dependency CLI for
+// targetPath. The pinned version comes from TERRA_<CLI>_VERSION when set in the
+// environment, else from the nearest `.<cli>-version` file above targetPath, else from
+// the project configuration; a value that is not an exact version is a constraint
+// instead. `<cli>_version_constraint` of the root Terragrunt configuration adds another
+// constraint, which the pinned version must satisfy.
+func (s *Settings) ResolveToolVersion(cli, targetPath string) (*ToolVersion, error) {
+	requirement := &ToolVersion{CLI: cli}
+
+	value, source := s.GetToolVersion(cli), toolVersionKey(cli)
+	if configured := s.sources[source]; configured != SourceEnvironment {
+		file, fileVersion, err := findVersionFile(cli, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case fileVersion != "":
+			value, source = fileVersion, file
+		case configured != "":
+			source = configured
+		}
+	}
+	if err := requirement.add(value, source); err != nil {
+		return nil, err
+	}
+
+	file, constraint, err := findTerragruntVersionConstraint(cli, targetPath)
+	if err != nil {
+		return nil, err
+	}
+	if err = requirement.add(constraint, file); err != nil {
+		return nil, err
+	}
+
+	if requirement.Pinned != "" && !requirement.Allows(requirement.Pinned) {
+		return nil, fmt.Errorf("%s %s pinned by %s does not satisfy %s",
+			cli, requirement.Pinned, requirement.PinnedSource, requirement.describeConstraints())
+	}
+	return requirement, nil
+}
+
+// IsEmpty reports whether nothing pins or constrains the dependency.
+func (t *ToolVersion) IsEmpty() bool {
+	return t.Pinned == "" && len(t.Constraints) == 0
+}
+
+// Allows reports whether version satisfies every constraint, ignoring the pinned version.
+func (t *ToolVersion) Allows(version string) bool {
+	for _, constraint := range t.Constraints {
+		if !constraint.Constraint.Check(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns an error describing how the installed version violates the requirement.
+func (t *ToolVersion) Check(installed string) error {
+	if installed == "" {
+		return fmt.Errorf("could not determine the installed %s version", t.CLI)
+	}
+	if t.Pinned != "" && CompareVersions(installed, t.Pinned) != 0 {
+		return fmt.Errorf("%s %s is installed, but %s pins %s", t.CLI, installed, t.PinnedSource, t.Pinned)
+	}
+	for _, constraint := range t.Constraints {
+		if !constraint.Constraint.Check(installed) {
+			return fmt.Errorf("%s %s is installed, but %s requires %q",
+				t.CLI, installed, constraint.Source, constraint.Constraint)
+		}
+	}
+	return nil
+}
+
+// add records value, an exact version or a constraint, declared by source.
+func (t *ToolVersion) add(value, source string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if IsExactVersion(value) {
+		t.Pinned, t.PinnedSource = strings.TrimPrefix(value, "v"), source
+		return nil
+	}
+
+	constraint, err := ParseVersionConstraint(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	t.Constraints = append(t.Constraints, ToolConstraint{Constraint: constraint, Source: source})
+	return nil
+}
+
+func (t *ToolVersion) describeConstraints() string {
+	described := make([]string, 0, len(t.Constraints))
+	for _, constraint := range t.Constraints {
+		described = append(described, fmt.Sprintf("%q required by %s", constraint.Constraint, constraint.Source))
+	}
+	return strings.Join(described, " and ")
+}
+
+// versionedTools returns the dependency CLIs whose version can be pinned.
+func versionedTools() []string {
+	return []string{"terraform", "terragrunt", "tofu"}
+}
+
+// VersionFileName returns the name of the file pinning the dependency CLI's version in a
+// directory, e.g. ".terraform-version".
+func VersionFileName(cli string) string {
+	return "." + cli + "-version"
+}
+
+// toolVersionKey returns the setting pinning the dependency CLI, e.g. TERRA_TERRAFORM_VERSION.
+func toolVersionKey(cli string) string {
+	return "TERRA_" + strings.ToUpper(cli) + "_VERSION"
+}
+
+// validateToolVersion checks that a configured version is an exact version or a constraint.
+func validateToolVersion(value string) error {
+	if value == "" || IsExactVersion(value) {
+		return nil
+	}
+	_, err := ParseVersionConstraint(value)
+	return err
+}
+
+// parseVersions reads the `versions:` section of the config file at path, mapping each
+// dependency CLI to its version, into the TERRA_<CLI>_VERSION keys.
+func parseVersions(path string, raw any, keys map[string]string) (map[string]string, error) {
+	definitions, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q must map tool names to their versions", path, versionsConfigKey)
+	}
+
+	values := make(map[string]string, len(definitions))
+	for cli, version := range definitions {
+		if !slices.Contains(versionedTools(), cli) {
+			return nil, fmt.Errorf("%s: %q: unknown tool %q (expected one of: %s)",
+				path, versionsConfigKey, cli, strings.Join(versionedTools(), ", "))
+		}
+
+		value, err := formatConfigValue(version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q: tool %q: %w", path, versionsConfigKey, cli, err)
+		}
+		values[keys[cli+"_version"]] = value
+	}
+	return values, nil
+}
+
+// findVersionFile returns the nearest `.<cli>-version` file (as used by tfenv and tgenv)
+// between targetPath and the filesystem root that pins a version, and that version.
+func findVersionFile(cli, targetPath string) (string, string, error) {
+	directory, err := filepath.Abs(targetPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", targetPath, err)
+	}
+
+	name := VersionFileName(cli)
+	for {
+		candidate := filepath.Join(directory, name)
+		version, readErr := readVersionFile(candidate)
+		if readErr != nil {
+			return "", "", readErr
+		}
+		if version != "" {
+			return candidate, version, nil
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return "", "", nil
+		}
+		directory = parent
+	}
+}
+
+// readVersionFile returns the first meaningful line of a version file, or "" when the file
+// does not exist or resolves its version dynamically (e.g. "latest").
+func readVersionFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, prefix := range versionFileIgnoredPrefixes {
+			if strings.HasPrefix(line, prefix) {
+				return "", nil
+			}
+		}
+		if err = validateToolVersion(line); err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+		return line, nil
+	}
+	return "", scanner.Err()
+}
+
+// findTerragruntVersionConstraint returns the outermost root Terragrunt configuration
+// between targetPath and the filesystem root that declares the version constraint of the
+// dependency CLI (see versionConstraintPatterns), and the constraint's value.
+func findTerragruntVersionConstraint(cli, targetPath string) (string, string, error) {
+	pattern, found := versionConstraintPatterns[cli]
+	if !found {
+		return "", "", nil
+	}
+	directory, err := filepath.Abs(targetPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", targetPath, err)
+	}
+
+	var foundFile, foundConstraint string
+	for {
+		for _, name := range rootTerragruntConfigNames {
+			candidate := filepath.Join(directory, name)
+			content, readErr := os.ReadFile(candidate)
+			if errors.Is(readErr, os.ErrNotExist) {
+				continue
+			}
+			if readErr != nil {
+				return "", "", fmt.Errorf("failed to read %s: %w", candidate, readErr)
+			}
+			if matches := pattern.FindSubmatch(content); matches != nil {
+				foundFile, foundConstraint = candidate, string(matches[1])
+			}
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return foundFile, foundConstraint, nil
+		}
+		directory = parent
+	}
+}
+
+// versionConstraintPattern matches the string value of the Terragrunt attribute.
+func versionConstraintPattern(attribute string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\s*` + attribute + `\s*=\s*"([^"]*)"`)
+}
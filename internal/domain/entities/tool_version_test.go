@@ -0,0 +1,184 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestSettings_ResolveToolVersion(t *testing.T) {
+	t.Run("should pin the version of the nearest version file over the project configuration", func(t *testing.T) {
+		// GIVEN: A project pinning terraform in .terra.yaml and in a closer .terraform-version
+		root := t.TempDir()
+		module := filepath.Join(root, "environments", "prod")
+		writeProjectConfig(t, root, "versions:\n  terraform: 1.8.0\n  terragrunt: 0.67.0\n")
+		writeFile(t, filepath.Join(root, "environments", ".terraform-version"), "1.9.5\n")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(module))
+
+		// WHEN: Resolving both tools for the module
+		terraform, terraformErr := settings.ResolveToolVersion("terraform", module)
+		terragrunt, terragruntErr := settings.ResolveToolVersion("terragrunt", module)
+
+		// THEN: Should take terraform from the version file and terragrunt from the config
+		require.NoError(t, terraformErr)
+		require.NoError(t, terragruntErr)
+		assert.Equal(t, "1.9.5", terraform.Pinned)
+		assert.Equal(t, filepath.Join(root, "environments", ".terraform-version"), terraform.PinnedSource)
+		assert.Equal(t, "0.67.0", terragrunt.Pinned)
+		assert.Equal(t, filepath.Join(root, entities.ProjectConfigFileName), terragrunt.PinnedSource)
+	})
+
+	t.Run("should let the environment variable win over version files", func(t *testing.T) {
+		// GIVEN: A version file and TERRA_TERRAFORM_VERSION
+		t.Setenv("TERRA_TERRAFORM_VERSION", "1.7.0")
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, ".terraform-version"), "1.9.5\n")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(root))
+
+		// WHEN: Resolving terraform
+		requirement, err := settings.ResolveToolVersion("terraform", root)
+
+		// THEN: Should pin the environment's version
+		require.NoError(t, err)
+		assert.Equal(t, "1.7.0", requirement.Pinned)
+		assert.Equal(t, "TERRA_TERRAFORM_VERSION", requirement.PinnedSource)
+	})
+
+	t.Run("should ignore version files resolving the latest version", func(t *testing.T) {
+		// GIVEN: A tfenv-style "latest" version file
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, ".terraform-version"), "latest:^1.9\n")
+
+		// WHEN: Resolving terraform
+		requirement, err := (&entities.Settings{}).ResolveToolVersion("terraform", root)
+
+		// THEN: Should neither pin nor constrain anything
+		require.NoError(t, err)
+		assert.True(t, requirement.IsEmpty())
+	})
+
+	t.Run("should read the constraint of the outermost root terragrunt configuration", func(t *testing.T) {
+		// GIVEN: A root terragrunt.hcl constraining both tools and a module config below it
+		root := t.TempDir()
+		module := filepath.Join(root, "vpc")
+		writeFile(t, filepath.Join(root, "terragrunt.hcl"),
+			"terraform_version_constraint  = \">= 1.5, < 2.0\"\nterragrunt_version_constraint = \"~> 0.67.0\"\n")
+		writeFile(t, filepath.Join(module, "terragrunt.hcl"), "include \"root\" {\n  path = find_in_parent_folders()\n}\n")
+
+		// WHEN: Resolving terragrunt for the module
+		requirement, err := (&entities.Settings{}).ResolveToolVersion("terragrunt", module)
+
+		// THEN: Should allow only versions matching the root constraint
+		require.NoError(t, err)
+		require.Len(t, requirement.Constraints, 1)
+		assert.Equal(t, filepath.Join(root, "terragrunt.hcl"), requirement.Constraints[0].Source)
+		require.NoError(t, requirement.Check("0.67.4"))
+		assert.ErrorContains(t, requirement.Check("0.68.0"), `requires "~> 0.67.0"`)
+	})
+
+	t.Run("should return an error when the pinned version violates the constraint", func(t *testing.T) {
+		// GIVEN: A pinned version outside the root terragrunt.hcl constraint
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "terragrunt.hcl"), "terraform_version_constraint = \"< 1.6\"\n")
+		writeFile(t, filepath.Join(root, ".terraform-version"), "1.9.5\n")
+
+		// WHEN: Resolving terraform
+		_, err := (&entities.Settings{}).ResolveToolVersion("terraform", root)
+
+		// THEN: Should report the conflict
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `does not satisfy "< 1.6"`)
+	})
+
+	t.Run("should treat a configured version that is not exact as a constraint", func(t *testing.T) {
+		// GIVEN: A constraint in the versions section
+		root := t.TempDir()
+		writeProjectConfig(t, root, "versions:\n  terraform: \"~> 1.9.0\"\n")
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(root))
+
+		// WHEN: Resolving terraform
+		requirement, err := settings.ResolveToolVersion("terraform", root)
+
+		// THEN: Should constrain without pinning
+		require.NoError(t, err)
+		assert.Empty(t, requirement.Pinned)
+		require.NoError(t, requirement.Check("1.9.8"))
+		assert.Error(t, requirement.Check("1.10.0"))
+	})
+
+	t.Run("should constrain OpenTofu with the terraform version constraint", func(t *testing.T) {
+		// GIVEN: A .tofu-version file and a root terragrunt.hcl constraining the engine
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "root.hcl"), "terraform_version_constraint = \">= 1.8\"\n")
+		writeFile(t, filepath.Join(root, ".tofu-version"), "1.8.2\n")
+
+		// WHEN: Resolving tofu
+		requirement, err := (&entities.Settings{}).ResolveToolVersion("tofu", root)
+
+		// THEN: Should pin the version file's version under the engine constraint
+		require.NoError(t, err)
+		assert.Equal(t, "1.8.2", requirement.Pinned)
+		require.Len(t, requirement.Constraints, 1)
+		assert.Equal(t, filepath.Join(root, "root.hcl"), requirement.Constraints[0].Source)
+		assert.ErrorContains(t, requirement.Check("1.7.3"), "pins 1.8.2")
+	})
+}
+
+func TestToolVersion_Check(t *testing.T) {
+	t.Run("should return an error when the installed version differs from the pinned one", func(t *testing.T) {
+		// GIVEN: A pinned terraform version
+		requirement := &entities.ToolVersion{CLI: "terraform", Pinned: "1.9.5", PinnedSource: ".terraform-version"}
+
+		// WHEN: Checking another installed version
+		err := requirement.Check("1.9.4")
+
+		// THEN: Should name both versions and the pin's source
+		require.Error(t, err)
+		assert.Equal(t, "terraform 1.9.4 is installed, but .terraform-version pins 1.9.5", err.Error())
+		assert.NoError(t, requirement.Check("1.9.5"))
+	})
+}
+
+func TestSettings_LoadProjectConfig_Versions(t *testing.T) {
+	t.Run("should return an error when the versions section names an unknown tool", func(t *testing.T) {
+		// GIVEN: A versions section with an unsupported tool
+		root := t.TempDir()
+		writeProjectConfig(t, root, "versions:\n  packer: 1.11.0\n")
+
+		// WHEN: Loading the project configuration
+		err := (&entities.Settings{}).LoadProjectConfig(root)
+
+		// THEN: Should reject the tool
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown tool "packer"`)
+	})
+
+	t.Run("should return an error when a configured version is malformed", func(t *testing.T) {
+		// GIVEN: A malformed terraform version
+		root := t.TempDir()
+		writeProjectConfig(t, root, "versions:\n  terraform: newest\n")
+
+		// WHEN: Loading the project configuration
+		err := (&entities.Settings{}).LoadProjectConfig(root)
+
+		// THEN: Should fail validation
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TERRA_TERRAFORM_VERSION")
+	})
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of every span terra emits.
+const TracerName = "github.com/rios0rios0/terra"
+
+// Span names of the phases of a terra run.
+const (
+	SpanAccountSwitch   = "account switch"
+	SpanProactiveInit   = "init"
+	SpanWorkspaceSelect = "workspace select"
+	SpanModule          = "module"
+	SpanInitUpgrade     = "init --upgrade"
+	SpanFormat          = "format"
+)
+
+// spanParents holds, per worker, the context of the innermost open span, which new spans
+// of that worker are started under. Contexts are not threaded through the commands, so
+// the parallel worker id (0 for the main flow) selects the parent instead. A worker
+// without an open span falls back to the main flow's, i.e. the CLI command's root span.
+// Without a configured exporter, otel's no-op provider makes all of this free.
+//
+//nolint:gochecknoglobals // mirrors otel's own global tracer provider
+var spanParents = struct {
+	mu       sync.Mutex
+	contexts map[int]context.Context
+}{contexts: map[int]context.Context{}}
+
+// Span is an open span of a terra run. It is the parent of the spans its worker starts
+// until End is called.
+type Span struct {
+	span     trace.Span
+	worker   int
+	previous context.Context
+}
+
+// StartSpan opens a span for the given worker (0 outside the parallel worker pool) under
+// that worker's innermost open span.
+func StartSpan(worker int, name string, attributes ...attribute.KeyValue) *Span {
+	spanParents.mu.Lock()
+	defer spanParents.mu.Unlock()
+
+	previous, found := spanParents.contexts[worker]
+	parent := previous
+	if !found {
+		if parent, found = spanParents.contexts[0]; !found {
+			parent = context.Background()
+		}
+	}
+
+	ctx, span := otel.Tracer(TracerName).Start(parent, name, trace.WithAttributes(attributes...))
+	spanParents.contexts[worker] = ctx
+	return &Span{span: span, worker: worker, previous: previous}
+}
+
+// SetAttributes adds attributes known only once the span is open (e.g. an exit code).
+func (s *Span) SetAttributes(attributes ...attribute.KeyValue) {
+	s.span.SetAttributes(attributes...)
+}
+
+// End closes the span, marking it as failed when err is not nil, and makes the span it
+// was started under the parent of its worker's next spans again.
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+
+	spanParents.mu.Lock()
+	defer spanParents.mu.Unlock()
+	if s.previous == nil {
+		delete(spanParents.contexts, s.worker)
+	} else {
+		spanParents.contexts[s.worker] = s.previous
+	}
+}
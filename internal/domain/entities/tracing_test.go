@@ -0,0 +1,75 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that records the ended spans in memory.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spansByName(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	return spans
+}
+
+func TestStartSpan(t *testing.T) {
+	t.Run("should nest spans of the main flow and of the workers under the open spans", func(t *testing.T) {
+		// GIVEN: A root span with a phase and a worker executing a module
+		recorder := recordSpans(t)
+		root := entities.StartSpan(0, "terra apply")
+		phase := entities.StartSpan(0, entities.SpanProactiveInit)
+		phase.End(nil)
+		module := entities.StartSpan(1, entities.SpanModule)
+
+		// WHEN: Starting a command span for the worker and ending everything
+		command := entities.StartSpan(1, "terragrunt apply")
+		command.End(nil)
+		module.End(nil)
+		root.End(nil)
+
+		// THEN: Should parent the phase and the module to the root, and the command to the module
+		spans := spansByName(recorder)
+		require.Len(t, spans, 4)
+		rootID := spans["terra apply"].SpanContext().SpanID()
+		assert.Equal(t, rootID, spans[entities.SpanProactiveInit].Parent().SpanID())
+		assert.Equal(t, rootID, spans[entities.SpanModule].Parent().SpanID())
+		assert.Equal(t, spans[entities.SpanModule].SpanContext().SpanID(), spans["terragrunt apply"].Parent().SpanID())
+		assert.False(t, spans["terra apply"].Parent().IsValid())
+	})
+
+	t.Run("should mark the span as failed when it ends with an error", func(t *testing.T) {
+		// GIVEN: An open span
+		recorder := recordSpans(t)
+		span := entities.StartSpan(0, entities.SpanWorkspaceSelect)
+
+		// WHEN: Ending it with an error
+		span.End(errors.New("workspace not found"))
+
+		// THEN: Should record the error status
+		spans := spansByName(recorder)
+		assert.Equal(t, codes.Error, spans[entities.SpanWorkspaceSelect].Status().Code)
+		assert.Equal(t, "workspace not found", spans[entities.SpanWorkspaceSelect].Status().Description)
+	})
+}
@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// exactVersionPattern matches a full release version such as "1.9.5", "v0.67.0" or
+// "1.10.0-rc1".
+var exactVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// constraintClausePattern splits a clause of a version constraint into its operator and
+// version, e.g. ">= 1.5" or "~>1.9.0".
+var constraintClausePattern = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*v?(\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.-]+)?)$`)
+
+// VersionConstraint is a comma-separated list of clauses in the syntax shared by
+// Terraform's `required_version` and Terragrunt's `*_version_constraint` attributes
+// (`>= 1.5, < 2.0`, `~> 1.9.0`, `!= 1.6.0`). A version satisfies it when it satisfies
+// every clause.
+type VersionConstraint struct {
+	raw     string
+	clauses []versionClause
+}
+
+type versionClause struct {
+	operator string
+	version  string
+	segments int // number of segments written, used by "~>"
+}
+
+// IsExactVersion reports whether value is a single release version rather than a
+// constraint.
+func IsExactVersion(value string) bool {
+	return exactVersionPattern.MatchString(strings.TrimSpace(value))
+}
+
+// ParseVersionConstraint parses a version constraint such as ">= 1.5, < 2.0".
+func ParseVersionConstraint(value string) (*VersionConstraint, error) {
+	constraint := &VersionConstraint{raw: strings.TrimSpace(value)}
+	for clause := range strings.SplitSeq(value, ",") {
+		clause = strings.TrimSpace(clause)
+		matches := constraintClausePattern.FindStringSubmatch(clause)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid version constraint %q: malformed clause %q", value, clause)
+		}
+
+		operator := matches[1]
+		if operator == "" {
+			operator = "="
+		}
+		version, _, _ := strings.Cut(matches[2], "-")
+		constraint.clauses = append(constraint.clauses, versionClause{
+			operator: operator,
+			version:  matches[2],
+			segments: strings.Count(version, ".") + 1,
+		})
+	}
+
+	if len(constraint.clauses) == 0 {
+		return nil, errors.New("empty version constraint")
+	}
+	return constraint, nil
+}
+
+// Check reports whether version satisfies every clause of the constraint.
+func (c *VersionConstraint) Check(version string) bool {
+	for _, clause := range c.clauses {
+		if !clause.check(version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
+
+func (c versionClause) check(version string) bool {
+	comparison := CompareVersions(version, c.version)
+	switch c.operator {
+	case "!=":
+		return comparison != 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case "~>":
+		return comparison >= 0 && CompareVersions(version, c.pessimisticUpperBound()) < 0
+	default:
+		return comparison == 0
+	}
+}
+
+// pessimisticUpperBound returns the exclusive upper bound of "~>": only the rightmost
+// written segment may increase, so "~> 1.9.0" allows up to 1.10.0 and "~> 1.9" up to 2.0.
+func (c versionClause) pessimisticUpperBound() string {
+	segments := versionSegments(c.version)
+	index := max(c.segments-2, 0)
+	segments[index]++
+	for rest := index + 1; rest < len(segments); rest++ {
+		segments[rest] = 0
+	}
+	return fmt.Sprintf("%d.%d.%d", segments[0], segments[1], segments[2])
+}
+
+// CompareVersions compares two dotted versions numerically, so "1.10.0" is newer than
+// "1.9.2". A leading "v" is ignored and missing segments count as zero. A pre-release
+// ("1.6.0-rc1") sorts before its release. It returns -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	segmentsA, segmentsB := versionSegments(a), versionSegments(b)
+	for index := range segmentsA {
+		if segmentsA[index] != segmentsB[index] {
+			if segmentsA[index] < segmentsB[index] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	_, preReleaseA, foundA := strings.Cut(a, "-")
+	_, preReleaseB, foundB := strings.Cut(b, "-")
+	switch {
+	case foundA == foundB:
+		return strings.Compare(preReleaseA, preReleaseB)
+	case foundA:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// versionSegments returns the major, minor and patch numbers of a version.
+func versionSegments(version string) [3]int {
+	version, _, _ = strings.Cut(strings.TrimPrefix(strings.TrimSpace(version), "v"), "-")
+	var segments [3]int
+	for index, part := range strings.SplitN(version, ".", len(segments)) {
+		segments[index], _ = strconv.Atoi(part)
+	}
+	return segments
+}
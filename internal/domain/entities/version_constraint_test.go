@@ -0,0 +1,81 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersionConstraint(t *testing.T) {
+	t.Run("should check versions against every clause of the constraint", func(t *testing.T) {
+		tests := []struct {
+			constraint string
+			version    string
+			expected   bool
+		}{
+			{constraint: ">= 1.5, < 2.0", version: "1.9.5", expected: true},
+			{constraint: ">= 1.5, < 2.0", version: "2.0.0", expected: false},
+			{constraint: "~> 1.9.0", version: "1.9.8", expected: true},
+			{constraint: "~> 1.9.0", version: "1.10.0", expected: false},
+			{constraint: "~> 1.9", version: "1.12.1", expected: true},
+			{constraint: "~> 1.9", version: "2.0.0", expected: false},
+			{constraint: "!= 1.6.0", version: "1.6.0", expected: false},
+			{constraint: "1.9.5", version: "v1.9.5", expected: true},
+			{constraint: "> 0.67.0", version: "0.67.0", expected: false},
+			{constraint: "<= 0.67", version: "0.67.0", expected: true},
+		}
+
+		for _, test := range tests {
+			// GIVEN: A parsed constraint
+			constraint, err := entities.ParseVersionConstraint(test.constraint)
+			require.NoError(t, err)
+
+			// WHEN: Checking a version
+			allowed := constraint.Check(test.version)
+
+			// THEN: Should allow it only when every clause is satisfied
+			assert.Equal(t, test.expected, allowed, "%s against %q", test.version, test.constraint)
+		}
+	})
+
+	t.Run("should return an error when a clause is malformed", func(t *testing.T) {
+		// GIVEN: A constraint with an unknown operator
+		value := ">= 1.5, ^2.0"
+
+		// WHEN: Parsing it
+		_, err := entities.ParseVersionConstraint(value)
+
+		// THEN: Should name the malformed clause
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"^2.0"`)
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Run("should compare versions numerically and sort pre-releases first", func(t *testing.T) {
+		// GIVEN: Pairs of versions
+		// WHEN: Comparing them
+		// THEN: Should order them numerically
+		assert.Equal(t, 1, entities.CompareVersions("1.10.0", "1.9.2"))
+		assert.Equal(t, 0, entities.CompareVersions("v0.67.0", "0.67"))
+		assert.Equal(t, -1, entities.CompareVersions("1.6.0-rc1", "1.6.0"))
+		assert.Equal(t, -1, entities.CompareVersions("1.6.0-alpha", "1.6.0-beta"))
+	})
+}
+
+func TestIsExactVersion(t *testing.T) {
+	t.Run("should tell a release version from a constraint", func(t *testing.T) {
+		// GIVEN: Versions and constraints
+		// WHEN: Checking whether they are exact versions
+		// THEN: Should accept only full release versions
+		assert.True(t, entities.IsExactVersion("1.9.5"))
+		assert.True(t, entities.IsExactVersion("v0.67.0"))
+		assert.True(t, entities.IsExactVersion("1.10.0-rc1"))
+		assert.False(t, entities.IsExactVersion("1.9"))
+		assert.False(t, entities.IsExactVersion("~> 1.9.0"))
+	})
+}
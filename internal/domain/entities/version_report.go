@@ -0,0 +1,39 @@
+package entities
+
+// VersionReport is the status of terra and its dependencies printed by `terra version`.
+type VersionReport struct {
+	Terra        string              `json:"terra"`
+	Dependencies []DependencyVersion `json:"dependencies"`
+}
+
+// DependencyVersion is the installed version of a dependency, where it is installed, and
+// how it compares with the latest release and the repository's version requirements.
+type DependencyVersion struct {
+	Name        string   `json:"name"`
+	CLI         string   `json:"cli"`
+	Installed   string   `json:"installed,omitempty"`
+	Path        string   `json:"path,omitempty"`
+	Pinned      string   `json:"pinned,omitempty"`
+	Constraints []string `json:"constraints,omitempty"`
+	// Satisfied is false when the installed version is missing or violates the pinned
+	// version or a constraint.
+	Satisfied bool `json:"satisfied"`
+	// Latest is only looked up on request, since it needs the network (or the mirror).
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated"`
+	// Problems explains why the dependency is unsatisfied or outdated, or why its
+	// status could not be determined.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// GetProblems returns every problem found across the dependencies, prefixed with the
+// dependency's name.
+func (r *VersionReport) GetProblems() []string {
+	var problems []string
+	for _, dependency := range r.Dependencies {
+		for _, problem := range dependency.Problems {
+			problems = append(problems, dependency.Name+": "+problem)
+		}
+	}
+	return problems
+}
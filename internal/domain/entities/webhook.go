@@ -0,0 +1,224 @@
+package entities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookFormatJSON posts the RunSummary itself.
+	WebhookFormatJSON = "json"
+	// WebhookFormatSlack posts a Slack incoming webhook message.
+	WebhookFormatSlack = "slack"
+	// WebhookFormatTeams posts a Microsoft Teams (workflows) message with an Adaptive Card.
+	WebhookFormatTeams = "teams"
+
+	webhookTimeout = 10 * time.Second
+	// webhookMaxModules bounds the modules listed by the chat messages, which have size
+	// limits; the JSON payload always lists every module.
+	webhookMaxModules = 20
+	shortSHALength    = 12
+)
+
+// DetectWebhookFormat returns the format expected by the webhook at rawURL, from its host:
+// Slack's and Teams' own hosts get their message format, anything else the JSON summary.
+func DetectWebhookFormat(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return WebhookFormatJSON
+	}
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return WebhookFormatSlack
+	case strings.HasSuffix(host, ".webhook.office.com"),
+		strings.HasSuffix(host, ".logic.azure.com"),
+		strings.HasSuffix(host, ".powerplatform.com"):
+		return WebhookFormatTeams
+	default:
+		return WebhookFormatJSON
+	}
+}
+
+// BuildWebhookPayload renders the summary in the given format.
+func BuildWebhookPayload(format string, summary RunSummary) ([]byte, error) {
+	var payload any
+	switch format {
+	case WebhookFormatJSON, "":
+		payload = summary
+	case WebhookFormatSlack:
+		payload = slackPayload(summary)
+	case WebhookFormatTeams:
+		payload = teamsPayload(summary)
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q, expected %s, %s or %s",
+			format, WebhookFormatJSON, WebhookFormatSlack, WebhookFormatTeams)
+	}
+	return json.Marshal(payload)
+}
+
+// PostWebhook sends the payload to the webhook at rawURL, failing on any non-2xx answer.
+// Errors name the webhook by its host only, as its URL usually embeds a secret token.
+func PostWebhook(rawURL string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create the request to the webhook at %s: %w", WebhookHost(rawURL), err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call the webhook at %s: %w", WebhookHost(rawURL), redactURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the webhook at %s answered HTTP %d", WebhookHost(rawURL), resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookHost returns the host of the webhook at rawURL, safe to log.
+func WebhookHost(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return "<invalid URL>"
+}
+
+// redactURLError drops the URL, and so the webhook's token, from the errors of http.Client.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// summaryHeadline is the one-line outcome leading the chat messages, e.g.
+// "terra apply failed in live/prod: 1 of 3 modules failed".
+func summaryHeadline(summary RunSummary) string {
+	total := summary.Succeeded + summary.Failed
+	if summary.Status == RunStatusFailed {
+		return fmt.Sprintf("❌ terra %s failed in %s: %d of %s failed",
+			summary.Command, summary.TargetPath, summary.Failed, countModules(total))
+	}
+	return fmt.Sprintf("✅ terra %s succeeded in %s: %s", summary.Command, summary.TargetPath, countModules(total))
+}
+
+func countModules(count int) string {
+	if count == 1 {
+		return "1 module"
+	}
+	return fmt.Sprintf("%d modules", count)
+}
+
+// summaryFacts are the details listed under the headline, in display order.
+func summaryFacts(summary RunSummary) [][2]string {
+	facts := [][2]string{
+		{"Duration", (time.Duration(summary.DurationSeconds * float64(time.Second))).Round(time.Second).String()},
+	}
+	if summary.GitSHA != "" {
+		facts = append(facts, [2]string{"Commit", summary.GitSHA[:min(len(summary.GitSHA), shortSHALength)]})
+	}
+	if summary.User != "" {
+		facts = append(facts, [2]string{"User", summary.User})
+	}
+	return facts
+}
+
+// summaryModuleLines lists the modules, the failed ones first, one line each.
+func summaryModuleLines(summary RunSummary) []string {
+	var failed, succeeded []string
+	for _, module := range summary.Modules {
+		name := moduleDisplayName(summary.TargetPath, module.Path)
+		if module.Status == RunStatusFailed {
+			failed = append(failed, fmt.Sprintf("❌ %s (exit code %d)", name, module.ExitCode))
+		} else {
+			succeeded = append(succeeded, "✅ "+name)
+		}
+	}
+
+	lines := slices.Concat(failed, succeeded)
+	if len(lines) > webhookMaxModules {
+		more := len(lines) - webhookMaxModules
+		lines = append(lines[:webhookMaxModules], fmt.Sprintf("… and %d more", more))
+	}
+	return lines
+}
+
+// moduleDisplayName returns the module's path relative to the target path, or the target's
+// own name for a single run.
+func moduleDisplayName(targetPath, modulePath string) string {
+	relative, err := filepath.Rel(targetPath, modulePath)
+	if err != nil || relative == "." {
+		return filepath.Base(modulePath)
+	}
+	return filepath.ToSlash(relative)
+}
+
+// slackPayload renders the summary as a Slack message: the headline as the notification
+// text, and Block Kit sections with the facts and the modules.
+func slackPayload(summary RunSummary) map[string]any {
+	headline := summaryHeadline(summary)
+	facts := make([]map[string]any, 0, len(summaryFacts(summary)))
+	for _, fact := range summaryFacts(summary) {
+		facts = append(facts, map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", fact[0], fact[1])})
+	}
+
+	return map[string]any{
+		"text": headline,
+		"blocks": []map[string]any{
+			{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": "*" + headline + "*"}},
+			{"type": "section", "fields": facts},
+			{"type": "section", "text": map[string]any{
+				"type": "mrkdwn", "text": strings.Join(summaryModuleLines(summary), "\n"),
+			}},
+		},
+	}
+}
+
+// teamsPayload renders the summary as the message accepted by Teams workflow webhooks: an
+// Adaptive Card with the headline, a fact set and the modules.
+func teamsPayload(summary RunSummary) map[string]any {
+	color := "Good"
+	if summary.Status == RunStatusFailed {
+		color = "Attention"
+	}
+	facts := make([]map[string]any, 0, len(summaryFacts(summary)))
+	for _, fact := range summaryFacts(summary) {
+		facts = append(facts, map[string]any{"title": fact[0], "value": fact[1]})
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []map[string]any{
+					{
+						"type": "TextBlock", "text": summaryHeadline(summary),
+						"weight": "Bolder", "color": color, "wrap": true,
+					},
+					{"type": "FactSet", "facts": facts},
+					{"type": "TextBlock", "text": strings.Join(summaryModuleLines(summary), "\n\n"), "wrap": true},
+				},
+			},
+		}},
+	}
+}
@@ -0,0 +1,191 @@
+//go:build unit
+
+package entities_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRunSummary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should count the failed modules and report the commit checked out in the target path", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A target path in a Git repository and two modules, one of which failed
+		root := t.TempDir()
+		helperRunGit(t, root, "init", "-q")
+		helperRunGit(t, root, "commit", "-q", "--allow-empty", "-m", "initial")
+		modules := []entities.ModuleResult{
+			entities.NewModuleResult(filepath.Join(root, "mod1"), time.Second, nil),
+			entities.NewModuleResult(filepath.Join(root, "mod2"), 2*time.Second, errors.New("boom")),
+		}
+
+		// WHEN: Summarizing the run
+		summary := entities.NewRunSummary("apply", root, 3*time.Second, modules)
+
+		// THEN: Should fail the run and describe both modules
+		assert.Equal(t, entities.RunStatusFailed, summary.Status)
+		assert.Equal(t, 1, summary.Succeeded)
+		assert.Equal(t, 1, summary.Failed)
+		assert.InDelta(t, 3.0, summary.DurationSeconds, 0.001)
+		assert.Len(t, summary.GitSHA, 40)
+		assert.Equal(t, "boom", summary.Modules[1].Error)
+		assert.Equal(t, -1, summary.Modules[1].ExitCode)
+	})
+}
+
+func TestDetectWebhookFormat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should detect the chat services from the host of the webhook", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: Slack, Teams and other webhooks
+		urls := map[string]string{
+			"https://hooks.slack.com/services/T000/B000/XXXX":                          entities.WebhookFormatSlack,
+			"https://contoso.webhook.office.com/webhookb2/abc":                         entities.WebhookFormatTeams,
+			"https://prod-01.westus.logic.azure.com:443/workflows/abc/triggers/manual": entities.WebhookFormatTeams,
+			"https://ci.example.com/terra":                                             entities.WebhookFormatJSON,
+		}
+
+		for url, expected := range urls {
+			// WHEN: Detecting their format
+			format := entities.DetectWebhookFormat(url)
+
+			// THEN: Should match the service
+			assert.Equal(t, expected, format, url)
+		}
+	})
+}
+
+func TestBuildWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	summary := entities.RunSummary{
+		Command: "apply", TargetPath: "/live", Status: entities.RunStatusFailed,
+		Succeeded: 1, Failed: 1, DurationSeconds: 65, GitSHA: "0123456789abcdef0123", User: "alice",
+		Modules: []entities.ModuleResult{
+			{Path: "/live/network", Status: entities.RunStatusSucceeded},
+			{Path: "/live/database", Status: entities.RunStatusFailed, ExitCode: 1},
+		},
+	}
+
+	t.Run("should render the summary as a Slack message with the failed modules first", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: The summary of a failed run
+
+		// WHEN: Rendering it for Slack
+		payload, err := entities.BuildWebhookPayload(entities.WebhookFormatSlack, summary)
+
+		// THEN: Should lead with the outcome and list the modules
+		require.NoError(t, err)
+		var message struct {
+			Text   string `json:"text"`
+			Blocks []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+				Fields []struct {
+					Text string `json:"text"`
+				} `json:"fields"`
+			} `json:"blocks"`
+		}
+		require.NoError(t, json.Unmarshal(payload, &message))
+		assert.Equal(t, "❌ terra apply failed in /live: 1 of 2 modules failed", message.Text)
+		require.Len(t, message.Blocks, 3)
+		assert.Equal(t, "*Duration*\n1m5s", message.Blocks[1].Fields[0].Text)
+		assert.Equal(t, "*Commit*\n0123456789ab", message.Blocks[1].Fields[1].Text)
+		assert.Equal(t, "❌ database (exit code 1)\n✅ network", message.Blocks[2].Text.Text)
+	})
+
+	t.Run("should render the summary as a Teams Adaptive Card", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: The summary of a failed run
+
+		// WHEN: Rendering it for Teams
+		payload, err := entities.BuildWebhookPayload(entities.WebhookFormatTeams, summary)
+
+		// THEN: Should wrap an Adaptive Card highlighting the failure
+		require.NoError(t, err)
+		var message struct {
+			Type        string `json:"type"`
+			Attachments []struct {
+				ContentType string `json:"contentType"`
+				Content     struct {
+					Type string           `json:"type"`
+					Body []map[string]any `json:"body"`
+				} `json:"content"`
+			} `json:"attachments"`
+		}
+		require.NoError(t, json.Unmarshal(payload, &message))
+		assert.Equal(t, "message", message.Type)
+		require.Len(t, message.Attachments, 1)
+		assert.Equal(t, "application/vnd.microsoft.card.adaptive", message.Attachments[0].ContentType)
+		assert.Equal(t, "AdaptiveCard", message.Attachments[0].Content.Type)
+		assert.Equal(t, "Attention", message.Attachments[0].Content.Body[0]["color"])
+	})
+
+	t.Run("should return error when the format is unknown", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: The summary of a run
+
+		// WHEN: Rendering it in an unknown format
+		_, err := entities.BuildWebhookPayload("discord", summary)
+
+		// THEN: Should report it
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown webhook format "discord"`)
+	})
+}
+
+func TestPostWebhook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post the payload as JSON", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A webhook stand-in recording what it receives
+		var contentType, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			received, _ := io.ReadAll(r.Body)
+			body = string(received)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		// WHEN: Posting a payload
+		err := entities.PostWebhook(server.URL, []byte(`{"command":"apply"}`))
+
+		// THEN: Should deliver it
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", contentType)
+		assert.JSONEq(t, `{"command":"apply"}`, body)
+	})
+
+	t.Run("should return error without the URL when the webhook rejects the payload", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A webhook stand-in rejecting everything
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		// WHEN: Posting to a URL carrying a token
+		err := entities.PostWebhook(server.URL+"/secret-token", []byte(`{}`))
+
+		// THEN: Should report the status and keep the token out of the error
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "answered HTTP 403")
+		assert.NotContains(t, err.Error(), "secret-token")
+	})
+}
@@ -0,0 +1,8 @@
+package repositories
+
+// OutputShellRepository executes a shell command and returns its captured standard output
+// instead of streaming it to the console. It is used when terra needs to consume what a
+// command prints (e.g. temporary cloud credentials) rather than show it to the user.
+type OutputShellRepository interface {
+	ExecuteCommandWithOutput(command string, arguments []string, directory string, environment []string) (string, error)
+}
@@ -3,7 +3,15 @@ package repositories
 // ParallelShellRepository executes a shell command while streaming its output through a
 // per-invocation line prefix, so concurrent module executions remain attributable in the
 // combined console output. It is a separate, focused port from ShellRepository because
-// only the parallel worker pool needs the prefixing behavior.
+// only the parallel worker pool needs the prefixing behavior. The environment holds extra
+// "KEY=VALUE" entries scoped to this single process (e.g. per-module cloud credentials),
+// and the worker is the 1-based id of the pool worker running it, used in the logs.
 type ParallelShellRepository interface {
-	ExecuteCommandWithPrefix(command string, arguments []string, directory, prefix string) error
+	ExecuteCommandWithPrefix(
+		command string,
+		arguments []string,
+		directory, prefix string,
+		worker int,
+		environment []string,
+	) error
 }
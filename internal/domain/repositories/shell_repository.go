@@ -1,6 +1,9 @@
 package repositories
 
 // ShellRepository is not totally necessary, but it is rather a good example for other applications.
+// ExecuteCommandWithEnvironment also passes extra "KEY=VALUE" entries to the child process
+// only, leaving the terra process environment untouched.
 type ShellRepository interface {
 	ExecuteCommand(command string, arguments []string, directory string) error
+	ExecuteCommandWithEnvironment(command string, arguments []string, directory string, environment []string) error
 }
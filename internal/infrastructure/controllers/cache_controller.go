@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"os"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type CacheController struct {
+	command commands.Cache
+}
+
+func NewCacheController(command commands.Cache) *CacheController {
+	return &CacheController{command: command}
+}
+
+func (it *CacheController) GetBind() entities.ControllerBind {
+	return entities.ControllerBind{
+		Use:   "cache stats|prune",
+		Short: "Inspect and prune the centralized module and provider caches",
+		Long: "Manage the centralized module and provider caches shared by every stack. " +
+			"'cache stats' lists every module checkout and provider version with its size and last use. " +
+			"'cache prune --older-than=30d --max-size=20GB' removes the entries unused for longer than " +
+			"--older-than, then the least recently used ones until the caches fit in --max-size, " +
+			"skipping anything locked by a running terragrunt.",
+	}
+}
+
+func (it *CacheController) Execute(cmd *cobra.Command, arguments []string) {
+	subcommand := ""
+	if len(arguments) > 0 {
+		subcommand = arguments[0]
+	}
+
+	switch subcommand {
+	case "stats":
+		if err := it.command.Stats(os.Stdout); err != nil {
+			logger.Fatalf("Error reading the caches: %s", err)
+		}
+	case "prune":
+		options, err := parsePruneOptions(cmd)
+		if err != nil {
+			logger.Fatalf("Error: %s", err)
+		}
+		if err = it.command.Prune(options); err != nil {
+			logger.Fatalf("Error pruning the caches: %s", err)
+		}
+	default:
+		logger.Fatalf("Unknown cache subcommand, usage: terra %s", it.GetBind().Use)
+	}
+}
+
+// parsePruneOptions reads the --older-than and --max-size flags.
+func parsePruneOptions(cmd *cobra.Command) (commands.CachePruneOptions, error) {
+	var options commands.CachePruneOptions
+	if olderThan, _ := cmd.Flags().GetString("older-than"); olderThan != "" {
+		age, err := entities.ParseAge(olderThan)
+		if err != nil {
+			return options, err
+		}
+		options.OlderThan = age
+	}
+	if maxSize, _ := cmd.Flags().GetString("max-size"); maxSize != "" {
+		size, err := entities.ParseByteSize(maxSize)
+		if err != nil {
+			return options, err
+		}
+		options.MaxSize = size
+	}
+	return options, nil
+}
@@ -0,0 +1,70 @@
+//go:build unit
+
+package controllers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheController_GetBind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return cache bind when called", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A cache controller with mock command
+		controller := controllers.NewCacheController(&commanddoubles.StubCacheCommand{})
+
+		// WHEN: Getting the controller bind
+		bind := controller.GetBind()
+
+		// THEN: Should expose the "cache" usage
+		assert.Equal(t, "cache stats|prune", bind.Use)
+		assert.Equal(t, "Inspect and prune the centralized module and provider caches", bind.Short)
+		assert.Contains(t, bind.Long, "--max-size")
+	})
+}
+
+func TestCacheController_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should print the statistics when called with stats", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A cache controller
+		mockCommand := &commanddoubles.StubCacheCommand{}
+		controller := controllers.NewCacheController(mockCommand)
+
+		// WHEN: Executing "cache stats"
+		controller.Execute(&cobra.Command{}, []string{"stats"})
+
+		// THEN: Should print the statistics without pruning
+		assert.Equal(t, 1, mockCommand.StatsCallCount)
+		assert.Equal(t, 0, mockCommand.PruneCallCount)
+	})
+
+	t.Run("should prune with the parsed limits when called with prune", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A cache controller and the prune flags
+		mockCommand := &commanddoubles.StubCacheCommand{}
+		controller := controllers.NewCacheController(mockCommand)
+		cmd := &cobra.Command{}
+		cmd.Flags().String("older-than", "", "test flag")
+		cmd.Flags().String("max-size", "", "test flag")
+		require.NoError(t, cmd.Flags().Set("older-than", "30d"))
+		require.NoError(t, cmd.Flags().Set("max-size", "20GB"))
+
+		// WHEN: Executing "cache prune"
+		controller.Execute(cmd, []string{"prune"})
+
+		// THEN: Should prune with both limits
+		assert.Equal(t, 1, mockCommand.PruneCallCount)
+		assert.Equal(t, 30*24*time.Hour, mockCommand.LastPruneOptions.OlderThan)
+		assert.Equal(t, int64(20<<30), mockCommand.LastPruneOptions.MaxSize)
+	})
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"os"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers/helpers"
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type ConfigController struct {
+	command commands.ShowConfig
+}
+
+func NewConfigController(command commands.ShowConfig) *ConfigController {
+	return &ConfigController{command: command}
+}
+
+func (it *ConfigController) GetBind() entities.ControllerBind {
+	return entities.ControllerBind{
+		Use:   "config show [directory]",
+		Short: "Show the effective terra configuration",
+		Long: "Show the effective terra configuration for a directory (the current one by default). " +
+			"Every " + entities.ProjectConfigFileName + " from the filesystem root down to the directory is " +
+			"merged root-to-leaf, then environment variables override the result. " +
+			"Each setting is printed with its value and the file or environment it came from.",
+	}
+}
+
+func (it *ConfigController) Execute(_ *cobra.Command, arguments []string) {
+	if len(arguments) == 0 || arguments[0] != "show" {
+		logger.Fatalf("Unknown config subcommand, usage: terra %s", it.GetBind().Use)
+	}
+
+	targetPath := helpers.ArgumentsHelper{}.FindAbsolutePath(arguments[1:])
+	if err := it.command.Execute(targetPath, os.Stdout); err != nil {
+		logger.Fatalf("Error showing configuration: %s", err)
+	}
+}
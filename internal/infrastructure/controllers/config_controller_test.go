@@ -0,0 +1,67 @@
+//go:build unit
+
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfigController(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create instance when command provided", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A mock show config command
+		mockCommand := &commanddoubles.StubShowConfigCommand{}
+
+		// WHEN: Creating a new config controller
+		controller := controllers.NewConfigController(mockCommand)
+
+		// THEN: Should create a valid controller instance
+		require.NotNil(t, controller)
+	})
+}
+
+func TestConfigController_GetBind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return config bind when called", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A config controller with mock command
+		controller := controllers.NewConfigController(&commanddoubles.StubShowConfigCommand{})
+
+		// WHEN: Getting the controller bind
+		bind := controller.GetBind()
+
+		// THEN: Should expose the "config show" usage
+		assert.Equal(t, "config show [directory]", bind.Use)
+		assert.Equal(t, "Show the effective terra configuration", bind.Short)
+		assert.Contains(t, bind.Long, ".terra.yaml")
+	})
+}
+
+func TestConfigController_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should show the configuration of the given directory", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A config controller and a target directory
+		mockCommand := &commanddoubles.StubShowConfigCommand{}
+		controller := controllers.NewConfigController(mockCommand)
+		targetPath := t.TempDir()
+
+		// WHEN: Executing "config show <directory>"
+		controller.Execute(&cobra.Command{}, []string{"show", targetPath})
+
+		// THEN: Should execute the command for that directory
+		assert.Equal(t, 1, mockCommand.ExecuteCallCount)
+		assert.Equal(t, targetPath, mockCommand.LastTargetPath)
+	})
+}
@@ -7,26 +7,52 @@ import (
 
 // RegisterProviders registers all controller providers with the DIG container.
 func RegisterProviders(container *dig.Container) error {
-	// Register dependencies value
-	if err := container.Provide(func() []entities.Dependency {
-		return []entities.Dependency{
+	// Register dependencies value, with the engine selected by TERRA_ENGINE and the tools
+	// declared by the project configuration
+	if err := container.Provide(func(settings *entities.Settings) []entities.Dependency {
+		dependencies := entities.SelectEngineDependencies([]entities.Dependency{
 			{
-				Name:              "Terraform",
-				CLI:               "terraform",
-				BinaryURL:         "https://releases.hashicorp.com/terraform/%[1]s/terraform_%[1]s_%[2]s_%[3]s.zip",
-				VersionURL:        "https://checkpoint-api.hashicorp.com/v1/check/terraform",
-				RegexVersion:      `"current_version":"([^"]+)"`,
-				FormattingCommand: []string{"fmt", "-recursive"},
+				Name:                     "Terraform",
+				CLI:                      "terraform",
+				BinaryURL:                "https://releases.hashicorp.com/terraform/%[1]s/terraform_%[1]s_%[2]s_%[3]s.zip",
+				VersionURL:               "https://checkpoint-api.hashicorp.com/v1/check/terraform",
+				RegexVersion:             `"current_version":"([^"]+)"`,
+				FormattingCommand:        []string{"fmt", "-recursive"},
+				FormattingCheckCommand:   []string{"fmt", "-check", "-diff", "-recursive"},
+				FormattingFileCommand:    []string{"fmt"},
+				FormattingFileExtensions: []string{".tf", ".tfvars"},
+				Engine:                   entities.EngineTerraform,
+				ChecksumsURL:             "https://releases.hashicorp.com/terraform/%[1]s/terraform_%[1]s_SHA256SUMS",
+				SignatureURL:             "https://releases.hashicorp.com/terraform/%[1]s/terraform_%[1]s_SHA256SUMS.sig",
+				SigningKey:               entities.HashiCorpPublicKey,
 			},
 			{
-				Name:              "Terragrunt",
-				CLI:               "terragrunt",
-				BinaryURL:         "https://github.com/gruntwork-io/terragrunt/releases/download/v%s/terragrunt_%[2]s_%[3]s",
-				VersionURL:        "https://api.github.com/repos/gruntwork-io/terragrunt/releases/latest",
-				RegexVersion:      `"tag_name":"v([^"]+)"`,
-				FormattingCommand: []string{"hcl", "format", "**/*.hcl"},
+				Name:                     "OpenTofu",
+				CLI:                      "tofu",
+				BinaryURL:                "https://github.com/opentofu/opentofu/releases/download/v%[1]s/tofu_%[1]s_%[2]s_%[3]s.zip",
+				VersionURL:               "https://api.github.com/repos/opentofu/opentofu/releases/latest",
+				RegexVersion:             `"tag_name":"v([^"]+)"`,
+				FormattingCommand:        []string{"fmt", "-recursive"},
+				FormattingCheckCommand:   []string{"fmt", "-check", "-diff", "-recursive"},
+				FormattingFileCommand:    []string{"fmt"},
+				FormattingFileExtensions: []string{".tf", ".tfvars"},
+				Engine:                   entities.EngineTofu,
+				ChecksumsURL:             "https://github.com/opentofu/opentofu/releases/download/v%[1]s/tofu_%[1]s_SHA256SUMS",
 			},
-		}
+			{
+				Name:                     "Terragrunt",
+				CLI:                      "terragrunt",
+				BinaryURL:                "https://github.com/gruntwork-io/terragrunt/releases/download/v%s/terragrunt_%[2]s_%[3]s",
+				VersionURL:               "https://api.github.com/repos/gruntwork-io/terragrunt/releases/latest",
+				RegexVersion:             `"tag_name":"v([^"]+)"`,
+				FormattingCommand:        []string{"hcl", "format", "**/*.hcl"},
+				FormattingCheckCommand:   []string{"hcl", "format", "--check", "--diff"},
+				FormattingFileCommand:    []string{"hcl", "format", "--file"},
+				FormattingFileExtensions: []string{".hcl"},
+				ChecksumsURL:             "https://github.com/gruntwork-io/terragrunt/releases/download/v%s/SHA256SUMS",
+			},
+		}, settings.GetEngine())
+		return append(dependencies, settings.GetTools()...)
 	}); err != nil {
 		return err
 	}
@@ -50,6 +76,18 @@ func RegisterProviders(container *dig.Container) error {
 	if err := container.Provide(NewVersionController); err != nil {
 		return err
 	}
+	if err := container.Provide(NewConfigController); err != nil {
+		return err
+	}
+	if err := container.Provide(NewUseVersionController); err != nil {
+		return err
+	}
+	if err := container.Provide(NewDoctorController); err != nil {
+		return err
+	}
+	if err := container.Provide(NewCacheController); err != nil {
+		return err
+	}
 	if err := container.Provide(NewControllers); err != nil {
 		return err
 	}
@@ -68,6 +106,10 @@ func NewControllers(
 	updateDependenciesController *UpdateDependenciesController,
 	selfUpdateController *SelfUpdateController,
 	versionController *VersionController,
+	configController *ConfigController,
+	useVersionController *UseVersionController,
+	doctorController *DoctorController,
+	cacheController *CacheController,
 ) *[]entities.Controller {
 	return &[]entities.Controller{
 		deleteCacheController,
@@ -76,5 +118,9 @@ func NewControllers(
 		updateDependenciesController,
 		selfUpdateController,
 		versionController,
+		configController,
+		useVersionController,
+		doctorController,
+		cacheController,
 	}
 }
@@ -61,14 +61,18 @@ func TestNewControllers(t *testing.T) {
 		)
 		selfUpdate := controllers.NewSelfUpdateController(&commanddoubles.StubSelfUpdateCommand{})
 		version := controllers.NewVersionController(&commanddoubles.StubVersionCommand{})
+		config := controllers.NewConfigController(&commanddoubles.StubShowConfigCommand{})
+		useVersion := controllers.NewUseVersionController(&commanddoubles.StubUseVersionCommand{}, deps)
+		doctor := controllers.NewDoctorController(&commanddoubles.StubDoctorCommand{})
+		cache := controllers.NewCacheController(&commanddoubles.StubCacheCommand{})
 
 		// when
 		result := controllers.NewControllers(
-			deleteCache, formatFiles, installDeps, updateDeps, selfUpdate, version,
+			deleteCache, formatFiles, installDeps, updateDeps, selfUpdate, version, config, useVersion, doctor, cache,
 		)
 
 		// then
 		require.NotNil(t, result)
-		assert.Len(t, *result, 6)
+		assert.Len(t, *result, 10)
 	})
 }
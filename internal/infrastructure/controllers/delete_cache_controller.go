@@ -3,6 +3,7 @@ package controllers
 import (
 	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers/helpers"
 	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -17,19 +18,33 @@ func NewDeleteCacheController(command commands.DeleteCache) *DeleteCacheControll
 
 func (it *DeleteCacheController) GetBind() entities.ControllerBind {
 	return entities.ControllerBind{
-		Use:   "clear",
+		Use:   "clear [directory]",
 		Short: "Clear all cache directories and lock files",
-		Long: "Clear all temporary directories, cache folders, and lock files created during the Terraform and Terragrunt execution. " +
+		Long: "Clear all temporary directories, cache folders, and lock files created during the Terraform and Terragrunt execution " +
+			"below a directory (the current one by default), which must belong to a project. " +
 			"This includes .terraform, .terragrunt-cache, terragrunt-cache directories and .terraform.lock.hcl files. " +
-			"Use --global to also remove centralized module and provider cache directories.",
+			"Use --dry-run to list what would be removed with its size, --keep-lock-files to keep the committed " +
+			".terraform.lock.hcl files, and --global to also remove centralized module and provider cache directories.",
 	}
 }
 
-func (it *DeleteCacheController) Execute(cmd *cobra.Command, _ []string) {
+func (it *DeleteCacheController) Execute(cmd *cobra.Command, arguments []string) {
 	global, err := cmd.Flags().GetBool("global")
 	if err != nil {
 		logger.Warnf("Failed to get global flag: %s, defaulting to false", err)
 		global = false
 	}
-	it.command.Execute([]string{".terraform", ".terragrunt-cache", "terragrunt-cache", ".terraform.lock.hcl"}, global)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	keepLockFiles, _ := cmd.Flags().GetBool("keep-lock-files")
+
+	toBeDeleted := []string{".terraform", ".terragrunt-cache", "terragrunt-cache"}
+	if !keepLockFiles {
+		toBeDeleted = append(toBeDeleted, ".terraform.lock.hcl")
+	}
+
+	targetPath := helpers.ArgumentsHelper{}.FindAbsolutePath(arguments)
+	options := commands.DeleteCacheOptions{Global: global, DryRun: dryRun}
+	if err = it.command.Execute(targetPath, toBeDeleted, options); err != nil {
+		logger.Fatalf("Error: %s", err)
+	}
 }
@@ -42,11 +42,12 @@ func TestDeleteCacheController_GetBind(t *testing.T) {
 		bind := controller.GetBind()
 
 		// THEN: Should return correct bind configuration
-		assert.Equal(t, "clear", bind.Use)
+		assert.Equal(t, "clear [directory]", bind.Use)
 		assert.Equal(t, "Clear all cache directories and lock files", bind.Short)
 		assert.Contains(t, bind.Long, "Clear all temporary directories")
 		assert.Contains(t, bind.Long, ".terraform.lock.hcl")
 		assert.Contains(t, bind.Long, "--global")
+		assert.Contains(t, bind.Long, "--keep-lock-files")
 	})
 }
 
@@ -125,4 +126,26 @@ func TestDeleteCacheController_Execute(t *testing.T) {
 		assert.Equal(t, 1, mockCommand.ExecuteCallCount)
 		assert.False(t, mockCommand.LastGlobal)
 	})
+
+	t.Run("should keep lock files and pass the dry run and directory when flags set", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A delete cache controller, a directory and the dry-run and keep-lock-files flags
+		mockCommand := &commanddoubles.StubDeleteCacheCommand{}
+		controller := controllers.NewDeleteCacheController(mockCommand)
+		cmd := &cobra.Command{} //nolint:exhaustruct // minimal test setup
+		cmd.Flags().Bool("global", false, "")
+		cmd.Flags().Bool("dry-run", false, "")
+		cmd.Flags().Bool("keep-lock-files", false, "")
+		require.NoError(t, cmd.Flags().Set("dry-run", "true"))
+		require.NoError(t, cmd.Flags().Set("keep-lock-files", "true"))
+		targetPath := t.TempDir()
+
+		// WHEN: Executing "clear <directory>"
+		controller.Execute(cmd, []string{targetPath})
+
+		// THEN: Should preview the directory without the lock files
+		assert.Equal(t, targetPath, mockCommand.LastTargetPath)
+		assert.Equal(t, []string{".terraform", ".terragrunt-cache", "terragrunt-cache"}, mockCommand.LastToBeDeleted)
+		assert.True(t, mockCommand.LastOptions.DryRun)
+	})
 }
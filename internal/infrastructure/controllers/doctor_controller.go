@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"os"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers/helpers"
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type DoctorController struct {
+	command commands.Doctor
+}
+
+func NewDoctorController(command commands.Doctor) *DoctorController {
+	return &DoctorController{command: command}
+}
+
+func (it *DoctorController) GetBind() entities.ControllerBind {
+	return entities.ControllerBind{
+		Use:   "doctor [directory]",
+		Short: "Diagnose the environment terra depends on",
+		Long: "Check everything terra depends on for a directory (the current one by default) and print " +
+			"a pass/warn/fail report with a fix for each problem: the project configuration and .env " +
+			"files, the installed Terraform/OpenTofu and Terragrunt versions against their pins and " +
+			"constraints, the installation directory on PATH, the cache directories and their sizes, " +
+			"conflicting TF_PLUGIN_CACHE_DIR/TG_EXPERIMENT variables, the cloud CLI login and the Git version. " +
+			"Exits with an error when any check fails.",
+	}
+}
+
+func (it *DoctorController) Execute(_ *cobra.Command, arguments []string) {
+	targetPath := helpers.ArgumentsHelper{}.FindAbsolutePath(arguments)
+	if err := it.command.Execute(targetPath, os.Stdout); err != nil {
+		logger.Fatalf("Doctor found problems: %s", err)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build unit
+
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctorController_GetBind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return doctor bind when called", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A doctor controller with mock command
+		controller := controllers.NewDoctorController(&commanddoubles.StubDoctorCommand{})
+
+		// WHEN: Getting the controller bind
+		bind := controller.GetBind()
+
+		// THEN: Should expose the "doctor" usage
+		assert.Equal(t, "doctor [directory]", bind.Use)
+		assert.Equal(t, "Diagnose the environment terra depends on", bind.Short)
+		assert.Contains(t, bind.Long, "TF_PLUGIN_CACHE_DIR")
+	})
+}
+
+func TestDoctorController_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should diagnose the given directory", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A doctor controller and a target directory
+		mockCommand := &commanddoubles.StubDoctorCommand{}
+		controller := controllers.NewDoctorController(mockCommand)
+		targetPath := t.TempDir()
+
+		// WHEN: Executing "doctor <directory>"
+		controller.Execute(&cobra.Command{}, []string{targetPath})
+
+		// THEN: Should execute the command for that directory
+		assert.Equal(t, 1, mockCommand.ExecuteCallCount)
+		assert.Equal(t, targetPath, mockCommand.LastTargetPath)
+	})
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers/helpers"
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -23,12 +25,21 @@ func NewFormatFilesController(
 
 func (it *FormatFilesController) GetBind() entities.ControllerBind {
 	return entities.ControllerBind{
-		Use:   "format",
+		Use:   "format [--check [directory]]",
 		Short: "Format all files in the current directory",
-		Long:  "Format all the Terraform and Terragrunt files in the current directory.",
+		Long: "Format all the Terraform and Terragrunt files in the current directory. " +
+			"With --check, only verify the formatting of a directory (the current one by default): " +
+			"print the diff of every unformatted file and exit with an error, without rewriting anything.",
 	}
 }
 
-func (it *FormatFilesController) Execute(_ *cobra.Command, _ []string) {
+func (it *FormatFilesController) Execute(cmd *cobra.Command, arguments []string) {
+	if check, _ := cmd.Flags().GetBool("check"); check {
+		targetPath := helpers.ArgumentsHelper{}.FindAbsolutePath(arguments)
+		if err := it.command.Check(targetPath, it.dependencies); err != nil {
+			logger.Fatalf("Error: %s", err)
+		}
+		return
+	}
 	it.command.Execute(it.dependencies)
 }
@@ -52,13 +52,10 @@ func TestFormatFilesController_GetBind(t *testing.T) {
 		bind := controller.GetBind()
 
 		// THEN: Should return correct bind configuration
-		assert.Equal(t, "format", bind.Use)
+		assert.Equal(t, "format [--check [directory]]", bind.Use)
 		assert.Equal(t, "Format all files in the current directory", bind.Short)
-		assert.Equal(
-			t,
-			"Format all the Terraform and Terragrunt files in the current directory.",
-			bind.Long,
-		)
+		assert.Contains(t, bind.Long, "Format all the Terraform and Terragrunt files in the current directory.")
+		assert.Contains(t, bind.Long, "--check")
 	})
 }
 
@@ -108,4 +105,23 @@ func TestFormatFilesController_Execute(t *testing.T) {
 		// THEN: Should execute the command the correct number of times
 		assert.Equal(t, 2, mockCommand.ExecuteCallCount)
 	})
+
+	t.Run("should only check the directory when check flag provided", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A format files controller, a directory and the check flag
+		mockCommand := &commanddoubles.StubFormatFilesCommand{}
+		controller := controllers.NewFormatFilesController(mockCommand, []entities.Dependency{})
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("check", false, "test flag")
+		require.NoError(t, cmd.Flags().Set("check", "true"))
+		targetPath := t.TempDir()
+
+		// WHEN: Executing "format --check <directory>"
+		controller.Execute(cmd, []string{targetPath})
+
+		// THEN: Should check the directory without formatting anything
+		assert.Equal(t, 1, mockCommand.CheckCallCount)
+		assert.Equal(t, targetPath, mockCommand.LastTargetPath)
+		assert.Equal(t, 0, mockCommand.ExecuteCallCount)
+	})
 }
@@ -18,6 +18,13 @@ func (it ArgumentsHelper) RemovePathFromArguments(arguments []string) []string {
 	return append(arguments[:position], arguments[position+1:]...)
 }
 
+// HasPath reports whether the arguments name a directory, as opposed to falling back to
+// the working directory.
+func (it ArgumentsHelper) HasPath(arguments []string) bool {
+	_, position := findRelativePath(arguments)
+	return position != -1
+}
+
 func (it ArgumentsHelper) FindAbsolutePath(arguments []string) string {
 	relativePath, _ := findRelativePath(arguments)
 	absolutePath, err := filepath.Abs(relativePath)
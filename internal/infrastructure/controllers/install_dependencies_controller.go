@@ -3,6 +3,7 @@ package controllers
 import (
 	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -25,10 +26,18 @@ func (it *InstallDependenciesController) GetBind() entities.ControllerBind {
 	return entities.ControllerBind{
 		Use:   "install",
 		Short: "Install or update Terraform and Terragrunt to the latest versions",
-		Long:  "Install all the dependencies required to run Terra, or update them if newer versions are available. Dependencies are installed to ~/.local/bin on Linux.",
+		Long:  "Install all the dependencies required to run Terra, or update them if newer versions are available. Dependencies are installed to ~/.local/bin on Linux, except versions pinned by the repository, which are installed side by side into ~/.cache/terra/bin. The replaced binary is kept as <tool>.prev: use --rollback=<tool> to restore it.",
 	}
 }
 
-func (it *InstallDependenciesController) Execute(_ *cobra.Command, _ []string) {
+func (it *InstallDependenciesController) Execute(cmd *cobra.Command, _ []string) {
+	if cmd != nil {
+		if tool, _ := cmd.Flags().GetString("rollback"); tool != "" {
+			if err := it.command.Rollback(tool, it.dependencies); err != nil {
+				logger.Fatalf("Rollback failed: %s", err)
+			}
+			return
+		}
+	}
 	it.command.Execute(it.dependencies)
 }
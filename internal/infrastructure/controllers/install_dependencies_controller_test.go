@@ -61,7 +61,7 @@ func TestInstallDependenciesController_GetBind(t *testing.T) {
 		)
 		assert.Equal(
 			t,
-			"Install all the dependencies required to run Terra, or update them if newer versions are available. Dependencies are installed to ~/.local/bin on Linux.",
+			"Install all the dependencies required to run Terra, or update them if newer versions are available. Dependencies are installed to ~/.local/bin on Linux, except versions pinned by the repository, which are installed side by side into ~/.cache/terra/bin. The replaced binary is kept as <tool>.prev: use --rollback=<tool> to restore it.",
 			bind.Long,
 		)
 	})
@@ -120,4 +120,27 @@ func TestInstallDependenciesController_Execute(t *testing.T) {
 		// THEN: Should execute the command the correct number of times
 		assert.Equal(t, 3, mockCommand.ExecuteCallCount)
 	})
+
+	t.Run("should roll back the tool instead of installing when rollback flag provided", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: An install dependencies controller and a command with the rollback flag set
+		mockCommand := &commanddoubles.StubInstallDependenciesCommand{}
+		dependencies := []entities.Dependency{
+			entitybuilders.NewDependencyBuilder().
+				WithName("Terraform").
+				WithCLI("terraform").
+				BuildDependency(),
+		}
+		controller := controllers.NewInstallDependenciesController(mockCommand, dependencies)
+		cmd := &cobra.Command{}
+		cmd.Flags().String("rollback", "", "test flag")
+		require.NoError(t, cmd.Flags().Set("rollback", "terraform"))
+
+		// WHEN: Executing the controller
+		controller.Execute(cmd, []string{})
+
+		// THEN: Should roll back the tool without installing anything
+		assert.Equal(t, []string{"terraform"}, mockCommand.RolledBackTools)
+		assert.Equal(t, 0, mockCommand.ExecuteCallCount)
+	})
 }
@@ -8,15 +8,18 @@ import (
 )
 
 type RunFromRootController struct {
+	settings     *entities.Settings
 	command      commands.RunFromRoot
 	dependencies []entities.Dependency
 }
 
 func NewRunFromRootController(
+	settings *entities.Settings,
 	command commands.RunFromRoot,
 	dependencies []entities.Dependency,
 ) *RunFromRootController {
 	return &RunFromRootController{
+		settings:     settings,
 		command:      command,
 		dependencies: dependencies,
 	}
@@ -42,12 +45,33 @@ func (it *RunFromRootController) GetBind() entities.ControllerBind {
 			"                 with --filter='!mod' (recommended; supports globs, graph,\n" +
 			"                 and git-diff expressions) or --queue-exclude-dir=mod.\n" +
 			"\n" +
-			"These two strategies cannot be combined. See docs/parallel-execution.md.",
+			"These two strategies cannot be combined. See docs/parallel-execution.md.\n" +
+			"\n" +
+			"Profiles:\n" +
+			"\n" +
+			"  --profile=NAME Select a profile declared under 'profiles:' in .terra.yaml\n" +
+			"                 (also TERRA_PROFILE). It sets the account, workspace,\n" +
+			"                 TF_VAR_* values and, when no directory is given, the\n" +
+			"                 target directory.\n" +
+			"\n" +
+			"Formatting:\n" +
+			"\n" +
+			"  Before a run, terra formats the target directory and the Terragrunt files\n" +
+			"  it includes from above it (e.g. root.hcl).\n" +
+			"\n" +
+			"  --changed-only Format only the files among them that Git reports as changed.\n" +
+			"  --no-format    Skip the formatting (also TERRA_NO_FORMAT=true).",
 	}
 }
 
 func (it *RunFromRootController) Execute(_ *cobra.Command, arguments []string) {
-	absolutePath := helpers.ArgumentsHelper{}.FindAbsolutePath(arguments)
+	pathArguments := arguments
+	// Without a directory on the command line, the selected profile may name its own
+	hasPath := helpers.ArgumentsHelper{}.HasPath(arguments)
+	if profilePath := it.settings.GetProfileTargetPath(); profilePath != "" && !hasPath {
+		pathArguments = []string{profilePath}
+	}
+	absolutePath := helpers.ArgumentsHelper{}.FindAbsolutePath(pathArguments)
 	filteredArguments := helpers.ArgumentsHelper{}.RemovePathFromArguments(arguments)
 	it.command.Execute(absolutePath, filteredArguments, it.dependencies)
 }
@@ -3,6 +3,8 @@
 package controllers_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rios0rios0/terra/internal/domain/entities"
@@ -30,7 +32,7 @@ func TestNewRunFromRootController(t *testing.T) {
 		}
 
 		// WHEN: Creating a new run from root controller
-		controller := controllers.NewRunFromRootController(mockCommand, dependencies)
+		controller := controllers.NewRunFromRootController(&entities.Settings{}, mockCommand, dependencies)
 
 		// THEN: Should create a valid controller instance
 		require.NotNil(t, controller)
@@ -45,7 +47,7 @@ func TestRunFromRootController_GetBind(t *testing.T) {
 		// GIVEN: A run from root controller with mock command and empty dependencies
 		mockCommand := &commanddoubles.StubRunFromRootCommand{}
 		dependencies := []entities.Dependency{}
-		controller := controllers.NewRunFromRootController(mockCommand, dependencies)
+		controller := controllers.NewRunFromRootController(&entities.Settings{}, mockCommand, dependencies)
 
 		// WHEN: Getting the controller bind
 		bind := controller.GetBind()
@@ -76,7 +78,7 @@ func TestRunFromRootController_Execute(t *testing.T) {
 				WithCLI("terraform").
 				BuildDependency(),
 		}
-		controller := controllers.NewRunFromRootController(mockCommand, dependencies)
+		controller := controllers.NewRunFromRootController(&entities.Settings{}, mockCommand, dependencies)
 		cmd := &cobra.Command{}
 		args := []string{"apply", "."}
 
@@ -95,7 +97,7 @@ func TestRunFromRootController_Execute(t *testing.T) {
 		// GIVEN: A run from root controller with mock command and empty dependencies
 		mockCommand := &commanddoubles.StubRunFromRootCommand{}
 		dependencies := []entities.Dependency{}
-		controller := controllers.NewRunFromRootController(mockCommand, dependencies)
+		controller := controllers.NewRunFromRootController(&entities.Settings{}, mockCommand, dependencies)
 		cmd := &cobra.Command{}
 		args := []string{"plan", "--dry-run"}
 
@@ -112,7 +114,7 @@ func TestRunFromRootController_Execute(t *testing.T) {
 		// GIVEN: A run from root controller with mock command and empty dependencies
 		mockCommand := &commanddoubles.StubRunFromRootCommand{}
 		dependencies := []entities.Dependency{}
-		controller := controllers.NewRunFromRootController(mockCommand, dependencies)
+		controller := controllers.NewRunFromRootController(&entities.Settings{}, mockCommand, dependencies)
 		cmd := &cobra.Command{}
 		args := []string{"plan"}
 
@@ -125,3 +127,52 @@ func TestRunFromRootController_Execute(t *testing.T) {
 		assert.Equal(t, 3, mockCommand.ExecuteCallCount)
 	})
 }
+
+func TestRunFromRootController_ExecuteWithProfile(t *testing.T) {
+	t.Run("should use the profile path when no directory is given", func(t *testing.T) {
+		// GIVEN: A selected profile that declares its own target directory
+		t.Setenv("TERRA_PROFILE", "prod")
+		root := t.TempDir()
+		target := filepath.Join(root, "environments", "prod")
+		require.NoError(t, os.MkdirAll(target, 0o755))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(root, entities.ProjectConfigFileName),
+			[]byte("profiles:\n  prod:\n    path: environments/prod\n"),
+			0o600,
+		))
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(root))
+		mockCommand := &commanddoubles.StubRunFromRootCommand{}
+		controller := controllers.NewRunFromRootController(settings, mockCommand, []entities.Dependency{})
+
+		// WHEN: Executing the controller without a directory
+		controller.Execute(&cobra.Command{}, []string{"--profile=prod", "plan"})
+
+		// THEN: Should target the profile's directory
+		assert.Equal(t, target, mockCommand.LastTargetPath)
+	})
+
+	t.Run("should prefer the directory given on the command line over the profile path", func(t *testing.T) {
+		// GIVEN: A selected profile with a target directory and an explicit directory argument
+		t.Setenv("TERRA_PROFILE", "prod")
+		root := t.TempDir()
+		explicit := filepath.Join(root, "network")
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "environments", "prod"), 0o755))
+		require.NoError(t, os.MkdirAll(explicit, 0o755))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(root, entities.ProjectConfigFileName),
+			[]byte("profiles:\n  prod:\n    path: environments/prod\n"),
+			0o600,
+		))
+		settings := &entities.Settings{}
+		require.NoError(t, settings.LoadProjectConfig(root))
+		mockCommand := &commanddoubles.StubRunFromRootCommand{}
+		controller := controllers.NewRunFromRootController(settings, mockCommand, []entities.Dependency{})
+
+		// WHEN: Executing the controller with a directory
+		controller.Execute(&cobra.Command{}, []string{"--profile=prod", "plan", explicit})
+
+		// THEN: Should target the directory from the command line
+		assert.Equal(t, explicit, mockCommand.LastTargetPath)
+	})
+}
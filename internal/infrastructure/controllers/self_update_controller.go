@@ -19,11 +19,18 @@ func (it *SelfUpdateController) GetBind() entities.ControllerBind {
 	return entities.ControllerBind{
 		Use:   "self-update",
 		Short: "Update terra to the latest version",
-		Long:  "Download and install the latest version of terra from GitHub releases.",
+		Long:  "Download and install the latest version of terra from GitHub releases. The replaced binary is kept as terra.prev: use --rollback to restore it.",
 	}
 }
 
 func (it *SelfUpdateController) Execute(cmd *cobra.Command, _ []string) {
+	if rollback, _ := cmd.Flags().GetBool("rollback"); rollback {
+		if err := it.command.Rollback(); err != nil {
+			logger.Fatalf("Rollback failed: %s", err)
+		}
+		return
+	}
+
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	force, _ := cmd.Flags().GetBool("force")
 
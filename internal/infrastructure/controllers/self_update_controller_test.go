@@ -45,7 +45,7 @@ func TestSelfUpdateController_GetBind(t *testing.T) {
 		// THEN: Should return correct binding details
 		assert.Equal(t, "self-update", bind.Use)
 		assert.Equal(t, "Update terra to the latest version", bind.Short)
-		assert.Equal(t, "Download and install the latest version of terra from GitHub releases.", bind.Long)
+		assert.Equal(t, "Download and install the latest version of terra from GitHub releases. The replaced binary is kept as terra.prev: use --rollback to restore it.", bind.Long)
 	})
 }
 
@@ -89,4 +89,21 @@ func TestSelfUpdateController_Execute(t *testing.T) {
 		assert.False(t, mockCommand.DryRunFlag)
 		assert.True(t, mockCommand.ForceFlag)
 	})
+
+	t.Run("should roll back instead of updating when rollback flag provided", func(t *testing.T) {
+		// GIVEN: A mock command and a cobra command with the rollback flag set
+		mockCommand := commanddoubles.NewStubSelfUpdateCommand()
+		controller := controllers.NewSelfUpdateController(mockCommand)
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", false, "test flag")
+		cmd.Flags().Bool("force", false, "test flag")
+		cmd.Flags().Bool("rollback", true, "test flag")
+
+		// WHEN: Executing the controller
+		controller.Execute(cmd, []string{})
+
+		// THEN: Should roll back without updating
+		assert.Equal(t, 1, mockCommand.RollbackCount)
+		assert.False(t, mockCommand.WasCalled())
+	})
 }
@@ -25,7 +25,7 @@ func (it *UpdateDependenciesController) GetBind() entities.ControllerBind {
 	return entities.ControllerBind{
 		Use:   "update",
 		Short: "Install or update Terraform and Terragrunt to the latest versions",
-		Long:  "Install all the dependencies required to run Terra, or update them if newer versions are available. Dependencies are installed to ~/.local/bin on Linux. This is an alias for the 'install' command.",
+		Long:  "Install all the dependencies required to run Terra, or update them if newer versions are available. Dependencies are installed to ~/.local/bin on Linux, except versions pinned by the repository, which are installed side by side into ~/.cache/terra/bin. This is an alias for the 'install' command.",
 	}
 }
 
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers/helpers"
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type UseVersionController struct {
+	command      commands.UseVersion
+	dependencies []entities.Dependency
+}
+
+func NewUseVersionController(
+	command commands.UseVersion,
+	dependencies []entities.Dependency,
+) *UseVersionController {
+	return &UseVersionController{
+		command:      command,
+		dependencies: dependencies,
+	}
+}
+
+func (it *UseVersionController) GetBind() entities.ControllerBind {
+	return entities.ControllerBind{
+		Use:   "use <tool>@<version> [directory]",
+		Short: "Pin and install a Terraform or Terragrunt version for a directory",
+		Long: "Pin a Terraform or Terragrunt version for a directory (the current one by default) by " +
+			"writing its .terraform-version or .terragrunt-version file, and install that version into " +
+			"the tool cache (~/.cache/terra/bin) next to the other versions. Every terra command run in " +
+			"the directory or below it then switches to the pinned binaries, e.g. terra use terraform@1.9.8.",
+	}
+}
+
+func (it *UseVersionController) Execute(_ *cobra.Command, arguments []string) {
+	if len(arguments) == 0 {
+		logger.Fatalf("Missing tool version, usage: terra %s", it.GetBind().Use)
+	}
+
+	tool, version, found := strings.Cut(arguments[0], "@")
+	if !found || tool == "" || version == "" {
+		logger.Fatalf("Invalid tool version %q, usage: terra %s", arguments[0], it.GetBind().Use)
+	}
+
+	targetPath := helpers.ArgumentsHelper{}.FindAbsolutePath(arguments[1:])
+	if err := it.command.Execute(targetPath, tool, version, it.dependencies); err != nil {
+		logger.Fatalf("Error switching %s version: %s", tool, err)
+	}
+}
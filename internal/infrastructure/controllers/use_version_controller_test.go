@@ -0,0 +1,51 @@
+//go:build unit
+
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	"github.com/rios0rios0/terra/internal/infrastructure/controllers"
+	"github.com/rios0rios0/terra/test/domain/commanddoubles"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseVersionController_GetBind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return use bind when called", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A use version controller with mock command
+		controller := controllers.NewUseVersionController(&commanddoubles.StubUseVersionCommand{}, nil)
+
+		// WHEN: Getting the controller bind
+		bind := controller.GetBind()
+
+		// THEN: Should expose the "use" usage
+		assert.Equal(t, "use <tool>@<version> [directory]", bind.Use)
+		assert.Contains(t, bind.Long, ".terraform-version")
+	})
+}
+
+func TestUseVersionController_Execute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should split the tool and the version when given tool@version", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A use version controller and a target directory
+		mockCommand := &commanddoubles.StubUseVersionCommand{}
+		controller := controllers.NewUseVersionController(mockCommand, []entities.Dependency{})
+		targetPath := t.TempDir()
+
+		// WHEN: Executing "use terraform@1.9.8 <directory>"
+		controller.Execute(&cobra.Command{}, []string{"terraform@1.9.8", targetPath})
+
+		// THEN: Should execute the command for that tool, version and directory
+		assert.Equal(t, 1, mockCommand.ExecuteCallCount)
+		assert.Equal(t, "terraform", mockCommand.LastTool)
+		assert.Equal(t, "1.9.8", mockCommand.LastVersion)
+		assert.Equal(t, targetPath, mockCommand.LastTargetPath)
+	})
+}
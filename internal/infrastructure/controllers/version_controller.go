@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"os"
+
 	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -18,10 +21,24 @@ func (it *VersionController) GetBind() entities.ControllerBind {
 	return entities.ControllerBind{
 		Use:   "version",
 		Short: "Show Terra, Terraform, and Terragrunt versions",
-		Long:  "Display the version information for Terra and its dependencies (Terraform and Terragrunt).",
+		Long: "Display the version information for Terra and its dependencies (Terraform or OpenTofu, and Terragrunt). " +
+			"Use --output=json for a machine-readable report with the install paths and whether each version " +
+			"satisfies the repository's pins and constraints, --latest to also look up the latest available " +
+			"versions, and --check to exit with an error when a dependency is missing, outdated or mismatched.",
 	}
 }
 
-func (it *VersionController) Execute(_ *cobra.Command, _ []string) {
-	it.command.Execute()
+// Execute reads the flags when the command was parsed by cobra; `terra --version` runs it
+// without a command and prints the default report.
+func (it *VersionController) Execute(cmd *cobra.Command, _ []string) {
+	options := commands.VersionOptions{}
+	if cmd != nil {
+		options.Output, _ = cmd.Flags().GetString("output")
+		options.Latest, _ = cmd.Flags().GetBool("latest")
+		options.Check, _ = cmd.Flags().GetBool("check")
+	}
+
+	if err := it.command.Execute(options, os.Stdout); err != nil {
+		logger.Fatalf("Error: %s", err)
+	}
 }
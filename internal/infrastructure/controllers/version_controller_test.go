@@ -5,6 +5,7 @@ package controllers_test
 import (
 	"testing"
 
+	"github.com/rios0rios0/terra/internal/domain/commands"
 	"github.com/rios0rios0/terra/internal/infrastructure/controllers"
 	"github.com/rios0rios0/terra/test/domain/commanddoubles"
 
@@ -44,11 +45,12 @@ func TestVersionController_GetBind(t *testing.T) {
 		// THEN: Should return correct bind configuration
 		assert.Equal(t, "version", bind.Use)
 		assert.Equal(t, "Show Terra, Terraform, and Terragrunt versions", bind.Short)
-		assert.Equal(
+		assert.Contains(
 			t,
-			"Display the version information for Terra and its dependencies (Terraform and Terragrunt).",
 			bind.Long,
+			"Display the version information for Terra and its dependencies (Terraform or OpenTofu, and Terragrunt).",
 		)
+		assert.Contains(t, bind.Long, "--output=json")
 	})
 }
 
@@ -85,4 +87,35 @@ func TestVersionController_Execute(t *testing.T) {
 		// THEN: Should execute the command the correct number of times
 		assert.Equal(t, 2, mockCommand.ExecuteCallCount)
 	})
+
+	t.Run("should pass the output and check flags to the command", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A version command parsed with --output=json --check
+		mockCommand := &commanddoubles.StubVersionCommand{}
+		controller := controllers.NewVersionController(mockCommand)
+		cmd := &cobra.Command{}
+		cmd.Flags().String("output", "text", "")
+		cmd.Flags().Bool("latest", false, "")
+		cmd.Flags().Bool("check", false, "")
+		require.NoError(t, cmd.Flags().Parse([]string{"--output=json", "--check"}))
+
+		// WHEN: Executing the controller
+		controller.Execute(cmd, []string{})
+
+		// THEN: Should forward the options
+		assert.Equal(t, commands.VersionOptions{Output: "json", Check: true}, mockCommand.LastOptions)
+	})
+
+	t.Run("should use the default options when run through terra --version", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A version controller invoked without a cobra command
+		mockCommand := &commanddoubles.StubVersionCommand{}
+		controller := controllers.NewVersionController(mockCommand)
+
+		// WHEN: Executing the controller
+		controller.Execute(nil, []string{})
+
+		// THEN: Should execute the command with the default options
+		assert.Equal(t, commands.VersionOptions{}, mockCommand.LastOptions)
+	})
 }
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	commandStartedEvent   = "command_started"
+	commandCompletedEvent = "command_completed"
+	commandFailedEvent    = "command_failed"
+)
+
+// commandLog describes one command execution for the "Running", "Completed" and "Failed"
+// log lines. Each line also carries the details as structured fields, which the JSON log
+// format (--log-format=json) emits as one object per event, and the execution is traced
+// as a span of the worker's current phase.
+type commandLog struct {
+	command   string
+	arguments []string // already redacted
+	directory string
+	worker    int // 1-based worker of the parallel pool, 0 outside of it
+	span      *entities.Span
+}
+
+func newCommandLog(redactor *entities.Redactor, command string, arguments []string, directory string) *commandLog {
+	return &commandLog{
+		command:   command,
+		arguments: redactor.RedactArguments(arguments),
+		directory: directory,
+	}
+}
+
+// started logs the command line before it runs and opens its span; detail is appended
+// to the message.
+func (it *commandLog) started(detail string) {
+	it.entry(commandStartedEvent).Infof("Running [%s] in %s%s", it.commandLine(), it.directory, detail)
+	it.span = entities.StartSpan(it.worker, it.spanName(),
+		attribute.String("terra.command", it.command),
+		attribute.StringSlice("terra.arguments", it.arguments),
+		attribute.String("terra.module", it.directory),
+		attribute.Int("terra.worker", it.worker),
+	)
+}
+
+// finished logs the elapsed time and exit code, using Warn level for failures and Info
+// level for successes, and closes the span.
+func (it *commandLog) finished(elapsed time.Duration, err error) {
+	if it.span != nil {
+		it.span.SetAttributes(attribute.Int("terra.exit_code", entities.ExitCode(err)))
+		it.span.End(err)
+	}
+
+	if err != nil {
+		it.entry(commandFailedEvent).WithFields(logger.Fields{
+			"duration_seconds": elapsed.Seconds(),
+			"exit_code":        entities.ExitCode(err),
+		}).Warnf("Failed [%s] in %s (took %.2fs)", it.commandLine(), it.directory, elapsed.Seconds())
+		return
+	}
+
+	it.entry(commandCompletedEvent).WithFields(logger.Fields{
+		"duration_seconds": elapsed.Seconds(),
+		"exit_code":        0,
+	}).Infof("Completed [%s] in %s (took %.2fs)", it.commandLine(), it.directory, elapsed.Seconds())
+}
+
+func (it *commandLog) entry(event string) *logger.Entry {
+	fields := logger.Fields{
+		"event":     event,
+		"command":   it.command,
+		"arguments": it.arguments,
+		"module":    it.directory,
+	}
+	if it.worker > 0 {
+		fields["worker"] = it.worker
+	}
+	return logger.WithFields(fields)
+}
+
+// spanName names the span after the command and its subcommand (e.g. "terragrunt plan"),
+// leaving the other arguments, which vary between runs, to the attributes.
+func (it *commandLog) spanName() string {
+	for _, argument := range it.arguments {
+		if !strings.HasPrefix(argument, "-") {
+			return it.command + " " + argument
+		}
+	}
+	return it.command
+}
+
+func (it *commandLog) commandLine() string {
+	return it.command + " " + strings.Join(it.arguments, " ")
+}
@@ -28,6 +28,12 @@ func RegisterProviders(container *dig.Container) error {
 	}); err != nil {
 		return err
 	}
+	// Bind OutputShellRepository interface to implementation (captured output for credentials)
+	if err := container.Provide(func(impl *StdShellRepository) repositories.OutputShellRepository {
+		return impl
+	}); err != nil {
+		return err
+	}
 	// Bind UpgradeShellRepository interface to implementation
 	if err := container.Provide(func(impl *UpgradeAwareShellRepository) repositories.UpgradeShellRepository {
 		return impl
@@ -8,8 +8,10 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/creack/pty"
+	"github.com/rios0rios0/terra/internal/domain/entities"
 	logger "github.com/sirupsen/logrus"
 )
 
@@ -20,10 +22,12 @@ const (
 )
 
 // InteractiveShellRepository handles interactive commands with auto-answering capabilities.
-type InteractiveShellRepository struct{}
+type InteractiveShellRepository struct {
+	redactor *entities.Redactor
+}
 
-func NewInteractiveShellRepository() *InteractiveShellRepository {
-	return &InteractiveShellRepository{}
+func NewInteractiveShellRepository(redactor *entities.Redactor) *InteractiveShellRepository {
+	return &InteractiveShellRepository{redactor: redactor}
 }
 
 func (it *InteractiveShellRepository) ExecuteCommand(
@@ -40,13 +44,9 @@ func (it *InteractiveShellRepository) ExecuteCommandWithAnswer(
 	directory string,
 	autoAnswer string,
 ) error {
-	logger.Infof(
-		"Running [%s %s] in %s with auto-answering (%s)",
-		command,
-		strings.Join(arguments, " "),
-		directory,
-		autoAnswer,
-	)
+	execLog := newCommandLog(it.redactor, command, arguments, directory)
+	execLog.started(fmt.Sprintf(" with auto-answering (%s)", autoAnswer))
+	start := time.Now()
 
 	cmd := exec.CommandContext(context.Background(), command, arguments...)
 	cmd.Dir = directory
@@ -57,6 +57,7 @@ func (it *InteractiveShellRepository) ExecuteCommandWithAnswer(
 	// Start the command with a pseudo-terminal to preserve interactivity
 	ptmx, err := pty.Start(cmd)
 	if err != nil {
+		execLog.finished(time.Since(start), err)
 		return fmt.Errorf("failed to start command with PTY: %w", err)
 	}
 	defer func() {
@@ -78,6 +79,7 @@ func (it *InteractiveShellRepository) ExecuteCommandWithAnswer(
 
 	// Wait for the command to complete
 	waitErr := cmd.Wait()
+	execLog.finished(time.Since(start), waitErr)
 	if waitErr != nil {
 		waitErr = fmt.Errorf("failed to perform command execution: %w", waitErr)
 	}
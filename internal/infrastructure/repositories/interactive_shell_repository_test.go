@@ -19,7 +19,7 @@ func TestNewInteractiveShellRepository(t *testing.T) {
 		// GIVEN: No preconditions needed
 
 		// WHEN: Creating a new interactive shell repository
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 
 		// THEN: Should return a valid repository instance
 		require.NotNil(t, repo, "NewInteractiveShellRepository should not return nil")
@@ -32,7 +32,7 @@ func TestInteractiveShellRepository_ExecuteCommandWithAnswer(t *testing.T) {
 	t.Run("should execute successfully with auto-answer when command exits quickly", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository and a command that exits immediately
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 
 		// WHEN: Executing with auto-answer
 		err := repo.ExecuteCommandWithAnswer("echo", []string{"hello"}, ".", "y")
@@ -44,7 +44,7 @@ func TestInteractiveShellRepository_ExecuteCommandWithAnswer(t *testing.T) {
 	t.Run("should return error when invalid command provided with auto-answer", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository and an invalid command
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 
 		// WHEN: Executing with auto-answer
 		err := repo.ExecuteCommandWithAnswer("nonexistentcommand12345", []string{}, ".", "n")
@@ -56,7 +56,7 @@ func TestInteractiveShellRepository_ExecuteCommandWithAnswer(t *testing.T) {
 	t.Run("should handle multi-line output with ANSI codes when auto-answering", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository and a command producing ANSI-coded output
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 
 		// WHEN: Executing a command that produces colored output
 		err := repo.ExecuteCommandWithAnswer("sh", []string{"-c", "printf '\\033[32mgreen\\033[0m\\n'; printf '\\033[31mred\\033[0m\\n'"}, ".", "y")
@@ -72,7 +72,7 @@ func TestInteractiveShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should execute successfully when valid command provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository instance and valid command parameters
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 		command := "echo"
 		args := []string{"test"}
 		workingDir := "."
@@ -87,7 +87,7 @@ func TestInteractiveShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should return error when invalid command provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository instance and invalid command
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 		invalidCommand := "nonexistentcommand12345"
 		args := []string{}
 		workingDir := "."
@@ -102,7 +102,7 @@ func TestInteractiveShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should return error when invalid directory provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository instance and invalid working directory
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 		command := "echo"
 		args := []string{"test"}
 		invalidDir := "/nonexistent/directory/12345"
@@ -117,7 +117,7 @@ func TestInteractiveShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should complete execution without hanging when command produces output", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository instance and command that produces multiple lines of output
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 		command := "sh"
 		args := []string{"-c", "echo 'line1'; echo 'line2'; echo 'line3'"}
 		workingDir := "."
@@ -132,7 +132,7 @@ func TestInteractiveShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should complete execution without hanging when command produces no output", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: An interactive shell repository instance and command that produces no output
-		repo := repositories.NewInteractiveShellRepository()
+		repo := repositories.NewInteractiveShellRepository(nil)
 		command := "sh"
 		args := []string{"-c", "exit 0"}
 		workingDir := "."
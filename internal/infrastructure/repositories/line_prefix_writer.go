@@ -7,6 +7,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/rios0rios0/terra/internal/domain/entities"
 	"golang.org/x/term"
 )
 
@@ -16,18 +17,25 @@ import (
 // writer are safe, while the shared mu serializes writes to dest so output from
 // concurrently executing modules never interleaves mid-line.
 type LinePrefixWriter struct {
-	dest   io.Writer
-	prefix []byte
-	mu     *sync.Mutex // shared across writers: serializes writes to dest
-	bufMu  sync.Mutex  // per-writer: guards buf for concurrent Write/Flush
-	buf    []byte
+	dest     io.Writer
+	prefix   []byte
+	mu       *sync.Mutex // shared across writers: serializes writes to dest
+	bufMu    sync.Mutex  // per-writer: guards buf for concurrent Write/Flush
+	buf      []byte
+	redactor *entities.Redactor
 }
 
 // NewLinePrefixWriter builds a writer that labels every line with "[label] ". When dest is
 // an interactive terminal (and NO_COLOR is unset), the label is colorized with a stable
 // per-label color so parallel module streams are easy to tell apart. The mu is shared
-// across all writers targeting the same console so their lines stay separated.
-func NewLinePrefixWriter(dest io.Writer, label string, mu *sync.Mutex) *LinePrefixWriter {
+// across all writers targeting the same console so their lines stay separated. Every line
+// goes through the redactor (which may be nil) before it reaches dest.
+func NewLinePrefixWriter(
+	dest io.Writer,
+	label string,
+	mu *sync.Mutex,
+	redactor *entities.Redactor,
+) *LinePrefixWriter {
 	rendered := "[" + label + "]"
 	if shouldColorize(dest) {
 		start, reset := colorForLabel(label)
@@ -35,9 +43,10 @@ func NewLinePrefixWriter(dest io.Writer, label string, mu *sync.Mutex) *LinePref
 	}
 
 	return &LinePrefixWriter{
-		dest:   dest,
-		prefix: []byte(rendered + " "),
-		mu:     mu,
+		dest:     dest,
+		prefix:   []byte(rendered + " "),
+		mu:       mu,
+		redactor: redactor,
 	}
 }
 
@@ -80,9 +89,13 @@ func (w *LinePrefixWriter) Flush() {
 	w.buf = nil
 }
 
-// emit writes the prefix and the line to dest as a single locked write so lines from
-// other writers sharing mu cannot split this one.
+// emit writes the prefix and the redacted line to dest as a single locked write so lines
+// from other writers sharing mu cannot split this one.
 func (w *LinePrefixWriter) emit(line []byte) {
+	if w.redactor != nil {
+		line = []byte(w.redactor.Redact(string(line)))
+	}
+
 	out := make([]byte, 0, len(w.prefix)+len(line))
 	out = append(out, w.prefix...)
 	out = append(out, line...)
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/internal/infrastructure/repositories"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,7 +22,7 @@ func TestLinePrefixWriter_Write(t *testing.T) {
 		t.Parallel()
 		// given
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, nil)
 
 		// when
 		n, err := writer.Write([]byte("hello world\n"))
@@ -36,7 +37,7 @@ func TestLinePrefixWriter_Write(t *testing.T) {
 		t.Parallel()
 		// given
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, nil)
 
 		// when
 		_, err := writer.Write([]byte("line one\nline two\nline three\n"))
@@ -50,7 +51,7 @@ func TestLinePrefixWriter_Write(t *testing.T) {
 		t.Parallel()
 		// given
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, nil)
 
 		// when: a fragment without a newline is written
 		_, err := writer.Write([]byte("partial "))
@@ -71,7 +72,7 @@ func TestLinePrefixWriter_Write(t *testing.T) {
 		t.Parallel()
 		// given
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, nil)
 
 		// when
 		_, err := writer.Write([]byte("text\n"))
@@ -81,6 +82,23 @@ func TestLinePrefixWriter_Write(t *testing.T) {
 		assert.Equal(t, "[mod1] text\n", dest.String())
 		assert.NotContains(t, dest.String(), "\x1b[")
 	})
+
+	t.Run("should redact secrets split across writes before emitting the line", func(t *testing.T) {
+		t.Parallel()
+		// given
+		var dest bytes.Buffer
+		redactor := entities.NewRedactor(&entities.Settings{TerraRedactPatterns: []string{`token=(\S+)`}})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, redactor)
+
+		// when: the secret arrives in two fragments
+		_, err := writer.Write([]byte("token=ab"))
+		require.NoError(t, err)
+		_, err = writer.Write([]byte("c123 ok\n"))
+
+		// then: the emitted line carries the prefix and no secret
+		require.NoError(t, err)
+		assert.Equal(t, "[mod1] token=<redacted> ok\n", dest.String())
+	})
 }
 
 func TestLinePrefixWriter_Flush(t *testing.T) {
@@ -90,7 +108,7 @@ func TestLinePrefixWriter_Flush(t *testing.T) {
 		t.Parallel()
 		// given
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, nil)
 		_, err := writer.Write([]byte("no trailing newline"))
 		require.NoError(t, err)
 
@@ -105,7 +123,7 @@ func TestLinePrefixWriter_Flush(t *testing.T) {
 		t.Parallel()
 		// given
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod1", &sync.Mutex{}, nil)
 		_, err := writer.Write([]byte("complete\n"))
 		require.NoError(t, err)
 
@@ -132,7 +150,7 @@ func TestLinePrefixWriter_Concurrency(t *testing.T) {
 		var wg sync.WaitGroup
 		for w := range writerCount {
 			label := fmt.Sprintf("mod%d", w)
-			writer := repositories.NewLinePrefixWriter(&dest, label, mu)
+			writer := repositories.NewLinePrefixWriter(&dest, label, mu, nil)
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
@@ -161,7 +179,7 @@ func TestLinePrefixWriter_Concurrency(t *testing.T) {
 		const goroutines = 8
 		const linesPerGoroutine = 100
 		var dest bytes.Buffer
-		writer := repositories.NewLinePrefixWriter(&dest, "mod", &sync.Mutex{})
+		writer := repositories.NewLinePrefixWriter(&dest, "mod", &sync.Mutex{}, nil)
 
 		// when: goroutines Write complete lines concurrently and each Flushes after
 		var wg sync.WaitGroup
@@ -1,16 +1,16 @@
 package repositories
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"strings"
 	"sync"
 	"time"
 
-	logger "github.com/sirupsen/logrus"
+	"github.com/rios0rios0/terra/internal/domain/entities"
 )
 
 // Allow long-running terraform/terragrunt commands
@@ -22,10 +22,11 @@ type StdShellRepository struct {
 	// mid-line. It lives on the struct (not as a package global) because DIG provides a
 	// single StdShellRepository instance that every parallel worker shares.
 	consoleMu sync.Mutex
+	redactor  *entities.Redactor
 }
 
-func NewStdShellRepository() *StdShellRepository {
-	return &StdShellRepository{}
+func NewStdShellRepository(redactor *entities.Redactor) *StdShellRepository {
+	return &StdShellRepository{redactor: redactor}
 }
 
 // ExecuteCommand runs a command with its stdio connected directly to the terminal. Use it
@@ -35,23 +36,41 @@ func (it *StdShellRepository) ExecuteCommand(
 	arguments []string,
 	directory string,
 ) error {
-	return it.run(command, arguments, directory, os.Stdout, os.Stderr, os.Stdin)
+	return it.run(command, arguments, directory, nil, 0, os.Stdout, os.Stderr, os.Stdin)
+}
+
+// ExecuteCommandWithEnvironment runs a command on the terminal like ExecuteCommand, with
+// the environment entries appended to the inherited process environment of the child only.
+func (it *StdShellRepository) ExecuteCommandWithEnvironment(
+	command string,
+	arguments []string,
+	directory string,
+	environment []string,
+) error {
+	return it.run(command, arguments, directory, environment, 0, os.Stdout, os.Stderr, os.Stdin)
 }
 
 // ExecuteCommandWithPrefix runs a command while streaming its stdout and stderr through
 // per-line prefix writers, so concurrent module executions stay attributable in the
 // combined console output. Stdin is left disconnected because parallel workers cannot
 // share a single terminal; callers must run non-interactively (e.g. via --yes/--no).
+// The environment entries are appended to the inherited process environment of the child
+// only, so concurrent workers can target different accounts. Secrets are masked in the
+// streamed lines, including secret variables set only in those entries. The worker
+// (1-based) is recorded in the command's structured log fields.
 func (it *StdShellRepository) ExecuteCommandWithPrefix(
 	command string,
 	arguments []string,
 	directory string,
 	prefix string,
+	worker int,
+	environment []string,
 ) error {
-	stdout := NewLinePrefixWriter(os.Stdout, prefix, &it.consoleMu)
-	stderr := NewLinePrefixWriter(os.Stderr, prefix, &it.consoleMu)
+	redactor := it.redactor.ForEnvironment(environment)
+	stdout := NewLinePrefixWriter(os.Stdout, prefix, &it.consoleMu, redactor)
+	stderr := NewLinePrefixWriter(os.Stderr, prefix, &it.consoleMu, redactor)
 
-	err := it.run(command, arguments, directory, stdout, stderr, nil)
+	err := it.run(command, arguments, directory, environment, worker, stdout, stderr, nil)
 
 	// Emit any trailing output that did not end with a newline.
 	stdout.Flush()
@@ -60,15 +79,35 @@ func (it *StdShellRepository) ExecuteCommandWithPrefix(
 	return err
 }
 
-// run executes the command with the given stdio wiring and logs its duration.
+// ExecuteCommandWithOutput runs a command and returns its standard output. Stderr is still
+// forwarded to the console so failures remain visible, while stdout is kept off the
+// terminal because it may carry secrets.
+func (it *StdShellRepository) ExecuteCommandWithOutput(
+	command string,
+	arguments []string,
+	directory string,
+	environment []string,
+) (string, error) {
+	var stdout bytes.Buffer
+	err := it.run(command, arguments, directory, environment, 0, &stdout, os.Stderr, nil)
+	return stdout.String(), err
+}
+
+// run executes the command with the given stdio wiring and logs its duration. Non-empty
+// environment entries are layered over the inherited process environment. Only the
+// logged command line is redacted; the child always receives the original arguments.
 func (it *StdShellRepository) run(
 	command string,
 	arguments []string,
 	directory string,
+	environment []string,
+	worker int,
 	stdout, stderr io.Writer,
 	stdin io.Reader,
 ) error {
-	logger.Infof("Running [%s %s] in %s", command, strings.Join(arguments, " "), directory)
+	execLog := newCommandLog(it.redactor.ForEnvironment(environment), command, arguments, directory)
+	execLog.worker = worker
+	execLog.started("")
 	start := time.Now()
 
 	cmd := exec.CommandContext(context.Background(), command, arguments...)
@@ -76,22 +115,14 @@ func (it *StdShellRepository) run(
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 	cmd.Stdin = stdin
+	if len(environment) > 0 {
+		cmd.Env = append(os.Environ(), environment...)
+	}
 
 	err := cmd.Run()
-	logCommandDuration(command, arguments, directory, time.Since(start), err)
+	execLog.finished(time.Since(start), err)
 	if err != nil {
 		err = fmt.Errorf("failed to perform command execution: %w", err)
 	}
 	return err
 }
-
-// logCommandDuration logs the elapsed time for a command execution, using Warn level for
-// failures and Info level for successes.
-func logCommandDuration(command string, arguments []string, directory string, elapsed time.Duration, err error) {
-	args := strings.Join(arguments, " ")
-	if err != nil {
-		logger.Warnf("Failed [%s %s] in %s (took %.2fs)", command, args, directory, elapsed.Seconds())
-	} else {
-		logger.Infof("Completed [%s %s] in %s (took %.2fs)", command, args, directory, elapsed.Seconds())
-	}
-}
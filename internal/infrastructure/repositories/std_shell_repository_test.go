@@ -3,11 +3,15 @@
 package repositories_test
 
 import (
+	"os"
 	"testing"
 
+	logger "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rios0rios0/terra/internal/domain/entities"
 	"github.com/rios0rios0/terra/internal/infrastructure/repositories"
 )
 
@@ -19,7 +23,7 @@ func TestNewStdShellRepository(t *testing.T) {
 		// GIVEN: No preconditions needed
 
 		// WHEN: Creating a new std shell repository
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 
 		// THEN: Should return a valid repository instance
 		require.NotNil(t, repo, "NewStdShellRepository should not return nil")
@@ -32,7 +36,7 @@ func TestStdShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should execute successfully when valid command provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and valid command parameters
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 		command := "echo"
 		args := []string{"test"}
 		workingDir := "."
@@ -47,7 +51,7 @@ func TestStdShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should return error when invalid command provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and invalid command
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 		invalidCommand := "nonexistentcommand12345"
 		args := []string{}
 		workingDir := "."
@@ -64,7 +68,7 @@ func TestStdShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should return error when invalid directory provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and invalid working directory
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 		command := "echo"
 		args := []string{"test"}
 		invalidDir := "/nonexistent/directory/12345"
@@ -81,7 +85,7 @@ func TestStdShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should execute successfully when empty arguments provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and command with empty arguments
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 		command := "echo"
 		emptyArgs := []string{}
 		workingDir := "."
@@ -96,7 +100,7 @@ func TestStdShellRepository_ExecuteCommand(t *testing.T) {
 	t.Run("should execute successfully when multiple arguments provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and command with multiple arguments
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 		command := "echo"
 		multipleArgs := []string{"hello", "world", "test"}
 		workingDir := "."
@@ -109,16 +113,37 @@ func TestStdShellRepository_ExecuteCommand(t *testing.T) {
 	})
 }
 
+func TestStdShellRepository_ExecuteCommandWithEnvironment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should expose environment entries only to the child process", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A repository instance and an extra environment entry
+		repo := repositories.NewStdShellRepository(nil)
+		environment := []string{"TERRA_TEST_HOOK_VALUE=scoped"}
+
+		// WHEN: Executing a command that fails unless the variable is set
+		err := repo.ExecuteCommandWithEnvironment(
+			"sh", []string{"-c", `test "$TERRA_TEST_HOOK_VALUE" = scoped`}, ".", environment,
+		)
+
+		// THEN: Should see the value in the child but not in the current process
+		require.NoError(t, err)
+		_, found := os.LookupEnv("TERRA_TEST_HOOK_VALUE")
+		assert.False(t, found)
+	})
+}
+
 func TestStdShellRepository_ExecuteCommandWithPrefix(t *testing.T) {
 	t.Parallel()
 
 	t.Run("should execute successfully when valid command provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and a valid command
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 
 		// WHEN: Executing a valid command with a module prefix
-		err := repo.ExecuteCommandWithPrefix("echo", []string{"hello", "world"}, ".", "module1")
+		err := repo.ExecuteCommandWithPrefix("echo", []string{"hello", "world"}, ".", "module1", 1, nil)
 
 		// THEN: Should execute without error (output is streamed through the prefix writer)
 		assert.NoError(t, err, "Expected no error for valid prefixed command execution")
@@ -127,10 +152,10 @@ func TestStdShellRepository_ExecuteCommandWithPrefix(t *testing.T) {
 	t.Run("should return error when invalid command provided", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A repository instance and an invalid command
-		repo := repositories.NewStdShellRepository()
+		repo := repositories.NewStdShellRepository(nil)
 
 		// WHEN: Executing an invalid command with a module prefix
-		err := repo.ExecuteCommandWithPrefix("nonexistentcommand12345", []string{}, ".", "module1")
+		err := repo.ExecuteCommandWithPrefix("nonexistentcommand12345", []string{}, ".", "module1", 1, nil)
 
 		// THEN: Should return an error with the expected message
 		require.Error(t, err, "Expected error for invalid prefixed command")
@@ -138,3 +163,78 @@ func TestStdShellRepository_ExecuteCommandWithPrefix(t *testing.T) {
 			"Error message should contain expected text")
 	})
 }
+
+func TestStdShellRepository_CommandLogFields(t *testing.T) {
+	t.Run("should log the started and failed events with structured fields", func(t *testing.T) {
+		// GIVEN: A repository with a secret pattern and a command that exits with code 3
+		hook := test.NewLocal(logger.StandardLogger())
+		defer hook.Reset()
+		redactor := entities.NewRedactor(&entities.Settings{TerraRedactPatterns: []string{`token=(\S+)`}})
+		repo := repositories.NewStdShellRepository(redactor)
+
+		// WHEN: Executing the command as worker 2 of the parallel pool
+		err := repo.ExecuteCommandWithPrefix("sh", []string{"-c", "exit 3", "token=abc"}, ".", "module1", 2, nil)
+
+		// THEN: Should log both events with the redacted arguments, exit code and worker
+		require.Error(t, err)
+		var events []*logger.Entry
+		for _, entry := range hook.AllEntries() {
+			if entry.Data["command"] == "sh" && entry.Data["worker"] == 2 {
+				events = append(events, entry)
+			}
+		}
+		require.Len(t, events, 2)
+		assert.Equal(t, "command_started", events[0].Data["event"])
+		assert.Equal(t, []string{"-c", "exit 3", "token=<redacted>"}, events[0].Data["arguments"])
+		assert.Equal(t, ".", events[0].Data["module"])
+		assert.Equal(t, "command_failed", events[1].Data["event"])
+		assert.Equal(t, 3, events[1].Data["exit_code"])
+		assert.Contains(t, events[1].Data, "duration_seconds")
+		assert.NotContains(t, events[1].Message, "abc")
+	})
+}
+
+func TestStdShellRepository_ExecuteCommandWithOutput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return captured output when command succeeds", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A repository instance and a command printing to stdout
+		repo := repositories.NewStdShellRepository(nil)
+
+		// WHEN: Executing the command capturing its output
+		output, err := repo.ExecuteCommandWithOutput("echo", []string{"hello"}, ".", nil)
+
+		// THEN: Should return what the command printed
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", output)
+	})
+
+	t.Run("should expose environment entries only to the child process", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A repository instance and an extra environment entry
+		repo := repositories.NewStdShellRepository(nil)
+		environment := []string{"TERRA_TEST_SCOPED_VALUE=scoped"}
+
+		// WHEN: Executing a command that prints the variable
+		output, err := repo.ExecuteCommandWithOutput("sh", []string{"-c", "echo $TERRA_TEST_SCOPED_VALUE"}, ".", environment)
+
+		// THEN: Should see the value in the child but not in the current process
+		require.NoError(t, err)
+		assert.Equal(t, "scoped\n", output)
+		_, found := os.LookupEnv("TERRA_TEST_SCOPED_VALUE")
+		assert.False(t, found)
+	})
+
+	t.Run("should return error when invalid command provided", func(t *testing.T) {
+		t.Parallel()
+		// GIVEN: A repository instance and an invalid command
+		repo := repositories.NewStdShellRepository(nil)
+
+		// WHEN: Executing the invalid command
+		_, err := repo.ExecuteCommandWithOutput("nonexistentcommand12345", []string{}, ".", nil)
+
+		// THEN: Should return an error
+		require.Error(t, err)
+	})
+}
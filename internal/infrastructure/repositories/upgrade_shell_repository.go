@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rios0rios0/terra/internal/domain/entities"
 	logger "github.com/sirupsen/logrus"
 )
 
@@ -38,6 +39,11 @@ func getUpgradePatterns() []string {
 		"terraform init -upgrade",
 		"You must run 'terragrunt init --upgrade'",
 		"install it automatically by running",
+		// The same suggestions as worded by OpenTofu.
+		"please run \"tofu init\"",
+		"run \"tofu init\"",
+		"please run 'tofu init'",
+		"tofu init -upgrade",
 
 		// Initialization-required diagnostics (exact Terraform source strings).
 		"Backend initialization required",
@@ -46,6 +52,7 @@ func getUpgradePatterns() []string {
 
 		// Uninitialized working directory.
 		"terraform init has not been run",
+		"tofu init has not been run",
 		"Working directory is not initialized",
 
 		// Backend configuration change detection.
@@ -74,11 +81,13 @@ func getUpgradePatterns() []string {
 // UpgradeAwareShellRepository wraps command execution with automatic upgrade detection.
 // When a command fails and the output matches known upgrade-needed patterns, it
 // automatically runs "init --upgrade" and retries the original command.
-type UpgradeAwareShellRepository struct{}
+type UpgradeAwareShellRepository struct {
+	redactor *entities.Redactor
+}
 
 // NewUpgradeAwareShellRepository creates a new UpgradeAwareShellRepository.
-func NewUpgradeAwareShellRepository() *UpgradeAwareShellRepository {
-	return &UpgradeAwareShellRepository{}
+func NewUpgradeAwareShellRepository(redactor *entities.Redactor) *UpgradeAwareShellRepository {
+	return &UpgradeAwareShellRepository{redactor: redactor}
 }
 
 // ExecuteCommandWithUpgrade runs the command, captures output, and if the command fails
@@ -121,7 +130,8 @@ func (it *UpgradeAwareShellRepository) executeAndCapture(
 	arguments []string,
 	directory string,
 ) (string, error) {
-	logger.Infof("Running [%s %s] in %s", command, strings.Join(arguments, " "), directory)
+	execLog := newCommandLog(it.redactor, command, arguments, directory)
+	execLog.started("")
 
 	start := time.Now()
 	cmd := exec.CommandContext(context.Background(), command, arguments...)
@@ -133,7 +143,7 @@ func (it *UpgradeAwareShellRepository) executeAndCapture(
 	cmd.Stderr = io.MultiWriter(os.Stderr, &outputBuf)
 
 	err := cmd.Run()
-	logCommandDuration(command, arguments, directory, time.Since(start), err)
+	execLog.finished(time.Since(start), err)
 	if err != nil {
 		err = fmt.Errorf("failed to perform command execution: %w", err)
 	}
@@ -147,7 +157,8 @@ func (it *UpgradeAwareShellRepository) executePassthrough(
 	arguments []string,
 	directory string,
 ) error {
-	logger.Infof("Running [%s %s] in %s", command, strings.Join(arguments, " "), directory)
+	execLog := newCommandLog(it.redactor, command, arguments, directory)
+	execLog.started("")
 
 	start := time.Now()
 	cmd := exec.CommandContext(context.Background(), command, arguments...)
@@ -157,7 +168,7 @@ func (it *UpgradeAwareShellRepository) executePassthrough(
 	cmd.Stdin = os.Stdin
 
 	err := cmd.Run()
-	logCommandDuration(command, arguments, directory, time.Since(start), err)
+	execLog.finished(time.Since(start), err)
 	if err != nil {
 		err = fmt.Errorf("failed to perform command execution: %w", err)
 	}
@@ -176,8 +187,10 @@ func (it *UpgradeAwareShellRepository) runInitUpgrade(
 	originalArguments []string,
 	directory string,
 ) error {
+	span := entities.StartSpan(0, entities.SpanInitUpgrade)
 	initArgs := append([]string{"init", "--upgrade"}, extractQueueScopingFlags(originalArguments)...)
-	logger.Infof("Running [%s %s] in %s", command, strings.Join(initArgs, " "), directory)
+	execLog := newCommandLog(it.redactor, command, initArgs, directory)
+	execLog.started("")
 
 	start := time.Now()
 	cmd := exec.CommandContext(context.Background(), command, initArgs...)
@@ -187,7 +200,8 @@ func (it *UpgradeAwareShellRepository) runInitUpgrade(
 	cmd.Stdin = os.Stdin
 
 	err := cmd.Run()
-	logCommandDuration(command, initArgs, directory, time.Since(start), err)
+	execLog.finished(time.Since(start), err)
+	span.End(err)
 	if err != nil {
 		return fmt.Errorf("failed to perform init --upgrade: %w", err)
 	}
@@ -35,7 +35,7 @@ func TestNewUpgradeAwareShellRepository(t *testing.T) {
 	t.Run("should create instance when called", func(t *testing.T) {
 		t.Parallel()
 		// given / when
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 
 		// then
 		require.NotNil(t, repo)
@@ -145,6 +145,14 @@ HH:MM:SS.000 STDERR terraform: │ "terraform init" to install all modules requi
 			"should detect install it automatically by running",
 			"You may be able to install it automatically by running:\n  terraform init",
 		},
+		{
+			"should detect run tofu init suggestion",
+			`Error: Could not load backend configuration. Please run "tofu init"`,
+		},
+		{
+			"should detect tofu init -upgrade suggestion",
+			"Please run tofu init -upgrade to resolve",
+		},
 	}
 
 	for _, tt := range upgradeOutputs {
@@ -267,7 +275,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should succeed when command executes successfully", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// WHEN
@@ -280,7 +288,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should return error when command fails without upgrade pattern", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// WHEN
@@ -294,7 +302,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should return error when command does not exist", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// WHEN
@@ -308,7 +316,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should return error when directory does not exist", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 
 		// WHEN
 		err := repo.ExecuteCommandWithUpgrade("echo", []string{"hello"}, "/nonexistent/directory/path")
@@ -320,7 +328,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should return error when command fails with upgrade pattern but init also fails", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A script that outputs an upgrade pattern and exits with error
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// WHEN: Running a command that outputs an upgrade pattern to stderr and fails
@@ -339,7 +347,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should succeed when command succeeds with output", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// WHEN: Running a command that produces both stdout and stderr but succeeds
@@ -356,7 +364,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 	t.Run("should pass arguments correctly to the command", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// WHEN: Running a command that uses its arguments
@@ -374,7 +382,7 @@ func TestUpgradeAwareShellRepository_ExecuteCommandWithUpgrade(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A script that uses a marker file to fail on first run with an upgrade
 		// pattern, succeed on "init --upgrade", and succeed on retry
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// Create a wrapper script that:
@@ -409,7 +417,7 @@ exit 1
 	t.Run("should return error when retry fails after successful init upgrade", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A script where init succeeds but the retry also fails
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		// Create a wrapper script that:
@@ -440,7 +448,7 @@ exit 1
 		// The init retry must also include --all and --queue-exclude-dir <dir>;
 		// otherwise terragrunt would refuse to run init in a parent directory with no
 		// terragrunt.hcl (the real-world bug).
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		scriptContent := `#!/bin/bash
@@ -477,7 +485,7 @@ exit 1
 	t.Run("should propagate --queue-exclude-dir in --flag=value form to init --upgrade retry", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A wrapper that requires the init retry to carry the equals-form flag verbatim
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		scriptContent := `#!/bin/bash
@@ -514,7 +522,7 @@ exit 1
 	t.Run("should propagate --filter to init --upgrade retry", func(t *testing.T) {
 		t.Parallel()
 		// GIVEN: A wrapper that requires the init retry to carry --filter and its value
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		scriptContent := `#!/bin/bash
@@ -553,7 +561,7 @@ exit 1
 		// GIVEN: A wrapper that asserts the init retry receives ONLY init --upgrade
 		// (no --auto-approve, --non-interactive, or other unrelated flags) when the
 		// original command had no queue-scoping flags.
-		repo := repositories.NewUpgradeAwareShellRepository()
+		repo := repositories.NewUpgradeAwareShellRepository(nil)
 		dir := t.TempDir()
 
 		scriptContent := `#!/bin/bash
@@ -0,0 +1,29 @@
+//go:build integration || unit || test
+
+package commanddoubles //nolint:staticcheck // Test package naming follows established project structure
+
+import (
+	"io"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+)
+
+// StubCacheCommand is a stub implementation of the Cache interface.
+type StubCacheCommand struct {
+	StatsCallCount   int
+	PruneCallCount   int
+	LastPruneOptions commands.CachePruneOptions
+	StatsError       error
+	PruneError       error
+}
+
+func (m *StubCacheCommand) Stats(_ io.Writer) error {
+	m.StatsCallCount++
+	return m.StatsError
+}
+
+func (m *StubCacheCommand) Prune(options commands.CachePruneOptions) error {
+	m.PruneCallCount++
+	m.LastPruneOptions = options
+	return m.PruneError
+}
@@ -2,15 +2,27 @@
 
 package commanddoubles //nolint:staticcheck // Test package naming follows established project structure
 
+import "github.com/rios0rios0/terra/internal/domain/commands"
+
 // StubDeleteCacheCommand is a stub implementation of the DeleteCache interface.
 type StubDeleteCacheCommand struct {
 	ExecuteCallCount int
+	LastTargetPath   string
 	LastToBeDeleted  []string
 	LastGlobal       bool
+	LastOptions      commands.DeleteCacheOptions
+	ExecuteError     error
 }
 
-func (m *StubDeleteCacheCommand) Execute(toBeDeleted []string, global bool) {
+func (m *StubDeleteCacheCommand) Execute(
+	targetPath string,
+	toBeDeleted []string,
+	options commands.DeleteCacheOptions,
+) error {
 	m.ExecuteCallCount++
+	m.LastTargetPath = targetPath
 	m.LastToBeDeleted = toBeDeleted
-	m.LastGlobal = global
+	m.LastGlobal = options.Global
+	m.LastOptions = options
+	return m.ExecuteError
 }
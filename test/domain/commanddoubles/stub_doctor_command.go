@@ -0,0 +1,18 @@
+//go:build integration || unit || test
+
+package commanddoubles //nolint:staticcheck // Test package naming follows established project structure
+
+import "io"
+
+// StubDoctorCommand is a stub implementation of the Doctor interface.
+type StubDoctorCommand struct {
+	ExecuteCallCount int
+	LastTargetPath   string
+	ExecuteError     error
+}
+
+func (m *StubDoctorCommand) Execute(targetPath string, _ io.Writer) error {
+	m.ExecuteCallCount++
+	m.LastTargetPath = targetPath
+	return m.ExecuteError
+}
@@ -8,11 +8,25 @@ import (
 
 // StubFormatFiles is a stub implementation for FormatFiles interface.
 type StubFormatFiles struct {
-	ExecuteCalled    bool
-	LastDependencies []entities.Dependency
+	ExecuteCalled       bool
+	ExecuteTargetCalled bool
+	LastTargetPath      string
+	LastChangedOnly     bool
+	LastDependencies    []entities.Dependency
 }
 
 func (m *StubFormatFiles) Execute(dependencies []entities.Dependency) {
 	m.ExecuteCalled = true
 	m.LastDependencies = dependencies
 }
+
+func (m *StubFormatFiles) ExecuteTarget(targetPath string, changedOnly bool, dependencies []entities.Dependency) {
+	m.ExecuteTargetCalled = true
+	m.LastTargetPath = targetPath
+	m.LastChangedOnly = changedOnly
+	m.LastDependencies = dependencies
+}
+
+func (m *StubFormatFiles) Check(_ string, _ []entities.Dependency) error {
+	return nil
+}
@@ -10,9 +10,28 @@ import (
 type StubFormatFilesCommand struct {
 	ExecuteCallCount int
 	LastDependencies []entities.Dependency
+	CheckCallCount   int
+	LastTargetPath   string
+	CheckError       error
 }
 
 func (m *StubFormatFilesCommand) Execute(dependencies []entities.Dependency) {
 	m.ExecuteCallCount++
 	m.LastDependencies = dependencies
 }
+
+func (m *StubFormatFilesCommand) ExecuteTarget(
+	targetPath string,
+	_ bool,
+	dependencies []entities.Dependency,
+) {
+	m.LastTargetPath = targetPath
+	m.LastDependencies = dependencies
+}
+
+func (m *StubFormatFilesCommand) Check(targetPath string, dependencies []entities.Dependency) error {
+	m.CheckCallCount++
+	m.LastTargetPath = targetPath
+	m.LastDependencies = dependencies
+	return m.CheckError
+}
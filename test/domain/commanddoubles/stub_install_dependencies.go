@@ -4,15 +4,39 @@ package commanddoubles //nolint:staticcheck // Test package naming follows estab
 
 import (
 	"github.com/rios0rios0/terra/internal/domain/entities"
+	logger "github.com/sirupsen/logrus"
 )
 
 // StubInstallDependencies is a stub implementation for InstallDependencies interface.
 type StubInstallDependencies struct {
 	ExecuteCalled    bool
 	LastDependencies []entities.Dependency
+	// InstalledVersions records every "cli@version" passed to InstallVersion.
+	InstalledVersions []string
+	// BinaryPaths maps "cli@version" to the path InstallVersion returns.
+	BinaryPaths map[string]string
+	// InstallFails makes InstallVersion exit through logger.Fatalf, like a failed download.
+	InstallFails bool
 }
 
 func (m *StubInstallDependencies) Execute(dependencies []entities.Dependency) {
 	m.ExecuteCalled = true
 	m.LastDependencies = dependencies
 }
+
+func (m *StubInstallDependencies) InstallVersion(dependency entities.Dependency, version string) string {
+	key := dependency.CLI + "@" + version
+	m.InstalledVersions = append(m.InstalledVersions, key)
+	if m.InstallFails {
+		logger.Fatalf("Failed to download %s", key)
+	}
+	return m.BinaryPaths[key]
+}
+
+func (m *StubInstallDependencies) LatestVersion(_ entities.Dependency) (string, error) {
+	return "", nil
+}
+
+func (m *StubInstallDependencies) Rollback(_ string, _ []entities.Dependency) error {
+	return nil
+}
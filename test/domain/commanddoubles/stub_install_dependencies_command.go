@@ -10,9 +10,28 @@ import (
 type StubInstallDependenciesCommand struct {
 	ExecuteCallCount int
 	LastDependencies []entities.Dependency
+	// LatestVersions maps a dependency CLI to the version LatestVersion returns.
+	LatestVersions map[string]string
+	LatestError    error
+	// RolledBackTools records every tool passed to Rollback.
+	RolledBackTools []string
+	RollbackError   error
 }
 
 func (m *StubInstallDependenciesCommand) Execute(dependencies []entities.Dependency) {
 	m.ExecuteCallCount++
 	m.LastDependencies = dependencies
 }
+
+func (m *StubInstallDependenciesCommand) InstallVersion(_ entities.Dependency, _ string) string {
+	return ""
+}
+
+func (m *StubInstallDependenciesCommand) LatestVersion(dependency entities.Dependency) (string, error) {
+	return m.LatestVersions[dependency.CLI], m.LatestError
+}
+
+func (m *StubInstallDependenciesCommand) Rollback(tool string, _ []entities.Dependency) error {
+	m.RolledBackTools = append(m.RolledBackTools, tool)
+	return m.RollbackError
+}
@@ -9,6 +9,8 @@ type StubSelfUpdateCommand struct {
 	DryRunFlag    bool
 	ForceFlag     bool
 	CallCount     int
+	RollbackError error
+	RollbackCount int
 }
 
 // NewStubSelfUpdateCommand creates a new stub self-update command.
@@ -25,6 +27,12 @@ func (s *StubSelfUpdateCommand) Execute(dryRun, force bool) error {
 	return s.ExecuteError
 }
 
+// Rollback implements the SelfUpdate interface.
+func (s *StubSelfUpdateCommand) Rollback() error {
+	s.RollbackCount++
+	return s.RollbackError
+}
+
 // WasCalled returns true if Execute was called.
 func (s *StubSelfUpdateCommand) WasCalled() bool {
 	return s.ExecuteCalled
@@ -37,4 +45,6 @@ func (s *StubSelfUpdateCommand) Reset() {
 	s.ForceFlag = false
 	s.CallCount = 0
 	s.ExecuteError = nil
+	s.RollbackError = nil
+	s.RollbackCount = 0
 }
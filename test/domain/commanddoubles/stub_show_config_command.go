@@ -0,0 +1,18 @@
+//go:build integration || unit || test
+
+package commanddoubles //nolint:staticcheck // Test package naming follows established project structure
+
+import "io"
+
+// StubShowConfigCommand is a stub implementation of the ShowConfig interface.
+type StubShowConfigCommand struct {
+	ExecuteCallCount int
+	LastTargetPath   string
+	ExecuteError     error
+}
+
+func (m *StubShowConfigCommand) Execute(targetPath string, _ io.Writer) error {
+	m.ExecuteCallCount++
+	m.LastTargetPath = targetPath
+	return m.ExecuteError
+}
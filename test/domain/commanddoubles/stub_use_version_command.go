@@ -0,0 +1,22 @@
+//go:build integration || unit || test
+
+package commanddoubles //nolint:staticcheck // Test package naming follows established project structure
+
+import "github.com/rios0rios0/terra/internal/domain/entities"
+
+// StubUseVersionCommand is a stub implementation of the UseVersion interface.
+type StubUseVersionCommand struct {
+	ExecuteCallCount int
+	LastTargetPath   string
+	LastTool         string
+	LastVersion      string
+	ExecuteError     error
+}
+
+func (m *StubUseVersionCommand) Execute(targetPath, tool, version string, _ []entities.Dependency) error {
+	m.ExecuteCallCount++
+	m.LastTargetPath = targetPath
+	m.LastTool = tool
+	m.LastVersion = version
+	return m.ExecuteError
+}
@@ -2,11 +2,21 @@
 
 package commanddoubles //nolint:staticcheck // Test package naming follows established project structure
 
+import (
+	"io"
+
+	"github.com/rios0rios0/terra/internal/domain/commands"
+)
+
 // StubVersionCommand is a stub implementation of the Version interface.
 type StubVersionCommand struct {
 	ExecuteCallCount int
+	LastOptions      commands.VersionOptions
+	ExecuteError     error
 }
 
-func (m *StubVersionCommand) Execute() {
+func (m *StubVersionCommand) Execute(options commands.VersionOptions, _ io.Writer) error {
 	m.ExecuteCallCount++
+	m.LastOptions = options
+	return m.ExecuteError
 }
@@ -16,6 +16,13 @@ type DependencyBuilder struct {
 	versionURL        string
 	regexVersion      string
 	formattingCommand []string
+	checkCommand      []string
+	fileCommand       []string
+	fileExtensions    []string
+	engine            string
+	checksumsURL      string
+	signatureURL      string
+	signingKey        string
 }
 
 // NewDependencyBuilder creates a new dependency builder.
@@ -53,6 +60,26 @@ func (b *DependencyBuilder) WithVersionURL(url string) *DependencyBuilder {
 	return b
 }
 
+// WithEngine marks the dependency as the engine selected by the given TERRA_ENGINE value.
+func (b *DependencyBuilder) WithEngine(engine string) *DependencyBuilder {
+	b.engine = engine
+	return b
+}
+
+// WithChecksumsURL sets the URL of the release's SHA256SUMS file.
+func (b *DependencyBuilder) WithChecksumsURL(url string) *DependencyBuilder {
+	b.checksumsURL = url
+	return b
+}
+
+// WithSignature sets the URL of the checksums' detached signature and the armored key
+// verifying it.
+func (b *DependencyBuilder) WithSignature(url, armoredKey string) *DependencyBuilder {
+	b.signatureURL = url
+	b.signingKey = armoredKey
+	return b
+}
+
 // WithRegexVersion sets the regex version pattern.
 func (b *DependencyBuilder) WithRegexVersion(regex string) *DependencyBuilder {
 	b.regexVersion = regex
@@ -67,6 +94,20 @@ func (b *DependencyBuilder) WithFormattingCommand(cmd []string) *DependencyBuild
 	return b
 }
 
+// WithFormattingCheckCommand sets the formatting check command.
+func (b *DependencyBuilder) WithFormattingCheckCommand(cmd []string) *DependencyBuilder {
+	b.checkCommand = append([]string(nil), cmd...)
+	return b
+}
+
+// WithFormattingFileCommand sets the command formatting a single file and the extensions
+// of the files it handles.
+func (b *DependencyBuilder) WithFormattingFileCommand(cmd []string, extensions ...string) *DependencyBuilder {
+	b.fileCommand = append([]string(nil), cmd...)
+	b.fileExtensions = append([]string(nil), extensions...)
+	return b
+}
+
 // WithTerraformPattern sets up Terraform-like patterns.
 func (b *DependencyBuilder) WithTerraformPattern() *DependencyBuilder {
 	return b.WithRegexVersion(`"current_version":"([^"]+)"`)
@@ -85,12 +126,19 @@ func (b *DependencyBuilder) Build() interface{} {
 // BuildDependency creates the dependency with a concrete return type for convenience.
 func (b *DependencyBuilder) BuildDependency() entities.Dependency {
 	return entities.Dependency{
-		Name:              b.name,
-		CLI:               b.cli,
-		BinaryURL:         b.binaryURL,
-		VersionURL:        b.versionURL,
-		RegexVersion:      b.regexVersion,
-		FormattingCommand: b.formattingCommand,
+		Name:                     b.name,
+		CLI:                      b.cli,
+		BinaryURL:                b.binaryURL,
+		VersionURL:               b.versionURL,
+		RegexVersion:             b.regexVersion,
+		FormattingCommand:        b.formattingCommand,
+		FormattingCheckCommand:   b.checkCommand,
+		FormattingFileCommand:    b.fileCommand,
+		FormattingFileExtensions: b.fileExtensions,
+		Engine:                   b.engine,
+		ChecksumsURL:             b.checksumsURL,
+		SignatureURL:             b.signatureURL,
+		SigningKey:               b.signingKey,
 	}
 }
 
@@ -103,6 +151,13 @@ func (b *DependencyBuilder) Reset() testkit.Builder {
 	b.versionURL = ""
 	b.regexVersion = `"version":"([^"]+)"`
 	b.formattingCommand = []string{"format"}
+	b.checkCommand = nil
+	b.fileCommand = nil
+	b.fileExtensions = nil
+	b.engine = ""
+	b.checksumsURL = ""
+	b.signatureURL = ""
+	b.signingKey = ""
 	return b
 }
 
@@ -118,5 +173,12 @@ func (b *DependencyBuilder) Clone() testkit.Builder {
 		versionURL:        b.versionURL,
 		regexVersion:      b.regexVersion,
 		formattingCommand: fmtCmd,
+		checkCommand:      append([]string(nil), b.checkCommand...),
+		fileCommand:       append([]string(nil), b.fileCommand...),
+		fileExtensions:    append([]string(nil), b.fileExtensions...),
+		engine:            b.engine,
+		checksumsURL:      b.checksumsURL,
+		signatureURL:      b.signatureURL,
+		signingKey:        b.signingKey,
 	}
 }
@@ -20,6 +20,8 @@ type SettingsBuilder struct {
 	terraNoProviderCache     bool
 	terraNoPartialParseCache bool
 	terraNoWorkspace         bool
+	terraNoFormat            bool
+	terraEngine              string
 }
 
 // NewSettingsBuilder creates a new Settings builder with empty defaults.
@@ -89,6 +91,18 @@ func (b *SettingsBuilder) WithTerraNoWorkspace(noWorkspace bool) *SettingsBuilde
 	return b
 }
 
+// WithTerraNoFormat sets the no-format flag.
+func (b *SettingsBuilder) WithTerraNoFormat(noFormat bool) *SettingsBuilder {
+	b.terraNoFormat = noFormat
+	return b
+}
+
+// WithTerraEngine sets the engine running the modules.
+func (b *SettingsBuilder) WithTerraEngine(engine string) *SettingsBuilder {
+	b.terraEngine = engine
+	return b
+}
+
 // Build creates the Settings (satisfies testkit.Builder interface).
 func (b *SettingsBuilder) Build() interface{} {
 	return b.BuildSettings()
@@ -107,6 +121,8 @@ func (b *SettingsBuilder) BuildSettings() *entities.Settings {
 		TerraNoProviderCache:     b.terraNoProviderCache,
 		TerraNoPartialParseCache: b.terraNoPartialParseCache,
 		TerraNoWorkspace:         b.terraNoWorkspace,
+		TerraNoFormat:            b.terraNoFormat,
+		TerraEngine:              b.terraEngine,
 	}
 }
 
@@ -123,6 +139,8 @@ func (b *SettingsBuilder) Reset() testkit.Builder {
 	b.terraNoProviderCache = false
 	b.terraNoPartialParseCache = false
 	b.terraNoWorkspace = false
+	b.terraNoFormat = false
+	b.terraEngine = ""
 	return b
 }
 
@@ -140,5 +158,7 @@ func (b *SettingsBuilder) Clone() testkit.Builder {
 		terraNoProviderCache:     b.terraNoProviderCache,
 		terraNoPartialParseCache: b.terraNoPartialParseCache,
 		terraNoWorkspace:         b.terraNoWorkspace,
+		terraNoFormat:            b.terraNoFormat,
+		terraEngine:              b.terraEngine,
 	}
 }
@@ -7,6 +7,17 @@ type StubCLI struct {
 	Name                  string
 	CanChangeAccountValue bool
 	CommandChangeAccount  []string
+
+	CanLoginValue      bool
+	IsLoginGlobalValue bool
+	CommandLogin       []string
+	CommandLoginError  error
+
+	CommandAccountEnvironment []string
+	AccountEnvironment        []string
+	AccountEnvironmentError   error
+
+	CommandIdentity []string
 }
 
 func (m *StubCLI) GetName() string {
@@ -20,3 +31,27 @@ func (m *StubCLI) CanChangeAccount() bool {
 func (m *StubCLI) GetCommandChangeAccount() []string {
 	return m.CommandChangeAccount
 }
+
+func (m *StubCLI) CanLogin() bool {
+	return m.CanLoginValue
+}
+
+func (m *StubCLI) IsLoginGlobal() bool {
+	return m.IsLoginGlobalValue
+}
+
+func (m *StubCLI) GetCommandLogin() ([]string, error) {
+	return m.CommandLogin, m.CommandLoginError
+}
+
+func (m *StubCLI) GetCommandAccountEnvironment() []string {
+	return m.CommandAccountEnvironment
+}
+
+func (m *StubCLI) GetAccountEnvironment(_ string) ([]string, error) {
+	return m.AccountEnvironment, m.AccountEnvironmentError
+}
+
+func (m *StubCLI) GetCommandIdentity() []string {
+	return m.CommandIdentity
+}
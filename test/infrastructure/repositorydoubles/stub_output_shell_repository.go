@@ -0,0 +1,53 @@
+//go:build integration || unit || test
+
+package repositorydoubles
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rios0rios0/terra/internal/domain/repositories"
+)
+
+// OutputCallRecord represents a single captured-output command execution.
+type OutputCallRecord struct {
+	Command     string
+	Arguments   []string
+	Directory   string
+	Environment []string
+}
+
+// StubOutputShellRepository is a test double that returns a canned output for every command.
+type StubOutputShellRepository struct {
+	Output      string
+	ShouldFail  bool
+	CallHistory []OutputCallRecord
+
+	mu sync.Mutex
+}
+
+// Verify it implements the interface
+var _ repositories.OutputShellRepository = (*StubOutputShellRepository)(nil)
+
+func (stub *StubOutputShellRepository) ExecuteCommandWithOutput(
+	command string,
+	arguments []string,
+	directory string,
+	environment []string,
+) (string, error) {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	stub.CallHistory = append(stub.CallHistory, OutputCallRecord{
+		Command:     command,
+		Arguments:   append([]string{}, arguments...),
+		Directory:   directory,
+		Environment: environment,
+	})
+
+	if stub.ShouldFail {
+		return "", errors.New("simulated output command failure")
+	}
+
+	return stub.Output, nil
+}
@@ -28,3 +28,12 @@ func (m *StubShellRepository) ExecuteCommand(
 	}
 	return nil
 }
+
+func (m *StubShellRepository) ExecuteCommandWithEnvironment(
+	command string,
+	arguments []string,
+	directory string,
+	_ []string,
+) error {
+	return m.ExecuteCommand(command, arguments, directory)
+}
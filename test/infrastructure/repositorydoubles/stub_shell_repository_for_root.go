@@ -10,9 +10,10 @@ type StubShellRepositoryForRoot struct {
 	LastDirectory    string
 	ExecuteErrors    []error
 	CallHistory      []struct {
-		Command   string
-		Arguments []string
-		Directory string
+		Command     string
+		Arguments   []string
+		Directory   string
+		Environment []string
 	}
 }
 
@@ -20,15 +21,26 @@ func (m *StubShellRepositoryForRoot) ExecuteCommand(
 	command string,
 	arguments []string,
 	directory string,
+) error {
+	return m.ExecuteCommandWithEnvironment(command, arguments, directory, nil)
+}
+
+func (m *StubShellRepositoryForRoot) ExecuteCommandWithEnvironment(
+	command string,
+	arguments []string,
+	directory string,
+	environment []string,
 ) error {
 	m.CallHistory = append(m.CallHistory, struct {
-		Command   string
-		Arguments []string
-		Directory string
+		Command     string
+		Arguments   []string
+		Directory   string
+		Environment []string
 	}{
-		Command:   command,
-		Arguments: arguments,
-		Directory: directory,
+		Command:     command,
+		Arguments:   arguments,
+		Directory:   directory,
+		Environment: environment,
 	})
 
 	m.ExecuteCallCount++
@@ -2,14 +2,20 @@
 
 package repositorydoubles
 
-import "github.com/rios0rios0/terra/internal/domain/repositories"
+import (
+	"sync"
+
+	"github.com/rios0rios0/terra/internal/domain/repositories"
+)
 
 // ParallelStateCallRecord represents a single command execution call for parallel state testing
 type ParallelStateCallRecord struct {
-	Command   string
-	Arguments []string
-	Directory string
-	Prefix    string
+	Command     string
+	Arguments   []string
+	Directory   string
+	Prefix      string
+	Worker      int
+	Environment []string
 }
 
 // StubShellRepositoryForParallelState is a test double for shell repository focused on parallel state testing
@@ -18,6 +24,8 @@ type StubShellRepositoryForParallelState struct {
 	CallHistory      []ParallelStateCallRecord
 	ShouldFail       bool
 	FailureMessage   string
+
+	mu sync.Mutex
 }
 
 // Verify it implements the interface
@@ -28,13 +36,20 @@ func (stub *StubShellRepositoryForParallelState) ExecuteCommandWithPrefix(
 	arguments []string,
 	directory string,
 	prefix string,
+	worker int,
+	environment []string,
 ) error {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
 	stub.ExecuteCallCount++
 	stub.CallHistory = append(stub.CallHistory, ParallelStateCallRecord{
-		Command:   command,
-		Arguments: make([]string, len(arguments)),
-		Directory: directory,
-		Prefix:    prefix,
+		Command:     command,
+		Arguments:   make([]string, len(arguments)),
+		Directory:   directory,
+		Prefix:      prefix,
+		Worker:      worker,
+		Environment: environment,
 	})
 
 	// Copy arguments to avoid modification issues
@@ -33,3 +33,12 @@ func (m *StubShellRepositoryWithRecording) ExecuteCommand(
 	}
 	return nil
 }
+
+func (m *StubShellRepositoryWithRecording) ExecuteCommandWithEnvironment(
+	command string,
+	arguments []string,
+	directory string,
+	_ []string,
+) error {
+	return m.ExecuteCommand(command, arguments, directory)
+}
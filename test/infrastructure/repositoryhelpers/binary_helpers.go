@@ -40,3 +40,13 @@ fi
 
 	return tempDir
 }
+
+// HelperCreateMockBinary writes an executable shell script named name into directory,
+// running body for any arguments.
+func HelperCreateMockBinary(t *testing.T, directory, name, body string) {
+	t.Helper()
+
+	//nolint:gosec // Test helper needs executable permissions
+	err := os.WriteFile(filepath.Join(directory, name), []byte("#!/bin/sh\n"+body+"\n"), 0755)
+	require.NoError(t, err)
+}
@@ -0,0 +1,28 @@
+//go:build integration || unit || test
+
+package repositoryhelpers //nolint:staticcheck // Test package naming follows established project structure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// HelperCreateCacheEntry creates the cache entry relative to root, holding one file of
+// size bytes, and dates the entry and everything below it to lastUsed. It returns the
+// entry's path.
+func HelperCreateCacheEntry(t *testing.T, root, relative string, size int, lastUsed time.Time) string {
+	t.Helper()
+
+	entryPath := filepath.Join(root, filepath.FromSlash(relative))
+	require.NoError(t, os.MkdirAll(entryPath, 0o755))
+	filePath := filepath.Join(entryPath, "content")
+	require.NoError(t, os.WriteFile(filePath, []byte(strings.Repeat("x", size)), 0o600))
+	require.NoError(t, os.Chtimes(filePath, lastUsed, lastUsed))
+	require.NoError(t, os.Chtimes(entryPath, lastUsed, lastUsed))
+	return entryPath
+}
@@ -0,0 +1,83 @@
+//go:build integration || unit || test
+
+package repositoryhelpers //nolint:staticcheck // Test package naming follows established project structure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/require"
+)
+
+// HelperCreateSigningKey generates an OpenPGP key and returns its armored public key and
+// a function producing binary detached signatures with it.
+func HelperCreateSigningKey(t *testing.T) (string, func(data []byte) []byte) {
+	t.Helper()
+	return HelperCreateSigningKeyAt(t, time.Now(), 0)
+}
+
+// HelperCreateSigningKeyAt generates an OpenPGP key created at the given time, which
+// expires after lifetime (never when 0), and returns its armored public key and a function
+// producing binary detached signatures with it, made at that same time.
+func HelperCreateSigningKeyAt(
+	t *testing.T,
+	created time.Time,
+	lifetime time.Duration,
+) (string, func(data []byte) []byte) {
+	t.Helper()
+
+	config := &packet.Config{
+		Time:            func() time.Time { return created },
+		KeyLifetimeSecs: uint32(lifetime.Seconds()),
+	}
+	entity, err := openpgp.NewEntity("terra test", "", "test@example.com", config)
+	require.NoError(t, err)
+
+	var public bytes.Buffer
+	writer, err := armor.Encode(&public, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(writer))
+	require.NoError(t, writer.Close())
+
+	sign := func(data []byte) []byte {
+		var signature bytes.Buffer
+		require.NoError(t, openpgp.DetachSign(&signature, entity, bytes.NewReader(data), config))
+		return signature.Bytes()
+	}
+	return public.String(), sign
+}
+
+// HelperSHA256SUMS returns a SHA256SUMS file listing the digest of each file's content.
+func HelperSHA256SUMS(files map[string][]byte) []byte {
+	var sums bytes.Buffer
+	for name, content := range files {
+		digest := sha256.Sum256(content)
+		sums.WriteString(hex.EncodeToString(digest[:]) + "  " + name + "\n")
+	}
+	return sums.Bytes()
+}
+
+// HelperCreateReleaseServer creates a server answering each path with its content and
+// every other path with 404.
+func HelperCreateReleaseServer(t *testing.T, files map[string][]byte) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, found := files[r.URL.Path]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}